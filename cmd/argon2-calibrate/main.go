@@ -0,0 +1,107 @@
+// argon2-calibrate benchmarks argon2id on this machine and recommends the
+// PASSWORD_ARGON2_* values that meet a target latency within a memory budget.
+// Run it on the same instance type (and container limits) as production.
+//
+//	go run ./cmd/argon2-calibrate                              # 500ms, budget = half the memory limit
+//	go run ./cmd/argon2-calibrate -target 250ms -budget 2048   # 2 GiB for hashing in total
+//	go run ./cmd/argon2-calibrate -concurrency 32              # more logins hashing at once
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/dhanarrizky/Golang-template/internal/config"
+	"github.com/dhanarrizky/Golang-template/internal/infrastructure/security"
+)
+
+// defaultBudget is used when no memory limit is configured or detected
+const defaultBudget = 1024 // MiB
+
+func main() {
+	target := flag.Duration("target", 500*time.Millisecond, "acceptable latency of a single hash")
+	budgetMB := flag.Int("budget", 0, "memory for hashing in total, MiB; 0 = half the memory limit")
+	concurrency := flag.Int("concurrency", 0, "hashes running at once; 0 = PASSWORD_HASH_EXPECTED_CONCURRENCY")
+	parallelism := flag.Uint("parallelism", 1, "argon2id lanes per hash")
+	samples := flag.Int("samples", 3, "runs per measured configuration")
+	flag.Parse()
+
+	_ = godotenv.Load()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("load config: %v", err)
+	}
+
+	if *concurrency <= 0 {
+		*concurrency = max(cfg.Password.ExpectedConcurrency, 1)
+	}
+	if *parallelism < 1 || *parallelism > 255 {
+		log.Fatalf("parallelism must be between 1 and 255")
+	}
+
+	memoryLimit := uint64(cfg.Password.MemoryLimitMB) << 20
+	if memoryLimit == 0 {
+		memoryLimit = security.ContainerMemoryLimit()
+	}
+
+	budget := uint64(*budgetMB) << 20
+	switch {
+	case budget > 0:
+	case memoryLimit > 0:
+		budget = memoryLimit / 2 // the rest is left for the application itself
+	default:
+		budget = defaultBudget << 20
+	}
+
+	fmt.Printf("target %s, budget %d MiB for %d concurrent hashes", *target, budget>>20, *concurrency)
+	if memoryLimit > 0 {
+		fmt.Printf(", memory limit %d MiB", memoryLimit>>20)
+	}
+	fmt.Println()
+
+	current := security.Argon2Params{
+		Memory:      cfg.Password.Memory,
+		Iterations:  cfg.Password.Iterations,
+		Parallelism: cfg.Password.Parallelism,
+	}
+	if current.Memory > 0 && current.Iterations > 0 && current.Parallelism > 0 {
+		d := security.BenchmarkArgon2(current, *samples)
+		fmt.Printf("\ncurrent  %s: %s per hash\n", current, d.Round(time.Millisecond))
+	}
+	for _, warning := range security.CheckArgon2Params(current, memoryLimit, *concurrency) {
+		fmt.Printf("  warning: %s\n", warning)
+	}
+
+	start := time.Now()
+	result, err := security.CalibrateArgon2(security.Argon2CalibrationOptions{
+		Target:       *target,
+		MemoryBudget: budget,
+		Concurrency:  *concurrency,
+		Parallelism:  uint8(*parallelism),
+		Samples:      *samples,
+	})
+	if err != nil {
+		log.Fatalf("calibrate: %v", err)
+	}
+
+	fmt.Printf("\nrecommended %s: %s per hash, %d MiB peak (calibrated in %s)\n",
+		result.Params,
+		result.Duration.Round(time.Millisecond),
+		result.PeakMemory>>20,
+		time.Since(start).Round(time.Second),
+	)
+	for _, warning := range result.Warnings {
+		fmt.Printf("  warning: %s\n", warning)
+	}
+
+	fmt.Printf("\nPASSWORD_ARGON2_MEMORY=%d\n", result.Params.Memory)
+	fmt.Printf("PASSWORD_ARGON2_ITERATIONS=%d\n", result.Params.Iterations)
+	fmt.Printf("PASSWORD_ARGON2_PARALLELISM=%d\n", result.Params.Parallelism)
+	fmt.Printf("PASSWORD_HASH_EXPECTED_CONCURRENCY=%d\n", *concurrency)
+	fmt.Println("\nexisting hashes keep their own parameters and are still verified.")
+}
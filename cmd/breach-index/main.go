@@ -0,0 +1,71 @@
+// breach-index builds the prefix offset index for an offline Pwned Passwords
+// dataset ordered by hash, so the API does not scan the whole file at startup.
+//
+//	go run ./cmd/breach-index                          # PASSWORD_BREACH_DATASET from .env
+//	go run ./cmd/breach-index -dataset pwned-sha1.txt  # explicit dataset
+//	go run ./cmd/breach-index -check 'P@ssw0rd'        # look a password up afterwards
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/dhanarrizky/Golang-template/internal/config"
+	"github.com/dhanarrizky/Golang-template/internal/infrastructure/breach"
+)
+
+func main() {
+	datasetPath := flag.String("dataset", "", "dataset file ordered by hash; empty = PASSWORD_BREACH_DATASET")
+	check := flag.String("check", "", "password to look up after indexing")
+	flag.Parse()
+
+	_ = godotenv.Load()
+
+	path := *datasetPath
+	if path == "" {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			log.Fatalf("load config: %v", err)
+		}
+		path = cfg.PasswordBreachDataset
+	}
+	if path == "" {
+		log.Fatal("no dataset: pass -dataset or set PASSWORD_BREACH_DATASET")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		log.Fatalf("dataset: %v", err)
+	}
+
+	if info.IsDir() {
+		fmt.Printf("%s is a range file directory, no index needed\n", path)
+	} else {
+		start := time.Now()
+		if err := breach.BuildIndex(path); err != nil {
+			log.Fatalf("build index: %v", err)
+		}
+		fmt.Printf("indexed %s (%d MiB) in %s\n", path, info.Size()>>20, time.Since(start).Round(time.Millisecond))
+	}
+
+	if *check == "" {
+		return
+	}
+
+	checker, err := breach.NewOfflineChecker(path)
+	if err != nil {
+		log.Fatalf("open dataset: %v", err)
+	}
+
+	count, err := checker.Count(context.Background(), *check)
+	if err != nil {
+		log.Fatalf("check: %v", err)
+	}
+	fmt.Printf("found %d times in breaches\n", count)
+}
@@ -0,0 +1,119 @@
+// dkim-check signs a sample message with the DKIM_* settings and verifies it
+// against a public key, so a key / DNS record mismatch shows up before real
+// mail lands in spam.
+//
+//	go run ./cmd/dkim-check                      # verify with the key derived from DKIM_PRIVATE_KEY
+//	go run ./cmd/dkim-check -pubkey record.txt   # verify with the published TXT record (or a PEM public key)
+//	go run ./cmd/dkim-check -out sample.eml      # also write the signed sample
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/mail"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+
+	"github.com/dhanarrizky/Golang-template/internal/config"
+	mailer "github.com/dhanarrizky/Golang-template/pkg/email"
+)
+
+func main() {
+	pubKeyPath := flag.String("pubkey", "", "PEM public key or DNS TXT record value; empty = derived from the private key")
+	outPath := flag.String("out", "", "write the signed sample message to this file")
+	flag.Parse()
+
+	_ = godotenv.Load()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("load config: %v", err)
+	}
+
+	// built from config + pkg/email only: this command must not depend on
+	// the application wiring (bootstrap) to run
+	signer, err := mailer.NewDKIMSignerFromSettings(mailer.DKIMSettings{
+		Domain:           cfg.DKIMDomain,
+		Selector:         cfg.DKIMSelector,
+		PrivateKey:       cfg.DKIMPrivateKey,
+		PrivateKeyPath:   cfg.DKIMPrivateKeyPath,
+		Canonicalization: cfg.DKIMCanonical,
+		Headers:          cfg.DKIMHeaders,
+	})
+	if err != nil {
+		log.Fatalf("invalid DKIM config: %v", err)
+	}
+
+	if cfg.EmailTransport == "http" {
+		fmt.Printf("WARN: EMAIL_TRANSPORT=http sends fields, not MIME; this signature is not applied, configure DKIM at the provider\n\n")
+	}
+
+	name, record, err := signer.DNSRecord()
+	if err != nil {
+		log.Fatalf("dns record: %v", err)
+	}
+	fmt.Printf("DNS TXT %s\n  %s\n\n", name, record)
+
+	from := cfg.SMTPFrom
+	if from == "" {
+		from = "no-reply@" + cfg.DKIMDomain
+	}
+	checkAlignment(from, cfg.DKIMDomain)
+
+	raw, err := mailer.BuildMIME(from, []string{"dkim-check@example.com"}, &mailer.Message{
+		Subject: "DKIM self-check",
+		Text:    "DKIM self-check from " + cfg.AppName + "\n",
+		HTML:    "<p>DKIM self-check from " + cfg.AppName + "</p>",
+	})
+	if err != nil {
+		log.Fatalf("build message: %v", err)
+	}
+
+	signed, err := signer.Sign(raw)
+	if err != nil {
+		log.Fatalf("sign: %v", err)
+	}
+
+	if *outPath != "" {
+		if err := os.WriteFile(*outPath, signed, 0o644); err != nil {
+			log.Fatalf("write %s: %v", *outPath, err)
+		}
+	}
+
+	pub := signer.PublicKey()
+	if *pubKeyPath != "" {
+		data, err := os.ReadFile(*pubKeyPath)
+		if err != nil {
+			log.Fatalf("read public key: %v", err)
+		}
+		if pub, err = mailer.ParseDKIMPublicKey(data); err != nil {
+			log.Fatalf("parse public key: %v", err)
+		}
+	}
+
+	if err := mailer.VerifyDKIM(signed, pub); err != nil {
+		fmt.Printf("FAIL: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("OK: signature by %s (selector %s) verifies\n", cfg.DKIMDomain, cfg.DKIMSelector)
+}
+
+// checkAlignment warns when the From domain is not d= or a subdomain of it;
+// DMARC then treats the signature as unaligned
+func checkAlignment(from, domain string) {
+	addr, err := mail.ParseAddress(from)
+	if err != nil {
+		fmt.Printf("WARN: cannot parse SMTP_FROM %q: %v\n\n", from, err)
+		return
+	}
+
+	fromDomain := strings.ToLower(addr.Address[strings.LastIndex(addr.Address, "@")+1:])
+	domain = strings.ToLower(domain)
+	if fromDomain != domain && !strings.HasSuffix(fromDomain, "."+domain) {
+		fmt.Printf("WARN: From domain %s is not aligned with DKIM domain %s (DMARC)\n\n", fromDomain, domain)
+	}
+}
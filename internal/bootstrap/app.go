@@ -6,10 +6,16 @@ import (
 
 	"github.com/dhanarrizky/Golang-template/internal/config"
 	"github.com/dhanarrizky/Golang-template/internal/delivery/http"
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	"github.com/dhanarrizky/Golang-template/internal/infrastructure/database"
 	authRepo "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/postgres/auth"
 	"github.com/dhanarrizky/Golang-template/internal/infrastructure/security"
 	authUC "github.com/dhanarrizky/Golang-template/internal/usecase/auth"
+	emailUC "github.com/dhanarrizky/Golang-template/internal/usecase/email"
+	passwordUsecase "github.com/dhanarrizky/Golang-template/internal/usecase/password"
+	quotaUC "github.com/dhanarrizky/Golang-template/internal/usecase/quota"
 	roleUC "github.com/dhanarrizky/Golang-template/internal/usecase/roles"
+	userUsecase "github.com/dhanarrizky/Golang-template/internal/usecase/user"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
@@ -19,19 +25,19 @@ func InitHTTPApp(cfg *config.Config) *gin.Engine {
 	// Infrastructure
 	// =====================
 	db := InitDatabase(cfg)
-	// redis := InitRedis(cfg)
-	// codecHaser := InitPublicIdCodec(cfg)
+	redisClient := InitRedis(cfg)
+	idCodec := InitPublicIdCodec(cfg)
+	txManager := database.NewTransactionManager(db)
 	// tokenVrifier := InitTokenVerifier(cfg)
 	// tokenGenerator := InitTokenGenerator(cfg)
-	passwordHasher := security.NewPasswordHasher(&security.PasswordConfig{
-		Memory:               cfg.Password.Memory,
-		Iterations:           cfg.Password.Iterations,
-		Parallelism:          cfg.Password.Parallelism,
-		SaltLength:           cfg.Password.SaltLength,
-		KeyLength:            cfg.Password.KeyLength,
-		Peppers:              cfg.Password.Peppers,
-		CurrentPepperVersion: cfg.Password.CurrentPepperVersion,
-	})
+	passwordHasher, passwordHashingMonitor := InitPasswordHasher(cfg)
+	emailTransport, mailCatcher := InitMailCatcher(cfg, InitDKIMTransport(cfg, InitEmailTransport(cfg)))
+	emailSender := InitEmailSender(cfg, emailTransport)
+	geoResolver := InitGeoIPResolver(cfg)
+	ipReputation := InitIPReputationChecker(cfg)
+	challengeVerifier := InitChallengeVerifier(cfg)
+	rateLimiter := InitRateLimiter(cfg, redisClient)
+	quotaCounter := InitQuotaCounter(cfg, redisClient)
 	// jwtSigner := authinfra.NewJWTSigner(cfg)
 	accessExp, err := time.ParseDuration(cfg.JWTExpiresIn)
 	if err != nil {
@@ -50,14 +56,34 @@ func InitHTTPApp(cfg *config.Config) *gin.Engine {
 		refreshExp,
 	)
 
+	elevatedTTL, err := time.ParseDuration(cfg.ElevatedTokenTTL)
+	if err != nil {
+		log.Fatalf("invalid ELEVATED_TOKEN_TTL: %v", err)
+	}
+
+	impersonationTTL, err := time.ParseDuration(cfg.ImpersonationTokenTTL)
+	if err != nil {
+		log.Fatalf("invalid IMPERSONATION_TOKEN_TTL: %v", err)
+	}
+
 	// =====================
 	// Repository
 	// =====================
 
 	// Auth
 	loginAttemptRepo := authRepo.NewLoginAttemptRepository(db)
-	// passwordResetTokenRepo := authRepo.NewPasswordResetTokenRepository(db)
-	// refreshTokenFamilyRepo := authRepo.NewRefreshTokenFamilyRepository(db)
+	loginRiskEventRepo := authRepo.NewLoginRiskEventRepository(db)
+	impersonationAuditRepo := authRepo.NewImpersonationAuditRepository(db)
+	accountLockEventRepo := authRepo.NewAccountLockEventRepository(db)
+	quotaPlanRepo := authRepo.NewQuotaPlanRepository(db)
+	quotaOverrideRepo := authRepo.NewQuotaOverrideRepository(db)
+	quotaUsageRepo := authRepo.NewQuotaUsageRepository(db)
+	emailOTPRepo := InitEmailOTPRepository(cfg, db, redisClient)
+	emailOutboxRepo := authRepo.NewEmailOutboxRepository(db)
+	emailVerificationTokenRepo := authRepo.NewEmailVerificationTokenRepository(db)
+	passwordResetTokenRepo := authRepo.NewPasswordResetTokenRepository(db)
+	passwordHistoryRepo := authRepo.NewPasswordHistoryRepository(db)
+	refreshTokenFamilyRepo := authRepo.NewRefreshTokenFamilyRepository(db)
 	refreshTokenRepo := authRepo.NewRefreshTokenRepository(db)
 	roleRepo := authRepo.NewRoleRepository(db)
 	userRepo := authRepo.NewUserRepository(db)
@@ -67,26 +93,86 @@ func InitHTTPApp(cfg *config.Config) *gin.Engine {
 	// Usecases
 	// =====================
 
-	// loginUC := authUC.NewLoginUsecase(
-	// 	userRepo,
-	// 	loginAttemptRepo,
-	// 	sessionRepo,
-	// 	refreshTokenRepo,
-	// 	passwordHasher,
-	// 	cfg.JWTSecret,
-	// 	accessExp,
-	// 	refreshExp,
-	// )
-
-	// passwordUC := authUC.NewPasswordUsecase(
-	// 	userRepo,
-	// 	passwordResetTokenRepo,
-	// 	refreshTokenRepo,
-	// 	sessionRepo,
-	// 	passwordHasher,
-	// 	mailer,
-	// 	accessExp,
-	// )
+	// email, SMS & WhatsApp dikirim lewat outbox: ditulis satu transaksi, dikirim worker dengan retry
+	messageChannels := InitMessageChannels(cfg)
+	emailOutboxUC := emailUC.NewEmailOutboxUsecase(
+		emailOutboxRepo,
+		emailSender,
+		messageChannels,
+		idCodec,
+		InitEmailOutboxPolicy(cfg),
+	)
+	StartEmailOutboxWorkers(cfg, emailOutboxUC)
+
+	// OTP & alert keamanan dikirim ke channel pilihan user (email / sms / whatsapp)
+	notificationUC := emailUC.NewNotificationUsecase(
+		emailOutboxUC,
+		cfg.AppName,
+		cfg.OTPExpiryMinutes,
+	)
+
+	// mail catcher hanya ada saat development
+	var devMailUC emailUC.DevMailUsecase
+	if mailCatcher != nil {
+		devMailUC = emailUC.NewDevMailUsecase(mailCatcher)
+	}
+
+	otpUC := emailUC.NewOTPUsecase(
+		notificationUC,
+		emailOTPRepo,
+		txManager,
+		cfg.OTPExpiryMinutes,
+		cfg.OTPMaxAttempts,
+	)
+
+	riskPolicy := authUC.DefaultRiskPolicy()
+	riskPolicy.StepUpThreshold = cfg.RiskStepUpThreshold
+	riskPolicy.BlockThreshold = cfg.RiskBlockThreshold
+	riskPolicy.ImpossibleTravelKmh = cfg.RiskImpossibleTravelKmh
+
+	riskEvaluator := authUC.NewRiskEvaluator(
+		sessionRepo,
+		loginRiskEventRepo,
+		geoResolver,
+		ipReputation,
+		riskPolicy,
+	)
+
+	// policy password baru: register, change & reset password
+	passwordPolicyUC := passwordUsecase.NewPasswordPolicyUsecase(
+		InitPasswordPolicy(cfg),
+		InitBreachedPasswordChecker(cfg),
+		InitPasswordStrengthEstimator(cfg),
+		passwordHistoryRepo,
+		passwordHasher,
+	)
+
+	// forgot / reset (link atau OTP) & change password; notifikasi lewat outbox
+	passwordUC := authUC.NewPasswordUsecase(
+		userRepo,
+		passwordResetTokenRepo,
+		refreshTokenRepo,
+		refreshTokenFamilyRepo,
+		sessionRepo,
+		passwordHasher,
+		passwordPolicyUC,
+		InitTokenGenerator(cfg),
+		InitTokenVerifier(cfg),
+		emailOutboxUC,
+		notificationUC,
+		txManager,
+		idCodec,
+		InitPasswordResetPolicy(cfg),
+	)
+
+	// PASSWORD_MAX_AGE_DAYS > 0 → password expired ditandai wajib ganti + email peringatan
+	if cfg.PasswordMaxAgeDays > 0 {
+		StartPasswordExpiryJob(cfg, authUC.NewPasswordExpiryUsecase(
+			userRepo,
+			passwordPolicyUC,
+			notificationUC,
+		))
+	}
 
 	sessionUC := authUC.NewSessionUsecase(
 		sessionRepo,
@@ -106,10 +192,122 @@ func InitHTTPApp(cfg *config.Config) *gin.Engine {
 		refreshExp,
 	)
 
-	// userUC := userUC.NewUserUsecase(
-	// 	userRepo,
-	// 	sessionRepo,
-	// 	refreshTokenRepo)
+	lockoutGuard := authUC.NewLockoutGuard(
+		loginAttemptRepo,
+		accountLockEventRepo,
+		userRepo,
+		InitTokenGenerator(cfg),
+		notificationUC,
+		InitLockoutPolicy(cfg),
+	)
+
+	accountLockUC := authUC.NewAccountLockUsecase(
+		userRepo,
+		accountLockEventRepo,
+		InitTokenVerifier(cfg),
+		idCodec,
+	)
+
+	loginHistoryUC := authUC.NewLoginHistoryUsecase(
+		loginAttemptRepo,
+		idCodec,
+	)
+
+	loginUC := authUC.NewLoginUsecase(
+		userRepo,
+		lockoutGuard,
+		passwordHasher,
+		roleRepo,
+		tokenUC,
+		riskEvaluator,
+		otpUC,
+		nil, // TOTP verifier belum tersedia → step-up via OTP (email / sms / whatsapp)
+		notificationUC,
+		passwordPolicyUC, // password bocor → wajib ganti password setelah login
+		InitUnverifiedLoginPolicy(cfg),
+	)
+
+	reauthUC := authUC.NewReauthUsecase(
+		userRepo,
+		passwordHasher,
+		nil, // TOTP verifier belum tersedia → re-auth via password
+		jwtSigner,
+		idCodec,
+		elevatedTTL,
+	)
+
+	impersonationUC := authUC.NewImpersonationUsecase(
+		userRepo,
+		roleRepo,
+		impersonationAuditRepo,
+		jwtSigner,
+		idCodec,
+		impersonationTTL,
+	)
+
+	// QUOTA_ENABLE=false → quotaUsecase nil (enforcement & endpoint kuota dimatikan)
+	var quotaUsecase quotaUC.QuotaUsecase
+	if quotaCounter != nil {
+		quotaUsecase = quotaUC.NewQuotaUsecase(
+			quotaCounter,
+			quotaPlanRepo,
+			quotaOverrideRepo,
+			quotaUsageRepo,
+			userRepo,
+			roleRepo,
+			idCodec,
+			valueobjects.QuotaLimits{
+				Daily:   cfg.QuotaDefaultDailyLimit,
+				Monthly: cfg.QuotaDefaultMonthlyLimit,
+			},
+		)
+		StartQuotaFlusher(cfg, quotaUsecase)
+	}
+
+	emailChangeUC := userUsecase.NewEmailChangeUsecase(
+		userRepo,
+		emailVerificationTokenRepo,
+		refreshTokenRepo,
+		refreshTokenFamilyRepo,
+		passwordHasher,
+		InitTokenGenerator(cfg),
+		InitTokenVerifier(cfg),
+		emailOutboxUC,
+		txManager,
+		idCodec,
+		InitEmailChangePolicy(cfg),
+	)
+
+	emailVerificationUC := userUsecase.NewEmailVerificationUsecase(
+		userRepo,
+		emailVerificationTokenRepo,
+		InitTokenGenerator(cfg),
+		InitTokenVerifier(cfg),
+		emailOutboxUC,
+		txManager,
+		idCodec,
+		InitEmailVerificationPolicy(cfg),
+	)
+
+	phoneUC := userUsecase.NewPhoneUsecase(
+		userRepo,
+		emailVerificationTokenRepo,
+		InitTokenVerifier(cfg),
+		notificationUC,
+		idCodec,
+		InitPhonePolicy(cfg, messageChannels),
+	)
+
+	userUC := userUsecase.NewUserUsecase(
+		userRepo,
+		sessionRepo,
+		passwordHasher,
+		passwordPolicyUC,
+		idCodec,
+		refreshTokenRepo,
+		refreshTokenFamilyRepo,
+		emailVerificationUC,
+	)
 
 	// =====================
 	// HTTP Router
@@ -123,12 +321,30 @@ func InitHTTPApp(cfg *config.Config) *gin.Engine {
 			Validator: validator.New(),
 			Config:    cfg,
 
-			LoginUC:    loginUC,
-			PasswordUC: passwordUC,
-			SessionUC:  sessionUC,
-			TokenUC:    tokenUC,
-			RoleUC:     roleUC,
-			UserUC:     userUC,
+			ChallengeVerifier: challengeVerifier,
+			RateLimiter:       rateLimiter,
+			RateLimitPolicies: InitRateLimitPolicies(cfg),
+
+			LoginUC:         loginUC,
+			ReauthUC:        reauthUC,
+			ImpersonationUC: impersonationUC,
+			AccountLockUC:   accountLockUC,
+			LoginHistoryUC:  loginHistoryUC,
+			QuotaUC:         quotaUsecase,
+			PasswordUC:      passwordUC,
+			SessionUC:       sessionUC,
+			TokenUC:         tokenUC,
+			RoleUC:          roleUC,
+			UserUC:          userUC,
+			EmailChangeUC:   emailChangeUC,
+
+			EmailVerificationUC: emailVerificationUC,
+			EmailOutboxUC:       emailOutboxUC,
+			DevMailUC:           devMailUC,
+			PhoneUC:             phoneUC,
+			PasswordPolicyUC:    passwordPolicyUC,
+
+			PasswordHashingMonitor: passwordHashingMonitor,
 		},
 	)
 
@@ -5,7 +5,9 @@ import (
 	"time"
 
 	"github.com/dhanarrizky/Golang-template/internal/config"
+	"github.com/dhanarrizky/Golang-template/internal/infrastructure/cache"
 	"github.com/dhanarrizky/Golang-template/internal/infrastructure/database/postgres"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
@@ -31,3 +33,22 @@ func InitDatabase(cfg *config.Config) *gorm.DB {
 
 	return db
 }
+
+// InitRedis returns nil when REDIS_HOST is not set
+func InitRedis(cfg *config.Config) *redis.Client {
+	if cfg.RedisHost == "" {
+		return nil
+	}
+
+	client, err := cache.NewRedisClient(cache.RedisConfig{
+		Host:     cfg.RedisHost,
+		Port:     cfg.RedisPort,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	})
+	if err != nil {
+		log.Fatal("failed to connect redis:", err)
+	}
+
+	return client
+}
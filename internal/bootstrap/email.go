@@ -0,0 +1,226 @@
+package bootstrap
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/dhanarrizky/Golang-template/internal/config"
+	authRepo "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/postgres/auth"
+	"github.com/dhanarrizky/Golang-template/internal/infrastructure/email"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/email"
+	userUC "github.com/dhanarrizky/Golang-template/internal/usecase/user"
+	mailer "github.com/dhanarrizky/Golang-template/pkg/email"
+	"github.com/dhanarrizky/Golang-template/pkg/template_html"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+func InitEmailSender(cfg *config.Config, transport email.Transport) ports.EmailSender {
+	return email.NewSender(
+		transport,
+		cfg.SMTPFrom,
+		InitEmailRenderer(cfg),
+		cfg.EmailLocale,
+		cfg.OTPExpiryMinutes,
+	)
+}
+
+// InitEmailOTPRepository memilih penyimpanan OTP dari OTP_STORE.
+// redis / memory: OTP tidak ikut transaksi outbox (tetap aman, OTP disimpan lebih dulu)
+func InitEmailOTPRepository(cfg *config.Config, db *gorm.DB, redisClient *redis.Client) ports.EmailOTPRepository {
+	switch cfg.OTPStore {
+	case "", "postgres":
+		return authRepo.NewEmailOTPRepository(db)
+
+	case "redis":
+		if redisClient == nil {
+			log.Fatal("OTP_STORE=redis requires REDIS_HOST")
+		}
+		return email.NewRedisOTPRepository(redisClient)
+
+	case "memory":
+		return email.NewMemoryOTPRepository()
+
+	default:
+		log.Fatalf("unknown OTP_STORE: %s", cfg.OTPStore)
+		return nil
+	}
+}
+
+// InitEmailTransport memilih transport dari EMAIL_TRANSPORT
+func InitEmailTransport(cfg *config.Config) email.Transport {
+	parse := func(name, value string) time.Duration {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			log.Fatalf("invalid %s: %v", name, err)
+		}
+		return d
+	}
+
+	switch cfg.EmailTransport {
+	case "", "smtp":
+		transport, err := email.NewSMTPTransport(email.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			Security: cfg.SMTPSecurity,
+			Timeout:  parse("SMTP_TIMEOUT", cfg.SMTPTimeout),
+			PoolSize: cfg.SMTPPoolSize,
+		})
+		if err != nil {
+			log.Fatalf("invalid SMTP config: %v", err)
+		}
+		return transport
+
+	case "file":
+		transport, err := email.NewFileTransport(cfg.EmailFileDir, cfg.EmailFileFormat)
+		if err != nil {
+			log.Fatalf("invalid EMAIL_FILE_DIR / EMAIL_FILE_FORMAT: %v", err)
+		}
+		return transport
+
+	case "memory":
+		return email.NewMemoryTransport(0)
+
+	case "http":
+		provider, ok := email.HTTPProviders[cfg.EmailHTTPProvider]
+		if !ok {
+			log.Fatalf("unknown EMAIL_HTTP_PROVIDER: %s", cfg.EmailHTTPProvider)
+		}
+		return email.NewHTTPTransport(
+			provider,
+			cfg.EmailHTTPEndpoint,
+			cfg.EmailHTTPAPIKey,
+			parse("EMAIL_HTTP_TIMEOUT", cfg.EmailHTTPTimeout),
+		)
+
+	default:
+		log.Fatalf("unknown EMAIL_TRANSPORT: %s", cfg.EmailTransport)
+		return nil
+	}
+}
+
+// InitDKIMTransport: DKIM_ENABLE=true → setiap email ditandatangani sebelum dikirim.
+// Penandatanganan ada di level Transport (MIME yang benar-benar dikirim), jadi
+// berlaku untuk semua EmailSender di atasnya selama transport mengirim MIME apa
+// adanya: smtp, file, memory. EMAIL_TRANSPORT=http tidak ikut ditandatangani.
+func InitDKIMTransport(cfg *config.Config, transport email.Transport) email.Transport {
+	if !cfg.DKIMEnable {
+		return transport
+	}
+
+	// provider HTTP menerima field, bukan MIME → DKIM diatur di dashboard provider
+	if cfg.EmailTransport == "http" {
+		log.Printf("DKIM_ENABLE ignored for EMAIL_TRANSPORT=http, configure DKIM at the provider")
+		return transport
+	}
+
+	return email.NewDKIMTransport(transport, InitDKIMSigner(cfg))
+}
+
+// InitDKIMSigner dari DKIM_*
+func InitDKIMSigner(cfg *config.Config) *mailer.DKIMSigner {
+	signer, err := mailer.NewDKIMSignerFromSettings(mailer.DKIMSettings{
+		Domain:           cfg.DKIMDomain,
+		Selector:         cfg.DKIMSelector,
+		PrivateKey:       cfg.DKIMPrivateKey,
+		PrivateKeyPath:   cfg.DKIMPrivateKeyPath,
+		Canonicalization: cfg.DKIMCanonical,
+		Headers:          cfg.DKIMHeaders,
+	})
+	if err != nil {
+		log.Fatalf("invalid DKIM config: %v", err)
+	}
+
+	return signer
+}
+
+// InitMailCatcher: dengan DEV_MAIL_CATCHER=true saat development semua email
+// keluar juga disimpan di memory (mail catcher, lihat /v1/dev/mail) lalu tetap
+// diteruskan ke transport asli. Selain itu catcher nil dan transport tidak diubah.
+func InitMailCatcher(cfg *config.Config, transport email.Transport) (email.Transport, ports.MailCatcher) {
+	if !cfg.DevMailCatcher {
+		return transport, nil
+	}
+	if !cfg.IsDevelopment() {
+		log.Printf("[SECURITY] WARNING: DEV_MAIL_CATCHER is ignored because APP_ENV=%s is not development", cfg.Environment)
+		return transport, nil
+	}
+
+	log.Println("[SECURITY] WARNING: DEV_MAIL_CATCHER is on: /v1/dev/mail serves every outgoing email " +
+		"(OTP codes, reset and verification links) without authentication. Never enable it on a reachable host.")
+
+	// EMAIL_TRANSPORT=memory sudah menangkap semua pesan
+	if memory, ok := transport.(*email.MemoryTransport); ok {
+		return transport, memory
+	}
+
+	catcher := email.NewMemoryTransport(0)
+	return email.NewCaptureTransport(transport, catcher), catcher
+}
+
+// InitEmailRenderer: template bawaan di-embed, EMAIL_TEMPLATE_DIR menimpa per file
+func InitEmailRenderer(cfg *config.Config) *mailer.Renderer {
+	if cfg.EmailTemplateDir != "" {
+		if _, err := os.Stat(cfg.EmailTemplateDir); err != nil {
+			log.Fatalf("invalid EMAIL_TEMPLATE_DIR: %v", err)
+		}
+	}
+
+	return mailer.NewRenderer(
+		template_html.FS,
+		cfg.EmailTemplateDir,
+		cfg.AppName,
+		cfg.EmailLocale,
+	)
+}
+
+func InitEmailChangePolicy(cfg *config.Config) userUC.EmailChangePolicy {
+	parse := func(name, value string) time.Duration {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			log.Fatalf("invalid %s: %v", name, err)
+		}
+		return d
+	}
+
+	policy := userUC.DefaultEmailChangePolicy()
+	policy.VerifyURL = cfg.EmailChangeVerifyURL
+	policy.RevertURL = cfg.EmailChangeRevertURL
+	policy.VerifyTTL = parse("EMAIL_CHANGE_TOKEN_TTL", cfg.EmailChangeTokenTTL)
+	policy.RevertTTL = parse("EMAIL_CHANGE_REVERT_TTL", cfg.EmailChangeRevertTTL)
+
+	return policy
+}
+
+func InitEmailVerificationPolicy(cfg *config.Config) userUC.EmailVerificationPolicy {
+	policy := userUC.DefaultEmailVerificationPolicy()
+
+	switch cfg.EmailVerificationMethod {
+	case userUC.EmailVerificationMethodLink, userUC.EmailVerificationMethodOTP:
+		policy.Method = cfg.EmailVerificationMethod
+	default:
+		log.Fatalf("invalid EMAIL_VERIFICATION_METHOD: %q", cfg.EmailVerificationMethod)
+	}
+
+	ttl, err := time.ParseDuration(cfg.EmailVerificationTTL)
+	if err != nil || ttl <= 0 {
+		log.Fatalf("invalid EMAIL_VERIFICATION_TTL: %v", err)
+	}
+
+	cooldown, err := time.ParseDuration(cfg.EmailVerificationResendCooldown)
+	if err != nil {
+		log.Fatalf("invalid EMAIL_VERIFICATION_RESEND_COOLDOWN: %v", err)
+	}
+
+	policy.VerifyURL = cfg.EmailVerificationURL
+	policy.TokenTTL = ttl
+	policy.ResendCooldown = cooldown
+	if cfg.EmailVerificationMaxAttempts > 0 {
+		policy.MaxAttempts = cfg.EmailVerificationMaxAttempts
+	}
+
+	return policy
+}
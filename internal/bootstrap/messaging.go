@@ -0,0 +1,86 @@
+package bootstrap
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"github.com/dhanarrizky/Golang-template/internal/config"
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	"github.com/dhanarrizky/Golang-template/internal/infrastructure/messaging"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+	userUC "github.com/dhanarrizky/Golang-template/internal/usecase/user"
+)
+
+// InitMessageChannels memilih channel non-email dari SMS_PROVIDER dan WHATSAPP_PROVIDER.
+// Provider "none" = channel tidak dipasang (user tidak bisa memilihnya)
+func InitMessageChannels(cfg *config.Config) map[string]ports.MessageChannel {
+	parse := func(name, value string) time.Duration {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			log.Fatalf("invalid %s: %v", name, err)
+		}
+		return d
+	}
+
+	channels := map[string]ports.MessageChannel{}
+
+	switch cfg.SMSProvider {
+	case "", "none":
+	case "log":
+		channels[valueobjects.ChannelSMS] = messaging.NewLogChannel(valueobjects.ChannelSMS)
+	case "http":
+		if cfg.SMSHTTPEndpoint == "" {
+			log.Fatal("SMS_HTTP_ENDPOINT is required when SMS_PROVIDER=http")
+		}
+		channels[valueobjects.ChannelSMS] = messaging.NewHTTPSMSGateway(
+			cfg.SMSHTTPEndpoint,
+			cfg.SMSHTTPAPIKey,
+			cfg.SMSSenderID,
+			parse("SMS_HTTP_TIMEOUT", cfg.SMSHTTPTimeout),
+		)
+	default:
+		log.Fatalf("unknown SMS_PROVIDER: %s", cfg.SMSProvider)
+	}
+
+	switch cfg.WhatsAppProvider {
+	case "", "none":
+	case "log":
+		channels[valueobjects.ChannelWhatsApp] = messaging.NewLogChannel(valueobjects.ChannelWhatsApp)
+	case "cloud":
+		if cfg.WhatsAppEndpoint == "" || cfg.WhatsAppToken == "" {
+			log.Fatal("WHATSAPP_ENDPOINT and WHATSAPP_TOKEN are required when WHATSAPP_PROVIDER=cloud")
+		}
+		channels[valueobjects.ChannelWhatsApp] = messaging.NewWhatsAppCloudChannel(
+			cfg.WhatsAppEndpoint,
+			cfg.WhatsAppToken,
+			parse("WHATSAPP_TIMEOUT", cfg.WhatsAppTimeout),
+		)
+	default:
+		log.Fatalf("unknown WHATSAPP_PROVIDER: %s", cfg.WhatsAppProvider)
+	}
+
+	return channels
+}
+
+func InitPhonePolicy(cfg *config.Config, channels map[string]ports.MessageChannel) userUC.PhonePolicy {
+	parse := func(name, value string) time.Duration {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			log.Fatalf("invalid %s: %v", name, err)
+		}
+		return d
+	}
+
+	policy := userUC.DefaultPhonePolicy()
+	policy.DefaultCountryCode = cfg.PhoneDefaultCountryCode
+	policy.CodeTTL = parse("PHONE_CODE_TTL", cfg.PhoneCodeTTL)
+	policy.ResendCooldown = parse("PHONE_RESEND_COOLDOWN", cfg.PhoneResendCooldown)
+
+	for name := range channels {
+		policy.Channels = append(policy.Channels, name)
+	}
+	sort.Strings(policy.Channels)
+
+	return policy
+}
@@ -0,0 +1,65 @@
+package bootstrap
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dhanarrizky/Golang-template/internal/config"
+	emailUC "github.com/dhanarrizky/Golang-template/internal/usecase/email"
+)
+
+func InitEmailOutboxPolicy(cfg *config.Config) emailUC.EmailOutboxPolicy {
+	parse := func(name, value string) time.Duration {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			log.Fatalf("invalid %s: %v", name, err)
+		}
+		return d
+	}
+
+	policy := emailUC.DefaultEmailOutboxPolicy()
+	policy.BaseDelay = parse("EMAIL_OUTBOX_BASE_DELAY", cfg.EmailOutboxBaseDelay)
+	policy.MaxDelay = parse("EMAIL_OUTBOX_MAX_DELAY", cfg.EmailOutboxMaxDelay)
+	if cfg.EmailOutboxMaxAttempts > 0 {
+		policy.MaxAttempts = cfg.EmailOutboxMaxAttempts
+	}
+	if cfg.EmailOutboxBatchSize > 0 {
+		policy.BatchSize = cfg.EmailOutboxBatchSize
+	}
+
+	return policy
+}
+
+// StartEmailOutboxWorkers runs EMAIL_OUTBOX_WORKERS pollers. Each one drains due
+// messages batch by batch, then sleeps for EMAIL_OUTBOX_POLL_INTERVAL. Claiming
+// uses SKIP LOCKED so several instances can share the same table.
+func StartEmailOutboxWorkers(cfg *config.Config, uc emailUC.EmailOutboxUsecase) {
+	interval, err := time.ParseDuration(cfg.EmailOutboxPollInterval)
+	if err != nil || interval <= 0 {
+		log.Fatalf("invalid EMAIL_OUTBOX_POLL_INTERVAL: %v", err)
+	}
+
+	for i := 0; i < cfg.EmailOutboxWorkers; i++ {
+		go func(worker int) {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			for range ticker.C {
+				for {
+					ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+					n, err := uc.ProcessDue(ctx)
+					cancel()
+
+					if err != nil {
+						log.Printf("[OUTBOX] worker %d: %v", worker, err)
+						break
+					}
+					if n == 0 {
+						break
+					}
+				}
+			}
+		}(i)
+	}
+}
@@ -0,0 +1,38 @@
+package bootstrap
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dhanarrizky/Golang-template/internal/config"
+	authUC "github.com/dhanarrizky/Golang-template/internal/usecase/auth"
+)
+
+// StartPasswordExpiryJob flags expired passwords and enqueues expiry warnings
+// every PASSWORD_EXPIRY_CHECK_INTERVAL. Only started when PASSWORD_MAX_AGE_DAYS > 0.
+func StartPasswordExpiryJob(cfg *config.Config, uc authUC.PasswordExpiryUsecase) {
+	interval, err := time.ParseDuration(cfg.PasswordExpiryCheckInterval)
+	if err != nil || interval <= 0 {
+		log.Fatalf("invalid PASSWORD_EXPIRY_CHECK_INTERVAL: %v", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			result, err := uc.ProcessDue(ctx)
+			cancel()
+
+			if err != nil {
+				log.Printf("[PASSWORD_EXPIRY] run failed: %v", err)
+				continue
+			}
+			if result.Flagged > 0 || result.Warned > 0 || result.Failed > 0 {
+				log.Printf("[PASSWORD_EXPIRY] %d expired, %d warned, %d to retry", result.Flagged, result.Warned, result.Failed)
+			}
+		}
+	}()
+}
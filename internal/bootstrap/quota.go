@@ -0,0 +1,56 @@
+package bootstrap
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dhanarrizky/Golang-template/internal/config"
+	"github.com/dhanarrizky/Golang-template/internal/infrastructure/quota"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+	quotaUC "github.com/dhanarrizky/Golang-template/internal/usecase/quota"
+	"github.com/redis/go-redis/v9"
+)
+
+// InitQuotaCounter returns nil when QUOTA_ENABLE is false (quota disabled)
+func InitQuotaCounter(cfg *config.Config, redisClient *redis.Client) ports.QuotaCounter {
+	if !cfg.QuotaEnable {
+		return nil
+	}
+
+	switch cfg.QuotaStore {
+	case "", "memory":
+		return quota.NewMemoryCounter()
+
+	case "redis":
+		if redisClient == nil {
+			log.Fatal("QUOTA_STORE=redis requires REDIS_HOST")
+		}
+		return quota.NewRedisCounter(redisClient)
+
+	default:
+		log.Fatalf("unknown QUOTA_STORE: %s", cfg.QuotaStore)
+		return nil
+	}
+}
+
+// StartQuotaFlusher persists quota counters to Postgres every QUOTA_FLUSH_INTERVAL
+func StartQuotaFlusher(cfg *config.Config, uc quotaUC.QuotaUsecase) {
+	interval, err := time.ParseDuration(cfg.QuotaFlushInterval)
+	if err != nil || interval <= 0 {
+		log.Fatalf("invalid QUOTA_FLUSH_INTERVAL: %v", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			if _, err := uc.Flush(ctx); err != nil {
+				log.Printf("[QUOTA] flush failed: %v", err)
+			}
+			cancel()
+		}
+	}()
+}
@@ -0,0 +1,62 @@
+package bootstrap
+
+import (
+	"log"
+
+	"github.com/dhanarrizky/Golang-template/internal/config"
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	"github.com/dhanarrizky/Golang-template/internal/infrastructure/ratelimit"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+	"github.com/redis/go-redis/v9"
+)
+
+// InitRateLimiter returns nil when RATE_LIMITER_ENABLE is false (rate limit disabled)
+func InitRateLimiter(cfg *config.Config, redisClient *redis.Client) ports.RateLimiter {
+	if !cfg.RateLimiterEnable {
+		return nil
+	}
+
+	switch cfg.RateLimiterStore {
+	case "", "memory":
+		return ratelimit.NewMemoryStore()
+
+	case "redis":
+		if redisClient == nil {
+			log.Fatal("RATE_LIMITER_STORE=redis requires REDIS_HOST")
+		}
+		return ratelimit.NewRedisStore(redisClient)
+
+	default:
+		log.Fatalf("unknown RATE_LIMITER_STORE: %s", cfg.RateLimiterStore)
+		return nil
+	}
+}
+
+func InitRateLimitPolicies(cfg *config.Config) map[string]valueobjects.RateLimitPolicy {
+	policies := make(map[string]valueobjects.RateLimitPolicy, len(cfg.RateLimitPolicies))
+
+	for name, pc := range cfg.RateLimitPolicies {
+		algorithm := pc.Algorithm
+		if algorithm == "" {
+			algorithm = cfg.RateLimiterAlgorithm
+		}
+
+		switch valueobjects.RateLimitAlgorithm(algorithm) {
+		case valueobjects.RateLimitTokenBucket,
+			valueobjects.RateLimitSlidingWindow,
+			valueobjects.RateLimitGCRA:
+		default:
+			log.Fatalf("unknown rate limit algorithm %q for policy %s", algorithm, name)
+		}
+
+		policies[name] = valueobjects.RateLimitPolicy{
+			Name:      name,
+			Algorithm: valueobjects.RateLimitAlgorithm(algorithm),
+			Limit:     pc.Limit,
+			Window:    pc.Window,
+			Burst:     pc.Burst,
+		}
+	}
+
+	return policies
+}
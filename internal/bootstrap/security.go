@@ -2,16 +2,78 @@ package bootstrap
 
 import (
 	"log"
+	"strings"
+	"time"
 
 	"github.com/dhanarrizky/Golang-template/internal/config"
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
 
+	"github.com/dhanarrizky/Golang-template/internal/infrastructure/breach"
+	"github.com/dhanarrizky/Golang-template/internal/infrastructure/challenge"
+	"github.com/dhanarrizky/Golang-template/internal/infrastructure/geoip"
 	"github.com/dhanarrizky/Golang-template/internal/infrastructure/security"
+	"github.com/dhanarrizky/Golang-template/internal/infrastructure/strength"
 	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+	userPorts "github.com/dhanarrizky/Golang-template/internal/ports/users"
+	authUC "github.com/dhanarrizky/Golang-template/internal/usecase/auth"
 )
 
-// func InitPasswordHasher(cfg *config.Config) ports.PasswordHasher {
-// 	return security.NewArgon2PasswordHasher(cfg)
-// }
+// InitPasswordHasher warns (without failing) when the Argon2id parameters are
+// below the OWASP minimums or PASSWORD_HASH_EXPECTED_CONCURRENCY hashes would
+// not fit in the memory limit. Run cmd/argon2-calibrate to pick values.
+//
+// Hashing runs in a pool bounded by PASSWORD_HASH_MEMORY_BUDGET_MB; the
+// returned monitor exposes its queue metrics.
+func InitPasswordHasher(cfg *config.Config) (userPorts.PasswordHasher, userPorts.PasswordHashingMonitor) {
+	if cfg.Password.Iterations < 1 || cfg.Password.Parallelism < 1 {
+		log.Fatalf("invalid argon2id parameters: PASSWORD_ARGON2_ITERATIONS and PASSWORD_ARGON2_PARALLELISM must be at least 1")
+	}
+
+	memoryLimit := uint64(cfg.Password.MemoryLimitMB) << 20
+	if memoryLimit == 0 {
+		memoryLimit = security.ContainerMemoryLimit()
+	}
+
+	params := security.Argon2Params{
+		Memory:      cfg.Password.Memory,
+		Iterations:  cfg.Password.Iterations,
+		Parallelism: cfg.Password.Parallelism,
+	}
+	for _, warning := range security.CheckArgon2Params(params, memoryLimit, cfg.Password.ExpectedConcurrency) {
+		log.Printf("[SECURITY] WARNING: %s", warning)
+	}
+
+	queueTimeout, err := time.ParseDuration(cfg.Password.QueueTimeout)
+	if err != nil || queueTimeout <= 0 {
+		log.Fatalf("invalid PASSWORD_HASH_QUEUE_TIMEOUT: %v", err)
+	}
+
+	budget := uint64(cfg.Password.MemoryBudgetMB) << 10 // KiB
+	switch {
+	case budget > 0:
+	case memoryLimit > 0:
+		budget = memoryLimit / 2 >> 10 // sisanya untuk aplikasi
+	default:
+		budget = uint64(cfg.Password.Memory) * uint64(max(cfg.Password.ExpectedConcurrency, 1))
+	}
+	if budget < uint64(cfg.Password.Memory) {
+		log.Printf("[SECURITY] WARNING: password hashing memory budget (%d MiB) is below PASSWORD_ARGON2_MEMORY, hashes will run one at a time", budget>>10)
+	}
+	log.Printf("[SECURITY] password hashing pool: %d MiB, up to %d concurrent hashes", budget>>10, budget/uint64(max(cfg.Password.Memory, 1)))
+
+	hasher := security.NewPasswordHasher(&security.PasswordConfig{
+		Memory:               cfg.Password.Memory,
+		Iterations:           cfg.Password.Iterations,
+		Parallelism:          cfg.Password.Parallelism,
+		SaltLength:           cfg.Password.SaltLength,
+		KeyLength:            cfg.Password.KeyLength,
+		Peppers:              cfg.Password.Peppers,
+		CurrentPepperVersion: cfg.Password.CurrentPepperVersion,
+	})
+
+	pool := security.NewPasswordHashingPool(hasher, budget, cfg.Password.Memory, queueTimeout)
+	return pool, pool
+}
 
 func InitPublicIdCodec(cfg *config.Config) ports.PublicIDCodec {
 	secret := cfg.PublicIdAesKey
@@ -39,3 +101,229 @@ func InitTokenGenerator(cfg *config.Config) ports.TokenGenerator {
 	verifier := InitTokenVerifier(cfg)
 	return security.NewSecureTokenGenerator(verifier)
 }
+
+// InitGeoIPResolver returns nil when no database is configured (geo checks are skipped)
+func InitGeoIPResolver(cfg *config.Config) ports.GeoIPResolver {
+	if cfg.GeoIPDatabasePath == "" {
+		return nil
+	}
+
+	resolver, err := geoip.NewCSVResolver(cfg.GeoIPDatabasePath)
+	if err != nil {
+		log.Fatalf("failed to load GeoIP database: %v", err)
+	}
+	return resolver
+}
+
+func InitIPReputationChecker(cfg *config.Config) ports.IPReputationChecker {
+	checker, err := security.NewIPReputationList(
+		cfg.IPMaliciousListPath,
+		cfg.IPSuspiciousListPath,
+	)
+	if err != nil {
+		log.Fatalf("failed to load IP reputation lists: %v", err)
+	}
+	return checker
+}
+
+func InitLockoutPolicy(cfg *config.Config) authUC.LockoutPolicy {
+	parse := func(name, value string) time.Duration {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("invalid %s: %v", name, err)
+		}
+		return d
+	}
+
+	policy := authUC.DefaultLockoutPolicy()
+	policy.Window = parse("LOCKOUT_WINDOW", cfg.LockoutWindow)
+	policy.FreeAttempts = cfg.LockoutFreeAttempts
+	policy.IPFreeAttempts = cfg.LockoutIPFreeAttempts
+	policy.BaseDelay = parse("LOCKOUT_BASE_DELAY", cfg.LockoutBaseDelay)
+	policy.MaxDelay = parse("LOCKOUT_MAX_DELAY", cfg.LockoutMaxDelay)
+	policy.HardLockThreshold = cfg.LockoutHardLockThreshold
+	policy.LockCooldown = parse("LOCKOUT_COOLDOWN", cfg.LockoutCooldown)
+	policy.UnlockURL = cfg.AccountUnlockURL
+
+	return policy
+}
+
+func InitUnverifiedLoginPolicy(cfg *config.Config) string {
+	switch cfg.LoginUnverifiedPolicy {
+	case authUC.UnverifiedLoginAllow, authUC.UnverifiedLoginLimit, authUC.UnverifiedLoginBlock:
+		return cfg.LoginUnverifiedPolicy
+	}
+
+	log.Fatalf("invalid LOGIN_UNVERIFIED_POLICY: %q", cfg.LoginUnverifiedPolicy)
+	return ""
+}
+
+// InitChallengeVerifier returns nil when CHALLENGE_PROVIDER is "none" (challenge disabled)
+func InitChallengeVerifier(cfg *config.Config) ports.ChallengeVerifier {
+	switch cfg.ChallengeProvider {
+	case "", "none":
+		return nil
+
+	case "pow":
+		secret := cfg.ChallengeSecret
+		if secret == "" {
+			secret = cfg.SecretKey
+		}
+
+		ttl, err := time.ParseDuration(cfg.ChallengePoWTTL)
+		if err != nil {
+			log.Fatalf("invalid CHALLENGE_POW_TTL: %v", err)
+		}
+		return challenge.NewPoWVerifier(secret, cfg.ChallengePoWDifficulty, ttl)
+
+	case "hcaptcha", "turnstile":
+		if cfg.ChallengeSecret == "" {
+			log.Fatal("CHALLENGE_SECRET is not set")
+		}
+
+		verifyURL := cfg.ChallengeVerifyURL
+		if verifyURL == "" {
+			verifyURL = challenge.HCaptchaVerifyURL
+			if cfg.ChallengeProvider == "turnstile" {
+				verifyURL = challenge.TurnstileVerifyURL
+			}
+		}
+		return challenge.NewHTTPVerifier(verifyURL, cfg.ChallengeSecret, cfg.ChallengeSiteKey, 5*time.Second)
+
+	default:
+		log.Fatalf("unknown CHALLENGE_PROVIDER: %s", cfg.ChallengeProvider)
+		return nil
+	}
+}
+
+func InitPasswordResetPolicy(cfg *config.Config) authUC.PasswordResetPolicy {
+	policy := authUC.DefaultPasswordResetPolicy()
+
+	switch cfg.PasswordResetMethod {
+	case authUC.PasswordResetMethodLink, authUC.PasswordResetMethodOTP:
+		policy.Method = cfg.PasswordResetMethod
+	default:
+		log.Fatalf("invalid PASSWORD_RESET_METHOD: %q", cfg.PasswordResetMethod)
+	}
+
+	ttl, err := time.ParseDuration(cfg.PasswordResetTTL)
+	if err != nil || ttl <= 0 {
+		log.Fatalf("invalid PASSWORD_RESET_TTL: %v", err)
+	}
+
+	cooldown, err := time.ParseDuration(cfg.PasswordResetCooldown)
+	if err != nil {
+		log.Fatalf("invalid PASSWORD_RESET_COOLDOWN: %v", err)
+	}
+
+	policy.ResetURL = cfg.PasswordResetURL
+	policy.TokenTTL = ttl
+	policy.ResendCooldown = cooldown
+	if cfg.PasswordResetMaxAttempts > 0 {
+		policy.MaxAttempts = cfg.PasswordResetMaxAttempts
+	}
+
+	return policy
+}
+
+func InitPasswordPolicy(cfg *config.Config) valueobjects.PasswordPolicy {
+	switch cfg.PasswordPolicyMode {
+	case valueobjects.PasswordPolicyModeComposition, valueobjects.PasswordPolicyModeNIST:
+	default:
+		log.Fatalf("invalid PASSWORD_POLICY_MODE: %q", cfg.PasswordPolicyMode)
+	}
+
+	if cfg.PasswordMinLength < 1 {
+		log.Fatalf("invalid PASSWORD_MIN_LENGTH: %d", cfg.PasswordMinLength)
+	}
+	if cfg.PasswordMaxLength > 0 && cfg.PasswordMaxLength < cfg.PasswordMinLength {
+		log.Fatalf("PASSWORD_MAX_LENGTH (%d) is lower than PASSWORD_MIN_LENGTH (%d)", cfg.PasswordMaxLength, cfg.PasswordMinLength)
+	}
+	if cfg.PasswordMinStrength < 0 || cfg.PasswordMinStrength > 4 {
+		log.Fatalf("invalid PASSWORD_MIN_STRENGTH: %d (0-4)", cfg.PasswordMinStrength)
+	}
+	if cfg.PasswordHistorySize < 0 {
+		log.Fatalf("invalid PASSWORD_HISTORY_SIZE: %d", cfg.PasswordHistorySize)
+	}
+	if cfg.PasswordMaxAgeDays < 0 {
+		log.Fatalf("invalid PASSWORD_MAX_AGE_DAYS: %d", cfg.PasswordMaxAgeDays)
+	}
+	if cfg.PasswordExpiryWarningDays < 0 {
+		log.Fatalf("invalid PASSWORD_EXPIRY_WARNING_DAYS: %d", cfg.PasswordExpiryWarningDays)
+	}
+	if cfg.PasswordMaxAgeDays > 0 && cfg.PasswordExpiryWarningDays >= cfg.PasswordMaxAgeDays {
+		log.Fatalf("PASSWORD_EXPIRY_WARNING_DAYS (%d) must be lower than PASSWORD_MAX_AGE_DAYS (%d)", cfg.PasswordExpiryWarningDays, cfg.PasswordMaxAgeDays)
+	}
+
+	var extra []string
+	for _, w := range strings.Split(cfg.PasswordBlocklist, ",") {
+		if w = strings.TrimSpace(w); w != "" {
+			extra = append(extra, w)
+		}
+	}
+	if cfg.AppName != "" {
+		extra = append(extra, cfg.AppName) // nama aplikasi selalu ditolak
+	}
+
+	blocklist, err := security.LoadPasswordBlocklist(cfg.PasswordBlocklistBuiltin, cfg.PasswordBlocklistPath, extra)
+	if err != nil {
+		log.Fatalf("failed to load password blocklist: %v", err)
+	}
+
+	return valueobjects.PasswordPolicy{
+		Mode:             cfg.PasswordPolicyMode,
+		MinLength:        cfg.PasswordMinLength,
+		MaxLength:        cfg.PasswordMaxLength,
+		RequireLower:     cfg.PasswordRequireLower,
+		RequireUpper:     cfg.PasswordRequireUpper,
+		RequireDigit:     cfg.PasswordRequireDigit,
+		RequireSpecial:   cfg.PasswordRequireSpecial,
+		MaxRepeated:      cfg.PasswordMaxRepeated,
+		DisallowUserInfo: cfg.PasswordDisallowUserInfo,
+		Blocklist:        blocklist,
+		BreachMinCount:   cfg.PasswordBreachMinCount,
+		MinStrength:      cfg.PasswordMinStrength,
+		HistorySize:      cfg.PasswordHistorySize,
+		MaxAge:           time.Duration(cfg.PasswordMaxAgeDays) * 24 * time.Hour,
+		ExpiryWarning:    time.Duration(cfg.PasswordExpiryWarningDays) * 24 * time.Hour,
+	}
+}
+
+// InitBreachedPasswordChecker returns nil when PASSWORD_BREACH_CHECK is "none"
+func InitBreachedPasswordChecker(cfg *config.Config) ports.BreachedPasswordChecker {
+	switch cfg.PasswordBreachCheck {
+	case "", "none":
+		return nil
+
+	case "offline":
+		if cfg.PasswordBreachDataset == "" {
+			log.Fatal("PASSWORD_BREACH_CHECK=offline requires PASSWORD_BREACH_DATASET")
+		}
+
+		checker, err := breach.NewOfflineChecker(cfg.PasswordBreachDataset)
+		if err != nil {
+			log.Fatalf("failed to open breached password dataset: %v", err)
+		}
+		return checker
+
+	case "online":
+		timeout, err := time.ParseDuration(cfg.PasswordBreachTimeout)
+		if err != nil {
+			log.Fatalf("invalid PASSWORD_BREACH_TIMEOUT: %v", err)
+		}
+		return breach.NewRangeAPIChecker(cfg.PasswordBreachAPIURL, timeout)
+
+	default:
+		log.Fatalf("unknown PASSWORD_BREACH_CHECK: %s", cfg.PasswordBreachCheck)
+		return nil
+	}
+}
+
+// InitPasswordStrengthEstimator: nama aplikasi ikut dianggap kata yang mudah ditebak
+func InitPasswordStrengthEstimator(cfg *config.Config) ports.PasswordStrengthEstimator {
+	var extra []string
+	if cfg.AppName != "" {
+		extra = append(extra, cfg.AppName)
+	}
+	return strength.NewEstimator(extra...)
+}
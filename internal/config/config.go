@@ -1,7 +1,10 @@
 package config
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -40,7 +43,7 @@ type Config struct {
 	// =========================
 	// Security - Password (Argon2id)
 	// =========================
-	Password PasswordConfig
+	Password PasswordConfig `mapstructure:",squash"` // key PASSWORD_ARGON2_* ada di level atas env
 
 	// =========================
 	// Security - Password (Argon2id)
@@ -57,11 +60,203 @@ type Config struct {
 	// =========================
 	CORSAllowedOrigins []string
 
+	// =========================
+	// SMTP / Email
+	// =========================
+	SMTPHost         string `mapstructure:"SMTP_HOST"`
+	SMTPPort         string `mapstructure:"SMTP_PORT"`
+	SMTPUsername     string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword     string `mapstructure:"SMTP_PASSWORD"`
+	SMTPFrom         string `mapstructure:"SMTP_FROM"`
+	SMTPSecurity     string `mapstructure:"SMTP_SECURITY"` // starttls | tls | none
+	SMTPTimeout      string `mapstructure:"SMTP_TIMEOUT"`
+	SMTPPoolSize     int    `mapstructure:"SMTP_POOL_SIZE"` // koneksi idle yang dipakai ulang
+	OTPExpiryMinutes int    `mapstructure:"OTP_EXPIRY_MINUTES"`
+	OTPMaxAttempts   int    `mapstructure:"OTP_MAX_ATTEMPTS"`   // kode salah sebelum OTP dihapus
+	OTPStore         string `mapstructure:"OTP_STORE"`          // postgres | redis | memory
+	EmailLocale      string `mapstructure:"EMAIL_LOCALE"`       // en | id
+	EmailTemplateDir string `mapstructure:"EMAIL_TEMPLATE_DIR"` // override template bawaan (opsional)
+
+	// EMAIL_TRANSPORT: smtp | file | memory | http
+	EmailTransport    string `mapstructure:"EMAIL_TRANSPORT"`
+	EmailFileDir      string `mapstructure:"EMAIL_FILE_DIR"`
+	EmailFileFormat   string `mapstructure:"EMAIL_FILE_FORMAT"`   // eml | maildir
+	EmailHTTPProvider string `mapstructure:"EMAIL_HTTP_PROVIDER"` // generic | sendgrid | mailgun
+	EmailHTTPEndpoint string `mapstructure:"EMAIL_HTTP_ENDPOINT"`
+	EmailHTTPAPIKey   string `mapstructure:"EMAIL_HTTP_API_KEY"`
+	EmailHTTPTimeout  string `mapstructure:"EMAIL_HTTP_TIMEOUT"`
+
+	// DEV_MAIL_CATCHER: /v1/dev/mail tanpa auth (berisi OTP & link reset),
+	// harus diaktifkan eksplisit dan hanya berlaku saat APP_ENV development
+	DevMailCatcher bool `mapstructure:"DEV_MAIL_CATCHER"`
+
+	// =========================
+	// DKIM (tanda tangan email keluar, smtp / file transport)
+	// =========================
+	DKIMEnable         bool   `mapstructure:"DKIM_ENABLE"`
+	DKIMDomain         string `mapstructure:"DKIM_DOMAIN"`
+	DKIMSelector       string `mapstructure:"DKIM_SELECTOR"`
+	DKIMPrivateKey     string `mapstructure:"DKIM_PRIVATE_KEY"`      // PEM (RSA / Ed25519), atau pakai path di bawah
+	DKIMPrivateKeyPath string `mapstructure:"DKIM_PRIVATE_KEY_PATH"` // file PEM
+	DKIMCanonical      string `mapstructure:"DKIM_CANONICALIZATION"` // header/body: relaxed/relaxed | simple/simple | ...
+	DKIMHeaders        string `mapstructure:"DKIM_HEADERS"`          // dipisah koma, kosong = header bawaan
+
+	// =========================
+	// SMS / WhatsApp (OTP, kode verifikasi nomor HP, alert keamanan)
+	// =========================
+	SMSProvider             string `mapstructure:"SMS_PROVIDER"` // none | log | http
+	SMSHTTPEndpoint         string `mapstructure:"SMS_HTTP_ENDPOINT"`
+	SMSHTTPAPIKey           string `mapstructure:"SMS_HTTP_API_KEY"`
+	SMSSenderID             string `mapstructure:"SMS_SENDER_ID"`
+	SMSHTTPTimeout          string `mapstructure:"SMS_HTTP_TIMEOUT"`
+	WhatsAppProvider        string `mapstructure:"WHATSAPP_PROVIDER"` // none | log | cloud
+	WhatsAppEndpoint        string `mapstructure:"WHATSAPP_ENDPOINT"` // https://graph.facebook.com/v19.0/{phone-number-id}/messages
+	WhatsAppToken           string `mapstructure:"WHATSAPP_TOKEN"`
+	WhatsAppTimeout         string `mapstructure:"WHATSAPP_TIMEOUT"`
+	PhoneDefaultCountryCode string `mapstructure:"PHONE_DEFAULT_COUNTRY_CODE"` // untuk nomor lokal "08..."
+	PhoneCodeTTL            string `mapstructure:"PHONE_CODE_TTL"`
+	PhoneResendCooldown     string `mapstructure:"PHONE_RESEND_COOLDOWN"`
+
+	// =========================
+	// Email Outbox (pengiriman background + retry)
+	// =========================
+	EmailOutboxWorkers      int    `mapstructure:"EMAIL_OUTBOX_WORKERS"` // 0 = worker tidak dijalankan di instance ini
+	EmailOutboxPollInterval string `mapstructure:"EMAIL_OUTBOX_POLL_INTERVAL"`
+	EmailOutboxBatchSize    int    `mapstructure:"EMAIL_OUTBOX_BATCH_SIZE"`
+	EmailOutboxMaxAttempts  int    `mapstructure:"EMAIL_OUTBOX_MAX_ATTEMPTS"` // setelah itu status dead
+	EmailOutboxBaseDelay    string `mapstructure:"EMAIL_OUTBOX_BASE_DELAY"`
+	EmailOutboxMaxDelay     string `mapstructure:"EMAIL_OUTBOX_MAX_DELAY"`
+
+	// =========================
+	// Risk-Based Login
+	// =========================
+	RiskStepUpThreshold     int     `mapstructure:"RISK_STEP_UP_THRESHOLD"`
+	RiskBlockThreshold      int     `mapstructure:"RISK_BLOCK_THRESHOLD"`
+	RiskImpossibleTravelKmh float64 `mapstructure:"RISK_IMPOSSIBLE_TRAVEL_KMH"`
+	GeoIPDatabasePath       string  `mapstructure:"GEOIP_DATABASE_PATH"`     // CSV: start_ip,end_ip,country,city,lat,lon
+	IPMaliciousListPath     string  `mapstructure:"IP_MALICIOUS_LIST_PATH"`  // satu IP/CIDR per baris
+	IPSuspiciousListPath    string  `mapstructure:"IP_SUSPICIOUS_LIST_PATH"` // Tor exit, VPN, hosting
+
+	// =========================
+	// Account Lockout
+	// =========================
+	LockoutWindow            string `mapstructure:"LOCKOUT_WINDOW"`
+	LockoutFreeAttempts      int    `mapstructure:"LOCKOUT_FREE_ATTEMPTS"`    // per identifier, sebelum backoff
+	LockoutIPFreeAttempts    int    `mapstructure:"LOCKOUT_IP_FREE_ATTEMPTS"` // per IP, sebelum backoff
+	LockoutBaseDelay         string `mapstructure:"LOCKOUT_BASE_DELAY"`
+	LockoutMaxDelay          string `mapstructure:"LOCKOUT_MAX_DELAY"`
+	LockoutHardLockThreshold int    `mapstructure:"LOCKOUT_HARD_LOCK_THRESHOLD"`
+	LockoutCooldown          string `mapstructure:"LOCKOUT_COOLDOWN"` // "0" = sampai di-unlock manual
+	AccountUnlockURL         string `mapstructure:"ACCOUNT_UNLOCK_URL"`
+
+	// =========================
+	// Email Change
+	// =========================
+	EmailChangeVerifyURL string `mapstructure:"EMAIL_CHANGE_VERIFY_URL"` // link ke alamat baru
+	EmailChangeRevertURL string `mapstructure:"EMAIL_CHANGE_REVERT_URL"` // link ke alamat lama
+	EmailChangeTokenTTL  string `mapstructure:"EMAIL_CHANGE_TOKEN_TTL"`
+	EmailChangeRevertTTL string `mapstructure:"EMAIL_CHANGE_REVERT_TTL"`
+
+	// =========================
+	// Password Reset (forgot password)
+	// =========================
+	PasswordResetMethod      string `mapstructure:"PASSWORD_RESET_METHOD"` // link | otp
+	PasswordResetURL         string `mapstructure:"PASSWORD_RESET_URL"`
+	PasswordResetTTL         string `mapstructure:"PASSWORD_RESET_TTL"`
+	PasswordResetMaxAttempts int    `mapstructure:"PASSWORD_RESET_MAX_ATTEMPTS"` // kode OTP salah sebelum token hangus
+	PasswordResetCooldown    string `mapstructure:"PASSWORD_RESET_COOLDOWN"`
+
+	// =========================
+	// Password Policy (register, change & reset password)
+	// =========================
+	PasswordPolicyMode       string `mapstructure:"PASSWORD_POLICY_MODE"` // composition | nist (tanpa aturan komposisi)
+	PasswordMinLength        int    `mapstructure:"PASSWORD_MIN_LENGTH"`
+	PasswordMaxLength        int    `mapstructure:"PASSWORD_MAX_LENGTH"`
+	PasswordRequireLower     bool   `mapstructure:"PASSWORD_REQUIRE_LOWER"` // Require*: hanya mode composition
+	PasswordRequireUpper     bool   `mapstructure:"PASSWORD_REQUIRE_UPPER"`
+	PasswordRequireDigit     bool   `mapstructure:"PASSWORD_REQUIRE_DIGIT"`
+	PasswordRequireSpecial   bool   `mapstructure:"PASSWORD_REQUIRE_SPECIAL"`
+	PasswordMaxRepeated      int    `mapstructure:"PASSWORD_MAX_REPEATED"`       // karakter sama berturut-turut, 0 = tidak dicek
+	PasswordDisallowUserInfo bool   `mapstructure:"PASSWORD_DISALLOW_USER_INFO"` // tolak password berisi username / email
+	PasswordBlocklistBuiltin bool   `mapstructure:"PASSWORD_BLOCKLIST_BUILTIN"`  // list password umum bawaan
+	PasswordBlocklistPath    string `mapstructure:"PASSWORD_BLOCKLIST_PATH"`     // satu password per baris
+	PasswordBlocklist        string `mapstructure:"PASSWORD_BLOCKLIST"`          // tambahan dipisah koma, mis. nama aplikasi
+	PasswordMinStrength      int    `mapstructure:"PASSWORD_MIN_STRENGTH"`       // skor estimasi 0-4 minimal, 0 = tidak dicek
+	PasswordHistorySize      int    `mapstructure:"PASSWORD_HISTORY_SIZE"`       // password lama yang tidak boleh dipakai lagi, 0 = hanya yang sekarang
+
+	// Password expiry (wajib ganti setelah umur maksimal)
+	PasswordMaxAgeDays          int    `mapstructure:"PASSWORD_MAX_AGE_DAYS"`          // 0 = password tidak pernah expired
+	PasswordExpiryWarningDays   int    `mapstructure:"PASSWORD_EXPIRY_WARNING_DAYS"`   // peringatan dikirim sekian hari sebelum expired, 0 = tanpa peringatan
+	PasswordExpiryCheckInterval string `mapstructure:"PASSWORD_EXPIRY_CHECK_INTERVAL"` // job penanda expired & pengirim peringatan
+
+	// Breached password (Pwned Passwords, k-anonymity)
+	PasswordBreachCheck    string `mapstructure:"PASSWORD_BREACH_CHECK"`     // none | offline | online
+	PasswordBreachDataset  string `mapstructure:"PASSWORD_BREACH_DATASET"`   // offline: folder range file atau file hash terurut
+	PasswordBreachAPIURL   string `mapstructure:"PASSWORD_BREACH_API_URL"`   // online
+	PasswordBreachTimeout  string `mapstructure:"PASSWORD_BREACH_TIMEOUT"`   // online
+	PasswordBreachMinCount int    `mapstructure:"PASSWORD_BREACH_MIN_COUNT"` // kemunculan minimal sebelum ditolak
+
+	// =========================
+	// Email Verification (signup)
+	// =========================
+	EmailVerificationMethod         string `mapstructure:"EMAIL_VERIFICATION_METHOD"` // link | otp
+	EmailVerificationURL            string `mapstructure:"EMAIL_VERIFICATION_URL"`
+	EmailVerificationTTL            string `mapstructure:"EMAIL_VERIFICATION_TTL"`
+	EmailVerificationResendCooldown string `mapstructure:"EMAIL_VERIFICATION_RESEND_COOLDOWN"`
+	EmailVerificationMaxAttempts    int    `mapstructure:"EMAIL_VERIFICATION_MAX_ATTEMPTS"`
+	LoginUnverifiedPolicy           string `mapstructure:"LOGIN_UNVERIFIED_POLICY"` // allow | limit | block
+
+	// =========================
+	// Bot Challenge (CAPTCHA / Proof-of-Work)
+	// =========================
+	ChallengeProvider      string `mapstructure:"CHALLENGE_PROVIDER"`   // none | pow | hcaptcha | turnstile
+	ChallengeVerifyURL     string `mapstructure:"CHALLENGE_VERIFY_URL"` // override siteverify URL
+	ChallengeSecret        string `mapstructure:"CHALLENGE_SECRET"`     // captcha secret / HMAC key PoW
+	ChallengeSiteKey       string `mapstructure:"CHALLENGE_SITE_KEY"`
+	ChallengePoWDifficulty int    `mapstructure:"CHALLENGE_POW_DIFFICULTY"` // leading zero bits
+	ChallengePoWTTL        string `mapstructure:"CHALLENGE_POW_TTL"`
+	ChallengeWindow        string `mapstructure:"CHALLENGE_WINDOW"`
+	ChallengeMaxRequests   int    `mapstructure:"CHALLENGE_MAX_REQUESTS"` // per IP per route sebelum challenge
+	ChallengeMaxFailures   int    `mapstructure:"CHALLENGE_MAX_FAILURES"` // 4xx per IP per route sebelum challenge
+
+	// =========================
+	// Step-Up Authentication
+	// =========================
+	StepUpMaxAge     string `mapstructure:"STEP_UP_MAX_AGE"`    // umur maksimal auth_time untuk operasi sensitif
+	ElevatedTokenTTL string `mapstructure:"ELEVATED_TOKEN_TTL"` // TTL token hasil /auth/reauthenticate
+
+	// =========================
+	// Admin Impersonation
+	// =========================
+	ImpersonationTokenTTL string `mapstructure:"IMPERSONATION_TOKEN_TTL"`
+
 	// =========================
 	// Rate Limiter
 	// =========================
-	RateLimiterEnable bool `mapstructure:"RATE_LIMITER_ENABLE"`
-	RateLimiterRPM    int  `mapstructure:"RATE_LIMITER_RPM"`
+	RateLimiterEnable    bool   `mapstructure:"RATE_LIMITER_ENABLE"`
+	RateLimiterRPM       int    `mapstructure:"RATE_LIMITER_RPM"`       // policy "default"
+	RateLimiterStore     string `mapstructure:"RATE_LIMITER_STORE"`     // memory | redis
+	RateLimiterAlgorithm string `mapstructure:"RATE_LIMITER_ALGORITHM"` // token_bucket | sliding_window | gcra
+	// RATE_LIMIT_POLICIES: "nama=limit/window[:algoritma[:burst]]" dipisah koma,
+	// mis. "login=5/1m:gcra:3,otp=3/5m"
+	RateLimitPolicies map[string]RateLimitPolicyConfig
+
+	// =========================
+	// Quota (harian / bulanan per user, service account, API key)
+	// =========================
+	QuotaEnable              bool   `mapstructure:"QUOTA_ENABLE"`
+	QuotaStore               string `mapstructure:"QUOTA_STORE"`                 // memory | redis
+	QuotaDefaultDailyLimit   int64  `mapstructure:"QUOTA_DEFAULT_DAILY_LIMIT"`   // tanpa plan role, 0 = tanpa batas
+	QuotaDefaultMonthlyLimit int64  `mapstructure:"QUOTA_DEFAULT_MONTHLY_LIMIT"` // tanpa plan role, 0 = tanpa batas
+	QuotaFlushInterval       string `mapstructure:"QUOTA_FLUSH_INTERVAL"`        // flush counter ke Postgres
+
+	// =========================
+	// Redis
+	// =========================
+	RedisHost     string `mapstructure:"REDIS_HOST"` // kosong = Redis tidak dipakai
+	RedisPort     int    `mapstructure:"REDIS_PORT"`
+	RedisPassword string `mapstructure:"REDIS_PASSWORD"`
+	RedisDB       int    `mapstructure:"REDIS_DB"`
 
 	// =========================
 	// Logging
@@ -86,6 +281,16 @@ type Config struct {
 	TokenHmacSecret string `mapstructure:"TOKEN_HMAC_SECRET"`
 }
 
+// =========================
+// Rate Limit Policy Config
+// =========================
+type RateLimitPolicyConfig struct {
+	Limit     int
+	Window    time.Duration
+	Algorithm string // kosong = RATE_LIMITER_ALGORITHM
+	Burst     int
+}
+
 // =========================
 // Argon2id Password Config
 // =========================
@@ -96,6 +301,14 @@ type PasswordConfig struct {
 	SaltLength  uint32 `mapstructure:"PASSWORD_ARGON2_SALT_LENGTH"`
 	KeyLength   uint32 `mapstructure:"PASSWORD_ARGON2_KEY_LENGTH"`
 
+	// dipakai untuk peringatan startup & cmd/argon2-calibrate
+	ExpectedConcurrency int `mapstructure:"PASSWORD_HASH_EXPECTED_CONCURRENCY"` // login / register yang hashing bersamaan
+	MemoryLimitMB       int `mapstructure:"PASSWORD_HASH_MEMORY_LIMIT_MB"`      // 0 = limit memory container (cgroup)
+
+	// pool hashing: total memory argon2id yang boleh dipakai bersamaan, sisanya antri
+	MemoryBudgetMB int    `mapstructure:"PASSWORD_HASH_MEMORY_BUDGET_MB"` // 0 = setengah memory limit, atau EXPECTED_CONCURRENCY x ARGON2_MEMORY
+	QueueTimeout   string `mapstructure:"PASSWORD_HASH_QUEUE_TIMEOUT"`    // lewat ini request ditolak 503
+
 	// Pepper management
 	Peppers              map[int]string
 	CurrentPepperVersion int `mapstructure:"PASSWORD_PEPPER_VERSION"`
@@ -121,15 +334,129 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("DATABASE_CONN_MAX_LIFETIME", "30m")
 
 	viper.SetDefault("RATE_LIMITER_ENABLE", true)
+	viper.SetDefault("RATE_LIMITER_RPM", 120)
+	viper.SetDefault("RATE_LIMITER_STORE", "memory")
+	viper.SetDefault("RATE_LIMITER_ALGORITHM", "sliding_window")
+	viper.SetDefault("RATE_LIMIT_POLICIES", "login=5/1m:gcra:5,otp=3/5m,register=10/1h,password_reset=5/15m,password_strength=30/1m")
+
+	viper.SetDefault("QUOTA_ENABLE", true)
+	viper.SetDefault("QUOTA_STORE", "memory")
+	viper.SetDefault("QUOTA_DEFAULT_DAILY_LIMIT", 0)
+	viper.SetDefault("QUOTA_DEFAULT_MONTHLY_LIMIT", 0)
+	viper.SetDefault("QUOTA_FLUSH_INTERVAL", "1m")
+
+	viper.SetDefault("REDIS_PORT", 6379)
 
 	viper.SetDefault("SECRET_KEY", "secret-key-default")
 
+	viper.SetDefault("SMTP_PORT", "587")
+	viper.SetDefault("OTP_EXPIRY_MINUTES", 5)
+	viper.SetDefault("OTP_MAX_ATTEMPTS", 5)
+	viper.SetDefault("OTP_STORE", "postgres")
+	viper.SetDefault("SMTP_SECURITY", "starttls")
+	viper.SetDefault("SMTP_TIMEOUT", "10s")
+	viper.SetDefault("SMTP_POOL_SIZE", 2)
+	viper.SetDefault("EMAIL_LOCALE", "en")
+	viper.SetDefault("EMAIL_TRANSPORT", "smtp")
+	viper.SetDefault("EMAIL_FILE_DIR", "./tmp/mail")
+	viper.SetDefault("EMAIL_FILE_FORMAT", "eml")
+	viper.SetDefault("EMAIL_HTTP_PROVIDER", "generic")
+	viper.SetDefault("EMAIL_HTTP_TIMEOUT", "10s")
+	viper.SetDefault("DEV_MAIL_CATCHER", false)
+
+	viper.SetDefault("DKIM_ENABLE", false)
+	viper.SetDefault("DKIM_CANONICALIZATION", "relaxed/relaxed")
+
+	viper.SetDefault("SMS_PROVIDER", "none")
+	viper.SetDefault("SMS_HTTP_TIMEOUT", "10s")
+	viper.SetDefault("WHATSAPP_PROVIDER", "none")
+	viper.SetDefault("WHATSAPP_TIMEOUT", "10s")
+	viper.SetDefault("PHONE_DEFAULT_COUNTRY_CODE", "62")
+	viper.SetDefault("PHONE_CODE_TTL", "10m")
+	viper.SetDefault("PHONE_RESEND_COOLDOWN", "1m")
+
+	viper.SetDefault("EMAIL_OUTBOX_WORKERS", 2)
+	viper.SetDefault("EMAIL_OUTBOX_POLL_INTERVAL", "5s")
+	viper.SetDefault("EMAIL_OUTBOX_BATCH_SIZE", 20)
+	viper.SetDefault("EMAIL_OUTBOX_MAX_ATTEMPTS", 8)
+	viper.SetDefault("EMAIL_OUTBOX_BASE_DELAY", "30s")
+	viper.SetDefault("EMAIL_OUTBOX_MAX_DELAY", "1h")
+
+	viper.SetDefault("RISK_STEP_UP_THRESHOLD", 40)
+	viper.SetDefault("RISK_BLOCK_THRESHOLD", 80)
+	viper.SetDefault("RISK_IMPOSSIBLE_TRAVEL_KMH", 900)
+
+	viper.SetDefault("LOCKOUT_WINDOW", "15m")
+	viper.SetDefault("LOCKOUT_FREE_ATTEMPTS", 3)
+	viper.SetDefault("LOCKOUT_IP_FREE_ATTEMPTS", 20)
+	viper.SetDefault("LOCKOUT_BASE_DELAY", "1s")
+	viper.SetDefault("LOCKOUT_MAX_DELAY", "15m")
+	viper.SetDefault("LOCKOUT_HARD_LOCK_THRESHOLD", 10)
+	viper.SetDefault("LOCKOUT_COOLDOWN", "30m")
+	viper.SetDefault("ACCOUNT_UNLOCK_URL", "http://localhost:3000/unlock-account") // halaman frontend → POST /v1/auth/unlock
+
+	viper.SetDefault("EMAIL_CHANGE_VERIFY_URL", "http://localhost:3000/verify-email-change") // halaman frontend → POST /v1/users/me/verify-email
+	viper.SetDefault("EMAIL_CHANGE_REVERT_URL", "http://localhost:3000/revert-email-change") // halaman frontend → POST /v1/auth/email-change/revert
+	viper.SetDefault("EMAIL_CHANGE_TOKEN_TTL", "24h")
+	viper.SetDefault("EMAIL_CHANGE_REVERT_TTL", "168h")
+
+	viper.SetDefault("PASSWORD_RESET_METHOD", "link")
+	viper.SetDefault("PASSWORD_RESET_URL", "http://localhost:3000/reset-password") // halaman frontend → POST /v1/auth/reset-password
+	viper.SetDefault("PASSWORD_RESET_TTL", "1h")
+	viper.SetDefault("PASSWORD_RESET_MAX_ATTEMPTS", 5)
+	viper.SetDefault("PASSWORD_RESET_COOLDOWN", "1m")
+
+	viper.SetDefault("PASSWORD_POLICY_MODE", "composition")
+	viper.SetDefault("PASSWORD_MIN_LENGTH", 12)
+	viper.SetDefault("PASSWORD_MAX_LENGTH", 128)
+	viper.SetDefault("PASSWORD_REQUIRE_LOWER", true)
+	viper.SetDefault("PASSWORD_REQUIRE_UPPER", true)
+	viper.SetDefault("PASSWORD_REQUIRE_DIGIT", true)
+	viper.SetDefault("PASSWORD_REQUIRE_SPECIAL", true)
+	viper.SetDefault("PASSWORD_MAX_REPEATED", 0)
+	viper.SetDefault("PASSWORD_DISALLOW_USER_INFO", true)
+	viper.SetDefault("PASSWORD_BLOCKLIST_BUILTIN", true)
+	viper.SetDefault("PASSWORD_BLOCKLIST_PATH", "")
+	viper.SetDefault("PASSWORD_BLOCKLIST", "")
+	viper.SetDefault("PASSWORD_MIN_STRENGTH", 0)
+	viper.SetDefault("PASSWORD_HISTORY_SIZE", 5)
+	viper.SetDefault("PASSWORD_MAX_AGE_DAYS", 0)
+	viper.SetDefault("PASSWORD_EXPIRY_WARNING_DAYS", 14)
+	viper.SetDefault("PASSWORD_EXPIRY_CHECK_INTERVAL", "1h")
+	viper.SetDefault("PASSWORD_BREACH_CHECK", "none")
+	viper.SetDefault("PASSWORD_BREACH_DATASET", "")
+	viper.SetDefault("PASSWORD_BREACH_API_URL", "https://api.pwnedpasswords.com")
+	viper.SetDefault("PASSWORD_BREACH_TIMEOUT", "3s")
+	viper.SetDefault("PASSWORD_BREACH_MIN_COUNT", 1)
+
+	viper.SetDefault("EMAIL_VERIFICATION_METHOD", "link")
+	viper.SetDefault("EMAIL_VERIFICATION_URL", "http://localhost:3000/verify-email") // halaman frontend → POST /v1/auth/verify-email
+	viper.SetDefault("EMAIL_VERIFICATION_TTL", "24h")
+	viper.SetDefault("EMAIL_VERIFICATION_RESEND_COOLDOWN", "1m")
+	viper.SetDefault("EMAIL_VERIFICATION_MAX_ATTEMPTS", 5)
+	viper.SetDefault("LOGIN_UNVERIFIED_POLICY", "limit")
+
+	viper.SetDefault("CHALLENGE_PROVIDER", "pow")
+	viper.SetDefault("CHALLENGE_POW_DIFFICULTY", 20)
+	viper.SetDefault("CHALLENGE_POW_TTL", "2m")
+	viper.SetDefault("CHALLENGE_WINDOW", "10m")
+	viper.SetDefault("CHALLENGE_MAX_REQUESTS", 20)
+	viper.SetDefault("CHALLENGE_MAX_FAILURES", 3)
+
+	viper.SetDefault("STEP_UP_MAX_AGE", "5m")
+	viper.SetDefault("ELEVATED_TOKEN_TTL", "5m")
+	viper.SetDefault("IMPERSONATION_TOKEN_TTL", "15m")
+
 	// Argon2id defaults (recommended)
 	viper.SetDefault("PASSWORD_ARGON2_MEMORY", 64*1024) // 64 MB
 	viper.SetDefault("PASSWORD_ARGON2_ITERATIONS", 4)
 	viper.SetDefault("PASSWORD_ARGON2_PARALLELISM", 1)
 	viper.SetDefault("PASSWORD_ARGON2_SALT_LENGTH", 16)
 	viper.SetDefault("PASSWORD_ARGON2_KEY_LENGTH", 32)
+	viper.SetDefault("PASSWORD_HASH_EXPECTED_CONCURRENCY", 16)
+	viper.SetDefault("PASSWORD_HASH_MEMORY_LIMIT_MB", 0)
+	viper.SetDefault("PASSWORD_HASH_MEMORY_BUDGET_MB", 0)
+	viper.SetDefault("PASSWORD_HASH_QUEUE_TIMEOUT", "3s")
 	viper.SetDefault("PASSWORD_PEPPER_VERSION", 1)
 
 	_ = viper.ReadInConfig()
@@ -152,6 +479,27 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	// =========================
+	// Parse Rate Limit Policies
+	// =========================
+	cfg.RateLimitPolicies = map[string]RateLimitPolicyConfig{
+		"default": {Limit: cfg.RateLimiterRPM, Window: time.Minute},
+	}
+	if raw := viper.GetString("RATE_LIMIT_POLICIES"); raw != "" {
+		for _, entry := range strings.Split(raw, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+
+			name, policy, err := parseRateLimitPolicy(entry)
+			if err != nil {
+				return nil, err
+			}
+			cfg.RateLimitPolicies[name] = policy
+		}
+	}
+
 	// =========================
 	// Load Peppers
 	// =========================
@@ -176,3 +524,39 @@ func (c *Config) IsDevelopment() bool {
 func (c *Config) IsProduction() bool {
 	return c.Environment == "prod" || c.Environment == "production"
 }
+
+// parseRateLimitPolicy: "nama=limit/window[:algoritma[:burst]]"
+func parseRateLimitPolicy(entry string) (string, RateLimitPolicyConfig, error) {
+	var policy RateLimitPolicyConfig
+
+	name, spec, ok := strings.Cut(entry, "=")
+	if !ok || name == "" {
+		return "", policy, fmt.Errorf("invalid RATE_LIMIT_POLICIES entry %q", entry)
+	}
+
+	parts := strings.Split(spec, ":")
+
+	limit, window, ok := strings.Cut(parts[0], "/")
+	if !ok {
+		return "", policy, fmt.Errorf("invalid rate limit %q: expected limit/window", entry)
+	}
+
+	var err error
+	if policy.Limit, err = strconv.Atoi(limit); err != nil || policy.Limit <= 0 {
+		return "", policy, fmt.Errorf("invalid rate limit %q: bad limit", entry)
+	}
+	if policy.Window, err = time.ParseDuration(window); err != nil || policy.Window <= 0 {
+		return "", policy, fmt.Errorf("invalid rate limit %q: bad window", entry)
+	}
+
+	if len(parts) > 1 {
+		policy.Algorithm = parts[1]
+	}
+	if len(parts) > 2 {
+		if policy.Burst, err = strconv.Atoi(parts[2]); err != nil || policy.Burst < 0 {
+			return "", policy, fmt.Errorf("invalid rate limit %q: bad burst", entry)
+		}
+	}
+
+	return strings.TrimSpace(name), policy, nil
+}
@@ -3,15 +3,28 @@ package dto
 import "time"
 
 type LoginRequest struct {
-	Identifier string `json:"identifier" validate:"required,min=3,max=100"` // email or username
-	Password   string `json:"password" validate:"required,min=8,max=100"`
-	DeviceName string `json:"device_name,omitempty" validate:"max=100"` // optional
+	Identifier string `json:"identifier" validate:"required,min=3,max=100"`           // email or username
+	Password   string `json:"password" validate:"required,max=1024"`                  // aturan detail dicek password policy
+	DeviceName string `json:"device_name,omitempty" validate:"max=100"`               // optional
+	OTP        string `json:"otp,omitempty" validate:"omitempty,numeric,min=6,max=8"` // step-up code, hanya jika diminta
 }
 
 type LoginResponse struct {
 	AccessToken string    `json:"access_token"`
 	ExpiresAt   time.Time `json:"expires_at"`
 	User        UserInfo  `json:"user"`
+
+	// PasswordChangeRequired: arahkan user ke form ganti password, endpoint lain ditolak
+	PasswordChangeRequired bool `json:"password_change_required,omitempty"`
+	// PasswordExpiresAt: hanya jika PASSWORD_MAX_AGE_DAYS aktif
+	PasswordExpiresAt *time.Time `json:"password_expires_at,omitempty"`
+}
+
+// Response ketika login berisiko dan perlu verifikasi tambahan (OTP / TOTP)
+type StepUpRequiredResponse struct {
+	Message        string `json:"message"`
+	StepUpRequired bool   `json:"step_up_required"`
+	Method         string `json:"method"` // totp | email_otp | sms_otp | whatsapp_otp
 }
 
 type UserInfo struct {
@@ -58,9 +71,12 @@ type ForgotPasswordResponse struct {
 	Message string `json:"message"`
 }
 
+// ResetPasswordRequest: token (metode link) atau email + code (metode otp)
 type ResetPasswordRequest struct {
-	Token    string `json:"token" validate:"required,min=32"` // panjang token cukup besar
-	Password string `json:"password" validate:"required,min=8,max=100"`
+	Token    string `json:"token,omitempty" validate:"required_without=Code,omitempty,min=32"` // panjang token cukup besar
+	Email    string `json:"email,omitempty" validate:"required_with=Code,omitempty,email"`
+	Code     string `json:"code,omitempty" validate:"required_without=Token,omitempty,numeric,len=6"`
+	Password string `json:"password" validate:"required,max=1024"` // aturan detail dicek password policy
 }
 
 type ResetPasswordResponse struct {
@@ -68,16 +84,96 @@ type ResetPasswordResponse struct {
 }
 
 type ChangePasswordRequest struct {
-	CurrentPassword string `json:"current_password" validate:"required"`
-	NewPassword     string `json:"new_password" validate:"required,min=8,max=100"`
+	CurrentPassword string `json:"current_password" validate:"required,max=1024"`
+	NewPassword     string `json:"new_password" validate:"required,max=1024"` // aturan detail dicek password policy
 }
 
 type ChangePasswordResponse struct {
 	Message string `json:"message"`
 }
 
+// AdminResetPasswordRequest: RequireChange = user wajib ganti password saat login berikutnya
+type AdminResetPasswordRequest struct {
+	NewPassword   string `json:"new_password" validate:"required,max=1024"` // aturan detail dicek password policy
+	RequireChange bool   `json:"require_change"`
+}
+
+// RequirePasswordChangeBulkRequest: daftar user, atau All = semua user kecuali admin yang menjalankan
+type RequirePasswordChangeBulkRequest struct {
+	UserIDs []string `json:"user_ids,omitempty" validate:"required_without=All,max=1000,dive,required"`
+	All     bool     `json:"all,omitempty"`
+}
+
+type RequirePasswordChangeBulkResponse struct {
+	Message string `json:"message"`
+	Count   int64  `json:"count"` // user yang baru ditandai
+}
+
+// PasswordPolicyResponse: aturan password untuk ditampilkan frontend (register, reset, change)
+type PasswordPolicyResponse struct {
+	Mode             string                 `json:"mode"` // composition | nist
+	MinLength        int                    `json:"min_length"`
+	MaxLength        int                    `json:"max_length,omitempty"`
+	RequireLower     bool                   `json:"require_lowercase"`
+	RequireUpper     bool                   `json:"require_uppercase"`
+	RequireDigit     bool                   `json:"require_digit"`
+	RequireSpecial   bool                   `json:"require_special"`
+	MaxRepeated      int                    `json:"max_repeated,omitempty"`
+	DisallowUserInfo bool                   `json:"disallow_user_info"`
+	Blocklist        bool                   `json:"blocklist"`
+	MinStrength      int                    `json:"min_strength,omitempty"` // skor 0-4, lihat /auth/password-strength
+	MaxAgeDays       int                    `json:"max_age_days,omitempty"` // 0 = password tidak pernah expired
+	Rules            []PasswordRuleResponse `json:"rules"`
+}
+
+// PasswordRuleResponse: Code sama dengan key di ErrorResponse.Errors saat password ditolak
+type PasswordRuleResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// PasswordStrengthRequest: username / email opsional, ikut dinilai kalau diisi
+type PasswordStrengthRequest struct {
+	Password string `json:"password" validate:"required,max=1024"`
+	Username string `json:"username,omitempty" validate:"omitempty,max=100"`
+	Email    string `json:"email,omitempty" validate:"omitempty,max=255"`
+}
+
+// PasswordStrengthResponse: Score 0 (sangat lemah) - 4 (sangat kuat)
+type PasswordStrengthResponse struct {
+	Score        int      `json:"score"`
+	GuessesLog10 float64  `json:"guesses_log10"`
+	CrackTime    string   `json:"crack_time"`
+	Warning      string   `json:"warning,omitempty"`
+	Suggestions  []string `json:"suggestions"`
+	MinScore     int      `json:"min_score,omitempty"` // skor minimal policy, 0 = tidak diwajibkan
+}
+
+// PasswordHashingStatsResponse: counter sejak proses start, waktu dalam milidetik
+type PasswordHashingStatsResponse struct {
+	MemoryBudgetMiB uint64                              `json:"memory_budget_mib"`
+	MemoryInUseMiB  uint64                              `json:"memory_in_use_mib"`
+	Running         int64                               `json:"running"`
+	Waiting         int64                               `json:"waiting"`
+	Acquired        uint64                              `json:"acquired"`
+	Rejected        uint64                              `json:"rejected"` // antri lewat PASSWORD_HASH_QUEUE_TIMEOUT → 503
+	Canceled        uint64                              `json:"canceled"`
+	WaitAvgMs       float64                             `json:"wait_avg_ms"`
+	WaitMaxMs       float64                             `json:"wait_max_ms"`
+	WaitBuckets     []PasswordHashingWaitBucketResponse `json:"wait_buckets"`
+}
+
+// PasswordHashingWaitBucketResponse: kumulatif, Count = request yang menunggu <= LeMs
+type PasswordHashingWaitBucketResponse struct {
+	LeMs  int64  `json:"le_ms"`
+	Count uint64 `json:"count"`
+}
+
+// VerifyEmailRequest: token (metode link) atau email + code (metode otp)
 type VerifyEmailRequest struct {
-	Token string `json:"token" validate:"required,min=32"`
+	Token string `json:"token,omitempty" validate:"required_without=Code,omitempty,min=32"`
+	Email string `json:"email,omitempty" validate:"required_with=Code,omitempty,email"`
+	Code  string `json:"code,omitempty" validate:"required_without=Token,omitempty,numeric,len=6"`
 }
 
 type VerifyEmailResponse struct {
@@ -91,3 +187,78 @@ type ResendVerificationRequest struct {
 type ResendVerificationResponse struct {
 	Message string `json:"message"`
 }
+
+// EmailNotVerifiedResponse: login/akses ditolak karena email belum diverifikasi
+type EmailNotVerifiedResponse struct {
+	Message string `json:"message"`
+	Code    string `json:"code"` // "email_unverified"
+}
+
+// ReauthenticateRequest: salah satu dari password atau code wajib diisi
+type ReauthenticateRequest struct {
+	Password string `json:"password,omitempty" validate:"required_without=Code,omitempty,max=1024"`
+	Code     string `json:"code,omitempty" validate:"required_without=Password,omitempty,numeric,min=6,max=8"`
+}
+
+type ReauthenticateResponse struct {
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	AuthTime    time.Time `json:"auth_time"`
+	ACR         string    `json:"acr"`
+}
+
+type ImpersonateRequest struct {
+	Reason string `json:"reason" validate:"required,min=5,max=255"` // wajib, masuk audit log
+}
+
+type ImpersonateResponse struct {
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	ActorID     string    `json:"actor_id"`
+	SubjectID   string    `json:"subject_id"`
+}
+
+// LoginLockoutResponse membedakan throttling sementara (429) dan akun terkunci (423)
+type LoginLockoutResponse struct {
+	Message     string     `json:"message"`
+	Code        string     `json:"code"`                   // "throttled" | "account_locked"
+	RetryAfter  int        `json:"retry_after,omitempty"`  // detik, hanya untuk throttled
+	LockedUntil *time.Time `json:"locked_until,omitempty"` // nil = sampai di-unlock
+}
+
+type LockAccountRequest struct {
+	Reason   string `json:"reason" validate:"required,min=3,max=255"`
+	Duration string `json:"duration,omitempty" validate:"omitempty"` // e.g. "24h", kosong = sampai di-unlock
+}
+
+type UnlockAccountRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=255"`
+}
+
+type UnlockWithTokenRequest struct {
+	Token string `json:"token" validate:"required,min=32"`
+}
+
+type LoginHistoryItem struct {
+	UserID     string    `json:"user_id,omitempty"`
+	Identifier string    `json:"identifier"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	Success    bool      `json:"success"`
+	Outcome    string    `json:"outcome"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type LoginHistoryResponse struct {
+	Items []LoginHistoryItem `json:"items"`
+	Total int64              `json:"total"`
+}
+
+// ChallengeResponse: provider "pow" → token + difficulty, provider "captcha" → site_key
+type ChallengeResponse struct {
+	Provider   string     `json:"provider"`
+	SiteKey    string     `json:"site_key,omitempty"`
+	Token      string     `json:"token,omitempty"`
+	Difficulty int        `json:"difficulty,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
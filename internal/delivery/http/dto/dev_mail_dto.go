@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+type DevMailSummaryResponse struct {
+	ID      uint64    `json:"id"`
+	From    string    `json:"from"`
+	To      []string  `json:"to"`
+	Subject string    `json:"subject"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+// DevMailResponse: codes / links / tokens diambil dari body plain-text
+type DevMailResponse struct {
+	DevMailSummaryResponse
+	HTML   string   `json:"html"`
+	Text   string   `json:"text"`
+	Codes  []string `json:"codes"`
+	Links  []string `json:"links"`
+	Tokens []string `json:"tokens"`
+}
+
+type DevMailListResponse struct {
+	Items []DevMailSummaryResponse `json:"items"`
+}
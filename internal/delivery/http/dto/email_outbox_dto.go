@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+type EmailOutboxMessageResponse struct {
+	ID            string     `json:"id"`
+	Kind          string     `json:"kind"`
+	Recipient     string     `json:"recipient"`
+	Status        string     `json:"status"` // pending | processing | sent | dead
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     string     `json:"last_error,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type EmailOutboxListResponse struct {
+	Items []EmailOutboxMessageResponse `json:"items"`
+}
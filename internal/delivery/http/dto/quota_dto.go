@@ -0,0 +1,51 @@
+package dto
+
+import "time"
+
+// ===== RESPONSE =====
+
+type QuotaWindowResponse struct {
+	Period    string    `json:"period"` // "daily" | "monthly"
+	Limit     int64     `json:"limit"`  // 0 = tanpa batas
+	Used      int64     `json:"used"`
+	Remaining int64     `json:"remaining"` // -1 = tanpa batas
+	ResetAt   time.Time `json:"reset_at"`
+}
+
+type QuotaUsageResponse struct {
+	SubjectType string                `json:"subject_type"`
+	SubjectID   string                `json:"subject_id"`
+	Plan        string                `json:"plan"`
+	Source      string                `json:"source"` // "default" | "plan" | "override"
+	Exhausted   bool                  `json:"exhausted"`
+	Windows     []QuotaWindowResponse `json:"windows"`
+}
+
+type QuotaPlanResponse struct {
+	RoleID       string    `json:"role_id"`
+	Name         string    `json:"name"`
+	DailyLimit   int64     `json:"daily_limit"`
+	MonthlyLimit int64     `json:"monthly_limit"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type ListQuotaPlanResponse struct {
+	Items []QuotaPlanResponse `json:"items"`
+	Total int64               `json:"total"`
+}
+
+// ===== REQUEST =====
+
+type SetQuotaPlanRequest struct {
+	Name         string `json:"name" validate:"required,min=2,max=64"`
+	DailyLimit   int64  `json:"daily_limit" validate:"min=0"`   // 0 = tanpa batas
+	MonthlyLimit int64  `json:"monthly_limit" validate:"min=0"` // 0 = tanpa batas
+}
+
+// SetQuotaOverrideRequest: limit kosong = ikut plan, 0 = tanpa batas
+type SetQuotaOverrideRequest struct {
+	DailyLimit   *int64     `json:"daily_limit,omitempty" validate:"omitempty,min=0"`
+	MonthlyLimit *int64     `json:"monthly_limit,omitempty" validate:"omitempty,min=0"`
+	Reason       string     `json:"reason" validate:"required,min=3,max=255"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+}
@@ -8,6 +8,10 @@ type UserProfileResponse struct {
 	Username      string    `json:"username"`
 	EmailVerified bool      `json:"email_verified"`
 	CreatedAt     time.Time `json:"created_at"`
+
+	Phone               string `json:"phone,omitempty"` // masked
+	PhoneVerified       bool   `json:"phone_verified"`
+	NotificationChannel string `json:"notification_channel"` // email | sms | whatsapp
 }
 
 type UpdateProfileRequest struct {
@@ -18,6 +22,44 @@ type UpdateProfileResponse struct {
 	Message string `json:"message"`
 }
 
+// Email change
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required,max=1024"`
+}
+
+type ChangeEmailResponse struct {
+	Message      string    `json:"message"`
+	PendingEmail string    `json:"pending_email"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// Phone & channel notifikasi
+type SetPhoneRequest struct {
+	Phone   string `json:"phone" validate:"required,min=6,max=32"`
+	Channel string `json:"channel" validate:"required,oneof=sms whatsapp"` // channel untuk kode verifikasi
+}
+
+type SetPhoneResponse struct {
+	Message   string    `json:"message"`
+	Phone     string    `json:"phone"` // masked
+	Channel   string    `json:"channel"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type VerifyPhoneRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+type NotificationChannelRequest struct {
+	Channel string `json:"channel" validate:"required,oneof=email sms whatsapp"`
+}
+
+// EmailTokenRequest: token dari link verifikasi / revert
+type EmailTokenRequest struct {
+	Token string `json:"token" validate:"required,min=32"`
+}
+
 type DeleteAccountResponse struct {
 	Message string `json:"message"`
 }
@@ -26,7 +68,7 @@ type DeleteAccountResponse struct {
 type CreateUserRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Username string `json:"username" validate:"required,min=3"`
-	Password string `json:"password" validate:"required,min=8"`
+	Password string `json:"password" validate:"required,max=1024"` // aturan detail dicek password policy
 }
 
 type CreateUserResponse struct {
@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/dto"
+	"github.com/dhanarrizky/Golang-template/internal/usecase/auth"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type AccountLockHandler struct {
+	accountLockUsecase auth.AccountLockUsecase
+	validate           *validator.Validate
+}
+
+func NewAccountLockHandler(
+	accountLockUsecase auth.AccountLockUsecase,
+	validate *validator.Validate,
+) *AccountLockHandler {
+	return &AccountLockHandler{
+		accountLockUsecase: accountLockUsecase,
+		validate:           validate,
+	}
+}
+
+// POST /users/:id/lock (admin only)
+func (h *AccountLockHandler) Lock(c *gin.Context) {
+	var req dto.LockAccountRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	var duration time.Duration
+	if req.Duration != "" {
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil || d <= 0 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Message: "Validation failed",
+				Errors:  map[string]string{"Duration": "duration"},
+			})
+			return
+		}
+		duration = d
+	}
+
+	err := h.accountLockUsecase.Lock(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.Param("id"),
+		req.Reason,
+		duration,
+	)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Account locked",
+	})
+}
+
+// POST /users/:id/unlock (admin only)
+func (h *AccountLockHandler) Unlock(c *gin.Context) {
+	var req dto.UnlockAccountRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	err := h.accountLockUsecase.Unlock(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.Param("id"),
+		req.Reason,
+	)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Account unlocked",
+	})
+}
+
+// POST /auth/unlock (link dari email lockout)
+func (h *AccountLockHandler) UnlockWithToken(c *gin.Context) {
+	var req dto.UnlockWithTokenRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	if err := h.accountLockUsecase.UnlockWithToken(c.Request.Context(), req.Token); err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Account unlocked. You can sign in again.",
+	})
+}
+
+func (h *AccountLockHandler) bind(c *gin.Context, req any) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Invalid request",
+		})
+		return false
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		errs := map[string]string{}
+		for _, e := range err.(validator.ValidationErrors) {
+			errs[e.Field()] = e.Tag()
+		}
+
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Validation failed",
+			Errors:  errs,
+		})
+		return false
+	}
+
+	return true
+}
+
+func (h *AccountLockHandler) error(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, auth.ErrLockUserNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, auth.ErrInvalidUnlockToken),
+		errors.Is(err, auth.ErrLockReasonIsRequired):
+		status = http.StatusBadRequest
+	case errors.Is(err, auth.ErrCannotLockSelf):
+		status = http.StatusForbidden
+	case errors.Is(err, auth.ErrAccountNotLocked):
+		status = http.StatusConflict
+	}
+
+	c.JSON(status, dto.ErrorResponse{
+		Message: err.Error(),
+	})
+}
@@ -1,9 +1,13 @@
 package auth
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/dhanarrizky/Golang-template/internal/delivery/http/dto"
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/handlers/httperr"
 	"github.com/dhanarrizky/Golang-template/internal/usecase/auth"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -52,15 +56,72 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		c.Request.Context(),
 		req.Identifier,
 		req.Password,
+		req.OTP,
+		auth.LoginClient{
+			IPAddress: c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		},
 	)
 
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+		if httperr.PasswordHasherBusy(c, err) {
+			return
+		}
+
+		var throttled *auth.LoginThrottledError
+		var locked *auth.AccountLockedError
+
+		switch {
+		case errors.As(err, &throttled):
+			retryAfter := int(math.Ceil(throttled.RetryAfter.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSON(http.StatusTooManyRequests, dto.LoginLockoutResponse{
+				Message:    err.Error(),
+				Code:       "throttled",
+				RetryAfter: retryAfter,
+			})
+			return
+		case errors.As(err, &locked):
+			c.JSON(http.StatusLocked, dto.LoginLockoutResponse{
+				Message:     err.Error(),
+				Code:        "account_locked",
+				LockedUntil: locked.LockedUntil,
+			})
+			return
+		case errors.Is(err, auth.ErrEmailNotVerified):
+			c.JSON(http.StatusForbidden, dto.EmailNotVerifiedResponse{
+				Message: err.Error(),
+				Code:    "email_unverified",
+			})
+			return
+		}
+
+		status := http.StatusUnauthorized
+		switch {
+		case errors.Is(err, auth.ErrLoginBlocked):
+			status = http.StatusForbidden
+		case errors.Is(err, auth.ErrAccountLocked):
+			status = http.StatusLocked
+		case errors.Is(err, auth.ErrTooManyAttempts):
+			status = http.StatusTooManyRequests
+		}
+
+		c.JSON(status, dto.ErrorResponse{
 			Message: err.Error(),
 		})
 		return
 	}
 
+	// Login berisiko → belum ada token, client harus kirim ulang dengan otp
+	if result.StepUpRequired {
+		c.JSON(http.StatusUnauthorized, dto.StepUpRequiredResponse{
+			Message:        "Additional verification required",
+			StepUpRequired: true,
+			Method:         result.StepUpMethod,
+		})
+		return
+	}
+
 	// Refresh token = HTTP concern → BOLEH di handler
 	c.SetCookie(
 		"refresh_token",
@@ -82,6 +143,8 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			Roles:         result.Roles,
 			EmailVerified: result.EmailVerified,
 		},
+		PasswordChangeRequired: result.PasswordChangeRequired,
+		PasswordExpiresAt:      result.PasswordExpiresAt,
 	})
 }
 
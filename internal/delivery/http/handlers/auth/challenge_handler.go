@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/dto"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+	"github.com/gin-gonic/gin"
+)
+
+type ChallengeHandler struct {
+	verifier ports.ChallengeVerifier // nil = challenge dimatikan
+}
+
+func NewChallengeHandler(verifier ports.ChallengeVerifier) *ChallengeHandler {
+	return &ChallengeHandler{
+		verifier: verifier,
+	}
+}
+
+// GET /auth/challenge
+func (h *ChallengeHandler) Issue(c *gin.Context) {
+	if h.verifier == nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Message: "Challenge is disabled",
+		})
+		return
+	}
+
+	ch, err := h.verifier.Issue(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{
+			Message: "Challenge unavailable",
+		})
+		return
+	}
+
+	resp := dto.ChallengeResponse{
+		Provider:   ch.Provider,
+		SiteKey:    ch.SiteKey,
+		Token:      ch.Token,
+		Difficulty: ch.Difficulty,
+	}
+	if !ch.ExpiresAt.IsZero() {
+		resp.ExpiresAt = &ch.ExpiresAt
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
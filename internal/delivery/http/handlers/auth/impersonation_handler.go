@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/dto"
+	"github.com/dhanarrizky/Golang-template/internal/usecase/auth"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type ImpersonationHandler struct {
+	impersonationUsecase auth.ImpersonationUsecase
+	validate             *validator.Validate
+}
+
+func NewImpersonationHandler(
+	impersonationUsecase auth.ImpersonationUsecase,
+	validate *validator.Validate,
+) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		impersonationUsecase: impersonationUsecase,
+		validate:             validate,
+	}
+}
+
+// POST /users/:id/impersonate (admin only)
+func (h *ImpersonationHandler) Start(c *gin.Context) {
+	if c.GetBool("impersonating") {
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{
+			Message: auth.ErrNestedImpersonation.Error(),
+		})
+		return
+	}
+
+	var req dto.ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Invalid request",
+		})
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		errs := map[string]string{}
+		for _, e := range err.(validator.ValidationErrors) {
+			errs[e.Field()] = e.Tag()
+		}
+
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Validation failed",
+			Errors:  errs,
+		})
+		return
+	}
+
+	result, err := h.impersonationUsecase.Start(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.Param("id"),
+		req.Reason,
+		auth.LoginClient{
+			IPAddress: c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		},
+	)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, auth.ErrCannotImpersonateAdmin),
+			errors.Is(err, auth.ErrCannotImpersonateSelf):
+			status = http.StatusForbidden
+		case errors.Is(err, auth.ErrImpersonationTargetNotFound),
+			errors.Is(err, auth.ErrImpersonationIDDecode):
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, dto.ErrorResponse{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ImpersonateResponse{
+		AccessToken: result.AccessToken,
+		ExpiresAt:   result.ExpiresAt,
+		ActorID:     result.ActorID,
+		SubjectID:   result.SubjectID,
+	})
+}
+
+// POST /auth/impersonation/stop (pakai token impersonation)
+func (h *ImpersonationHandler) Stop(c *gin.Context) {
+	err := h.impersonationUsecase.Stop(
+		c.Request.Context(),
+		c.GetString("actorID"),
+		c.GetString("user_id"),
+		c.GetString("tokenID"),
+		auth.LoginClient{
+			IPAddress: c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		},
+	)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, auth.ErrNotImpersonating) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, dto.ErrorResponse{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Impersonation ended. The impersonation token is no longer accepted.",
+	})
+}
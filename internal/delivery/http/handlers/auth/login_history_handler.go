@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/dto"
+	"github.com/dhanarrizky/Golang-template/internal/usecase/auth"
+	"github.com/gin-gonic/gin"
+)
+
+type LoginHistoryHandler struct {
+	loginHistoryUsecase auth.LoginHistoryUsecase
+}
+
+func NewLoginHistoryHandler(loginHistoryUsecase auth.LoginHistoryUsecase) *LoginHistoryHandler {
+	return &LoginHistoryHandler{
+		loginHistoryUsecase: loginHistoryUsecase,
+	}
+}
+
+// GET /users/me/login-history?limit=&offset=
+func (h *LoginHistoryHandler) Me(c *gin.Context) {
+	limit, offset, ok := h.paging(c)
+	if !ok {
+		return
+	}
+
+	entries, total, err := h.loginHistoryUsecase.ListMine(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		limit,
+		offset,
+	)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toLoginHistoryResponse(entries, total))
+}
+
+// GET /login-attempts?user_id=&ip=&outcome=&from=&to=&limit=&offset= (admin only)
+func (h *LoginHistoryHandler) Search(c *gin.Context) {
+	limit, offset, ok := h.paging(c)
+	if !ok {
+		return
+	}
+
+	query := auth.LoginHistoryQuery{
+		UserID:    c.Query("user_id"),
+		IPAddress: c.Query("ip"),
+		Outcome:   c.Query("outcome"),
+		Limit:     limit,
+		Offset:    offset,
+	}
+
+	errs := map[string]string{}
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			errs["from"] = "rfc3339"
+		}
+		query.From = &t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			errs["to"] = "rfc3339"
+		}
+		query.To = &t
+	}
+	if len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Validation failed",
+			Errors:  errs,
+		})
+		return
+	}
+
+	entries, total, err := h.loginHistoryUsecase.Search(c.Request.Context(), query)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toLoginHistoryResponse(entries, total))
+}
+
+func (h *LoginHistoryHandler) paging(c *gin.Context) (int, int, bool) {
+	limit, offset := 0, 0
+	errs := map[string]string{}
+
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			errs["limit"] = "min"
+		}
+		limit = n
+	}
+	if v := c.Query("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			errs["offset"] = "min"
+		}
+		offset = n
+	}
+
+	if len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Validation failed",
+			Errors:  errs,
+		})
+		return 0, 0, false
+	}
+
+	return limit, offset, true
+}
+
+func (h *LoginHistoryHandler) error(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, auth.ErrLoginHistoryUserIDDecode) ||
+		errors.Is(err, auth.ErrInvalidLoginHistoryRange) {
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, dto.ErrorResponse{
+		Message: err.Error(),
+	})
+}
+
+func toLoginHistoryResponse(entries []*auth.LoginHistoryEntry, total int64) dto.LoginHistoryResponse {
+	items := make([]dto.LoginHistoryItem, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, dto.LoginHistoryItem{
+			UserID:     e.UserID,
+			Identifier: e.Identifier,
+			IPAddress:  e.IPAddress,
+			UserAgent:  e.UserAgent,
+			Success:    e.Success,
+			Outcome:    e.Outcome,
+			CreatedAt:  e.CreatedAt,
+		})
+	}
+
+	return dto.LoginHistoryResponse{
+		Items: items,
+		Total: total,
+	}
+}
@@ -1,10 +1,16 @@
 package auth
 
 import (
+	"errors"
+	"log"
 	"net/http"
 
 	"github.com/dhanarrizky/Golang-template/internal/delivery/http/dto"
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/handlers/httperr"
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	userPorts "github.com/dhanarrizky/Golang-template/internal/ports/users"
 	"github.com/dhanarrizky/Golang-template/internal/usecase/auth"
+	passwordUC "github.com/dhanarrizky/Golang-template/internal/usecase/password"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -12,17 +18,88 @@ import (
 
 type PasswordHandler struct {
 	passwordUsecase auth.PasswordUsecase
+	policyUsecase   passwordUC.PasswordPolicyUsecase
+	hashingMonitor  userPorts.PasswordHashingMonitor // nil = metrik tidak tersedia
 	validate        *validator.Validate
 }
 
-func NewPasswordHandler(passwordUsecase auth.PasswordUsecase, validate *validator.Validate) *PasswordHandler {
+func NewPasswordHandler(
+	passwordUsecase auth.PasswordUsecase,
+	policyUsecase passwordUC.PasswordPolicyUsecase,
+	hashingMonitor userPorts.PasswordHashingMonitor,
+	validate *validator.Validate,
+) *PasswordHandler {
 	return &PasswordHandler{
 		passwordUsecase: passwordUsecase,
+		policyUsecase:   policyUsecase,
+		hashingMonitor:  hashingMonitor,
 		validate:        validate,
 	}
 }
 
-// POST /auth/password/forgot
+// GET /auth/password-policy
+func (h *PasswordHandler) Policy(c *gin.Context) {
+	policy := h.policyUsecase.Policy()
+
+	rules := make([]dto.PasswordRuleResponse, 0)
+	for _, r := range policy.Rules() {
+		rules = append(rules, dto.PasswordRuleResponse{Code: r.Code, Message: r.Message})
+	}
+
+	composition := policy.Mode == valueobjects.PasswordPolicyModeComposition
+	c.JSON(http.StatusOK, dto.PasswordPolicyResponse{
+		Mode:             policy.Mode,
+		MinLength:        policy.MinLength,
+		MaxLength:        policy.MaxLength,
+		RequireLower:     composition && policy.RequireLower,
+		RequireUpper:     composition && policy.RequireUpper,
+		RequireDigit:     composition && policy.RequireDigit,
+		RequireSpecial:   composition && policy.RequireSpecial,
+		MaxRepeated:      policy.MaxRepeated,
+		DisallowUserInfo: policy.DisallowUserInfo,
+		Blocklist:        len(policy.Blocklist) > 0,
+		MinStrength:      policy.MinStrength,
+		MaxAgeDays:       int(policy.MaxAge.Hours() / 24),
+		Rules:            rules,
+	})
+}
+
+// POST /auth/password-strength
+// Meter kekuatan password untuk form register / reset / change; tidak menyimpan apa pun
+func (h *PasswordHandler) Strength(c *gin.Context) {
+	var req dto.PasswordStrengthRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request"})
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Validation failed"})
+		return
+	}
+
+	strength := h.policyUsecase.Estimate(
+		c.Request.Context(),
+		req.Password,
+		valueobjects.PasswordUserInfo{Username: req.Username, Email: req.Email},
+	)
+
+	suggestions := strength.Suggestions
+	if suggestions == nil {
+		suggestions = []string{}
+	}
+
+	c.JSON(http.StatusOK, dto.PasswordStrengthResponse{
+		Score:        strength.Score,
+		GuessesLog10: strength.GuessesLog10,
+		CrackTime:    strength.CrackTime,
+		Warning:      strength.Warning,
+		Suggestions:  suggestions,
+		MinScore:     h.policyUsecase.Policy().MinStrength,
+	})
+}
+
+// POST /auth/forgot-password
 func (h *PasswordHandler) Forgot(c *gin.Context) {
 	var req dto.ForgotPasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -36,14 +113,14 @@ func (h *PasswordHandler) Forgot(c *gin.Context) {
 	}
 
 	// Selalu return success (anti-enumeration)
-	if err := h.passwordUsecase.Forgot(c.Request.Context(), req.Email); err != nil {
-		// Log error internally
+	if err := h.passwordUsecase.Forgot(c.Request.Context(), req.Email, client(c)); err != nil {
+		log.Printf("forgot password failed: %v", err) // respon tetap sama
 	}
 
-	c.JSON(http.StatusOK, dto.ForgotPasswordResponse{Message: "If the email exists, a reset link has been sent"})
+	c.JSON(http.StatusOK, dto.ForgotPasswordResponse{Message: "If the email exists, password reset instructions have been sent"})
 }
 
-// POST /auth/password/reset
+// POST /auth/reset-password
 func (h *PasswordHandler) Reset(c *gin.Context) {
 	var req dto.ResetPasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -56,9 +133,14 @@ func (h *PasswordHandler) Reset(c *gin.Context) {
 		return
 	}
 
-	err := h.passwordUsecase.Reset(c.Request.Context(), req.Token, req.Password)
+	var err error
+	if req.Token != "" {
+		err = h.passwordUsecase.Reset(c.Request.Context(), req.Token, req.Password, client(c))
+	} else {
+		err = h.passwordUsecase.ResetWithCode(c.Request.Context(), req.Email, req.Code, req.Password, client(c))
+	}
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		h.error(c, err)
 		return
 	}
 
@@ -80,11 +162,147 @@ func (h *PasswordHandler) Change(c *gin.Context) {
 		return
 	}
 
-	err := h.passwordUsecase.Change(c.Request.Context(), userID, req.CurrentPassword, req.NewPassword)
+	err := h.passwordUsecase.Change(c.Request.Context(), userID, req.CurrentPassword, req.NewPassword, client(c))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		h.error(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, dto.ChangePasswordResponse{Message: "Password changed successfully"})
-}
\ No newline at end of file
+}
+
+// POST /users/:id/password (admin)
+func (h *PasswordHandler) AdminReset(c *gin.Context) {
+	var req dto.AdminResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request"})
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Validation failed"})
+		return
+	}
+
+	err := h.passwordUsecase.AdminReset(c.Request.Context(), c.Param("id"), req.NewPassword, req.RequireChange, client(c))
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ChangePasswordResponse{Message: "Password reset successfully"})
+}
+
+// POST /users/:id/require-password-change (admin)
+func (h *PasswordHandler) RequireChange(c *gin.Context) {
+	if err := h.passwordUsecase.RequireChange(c.Request.Context(), c.Param("id")); err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ChangePasswordResponse{Message: "User must change password on next request"})
+}
+
+// POST /users/require-password-change (admin)
+func (h *PasswordHandler) RequireChangeBulk(c *gin.Context) {
+	var req dto.RequirePasswordChangeBulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request"})
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Validation failed"})
+		return
+	}
+
+	count, err := h.passwordUsecase.RequireChangeBulk(c.Request.Context(), c.GetString("user_id"), req.UserIDs, req.All)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.RequirePasswordChangeBulkResponse{
+		Message: "Users must change password on next request",
+		Count:   count,
+	})
+}
+
+// GET /security/password-hashing (admin)
+// Metrik pool hashing: memory terpakai, antrian, waktu tunggu & request yang ditolak 503
+func (h *PasswordHandler) HashingStats(c *gin.Context) {
+	if h.hashingMonitor == nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Message: "Password hashing metrics are not available"})
+		return
+	}
+
+	stats := h.hashingMonitor.Stats()
+
+	buckets := make([]dto.PasswordHashingWaitBucketResponse, 0, len(stats.WaitBuckets))
+	for _, b := range stats.WaitBuckets {
+		buckets = append(buckets, dto.PasswordHashingWaitBucketResponse{LeMs: b.Le.Milliseconds(), Count: b.Count})
+	}
+
+	var waitAvg float64
+	if stats.Acquired > 0 {
+		waitAvg = float64(stats.WaitTotal.Microseconds()) / float64(stats.Acquired) / 1000
+	}
+
+	c.JSON(http.StatusOK, dto.PasswordHashingStatsResponse{
+		MemoryBudgetMiB: stats.MemoryBudget >> 10,
+		MemoryInUseMiB:  stats.MemoryInUse >> 10,
+		Running:         stats.Running,
+		Waiting:         stats.Waiting,
+		Acquired:        stats.Acquired,
+		Rejected:        stats.Rejected,
+		Canceled:        stats.Canceled,
+		WaitAvgMs:       waitAvg,
+		WaitMaxMs:       float64(stats.WaitMax.Microseconds()) / 1000,
+		WaitBuckets:     buckets,
+	})
+}
+
+func (h *PasswordHandler) error(c *gin.Context, err error) {
+	if res, ok := passwordPolicyError(err); ok {
+		c.JSON(http.StatusBadRequest, res)
+		return
+	}
+	if httperr.PasswordHasherBusy(c, err) {
+		return
+	}
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, auth.ErrResetTokenInvalid),
+		errors.Is(err, auth.ErrResetTokenUsed),
+		errors.Is(err, auth.ErrCurrentPasswordWrong),
+		errors.Is(err, auth.ErrPasswordSameAsOld),
+		errors.Is(err, auth.ErrDecode):
+		status = http.StatusBadRequest
+	case errors.Is(err, auth.ErrPasswordUserNotFound):
+		status = http.StatusNotFound
+	}
+
+	c.JSON(status, dto.ErrorResponse{Message: err.Error()})
+}
+
+// passwordPolicyError: semua violation di "errors" (kode rule → pesan)
+func passwordPolicyError(err error) (dto.ErrorResponse, bool) {
+	var policyErr *valueobjects.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		return dto.ErrorResponse{}, false
+	}
+
+	errs := make(map[string]string, len(policyErr.Violations))
+	for _, v := range policyErr.Violations {
+		errs[v.Code] = v.Message
+	}
+	return dto.ErrorResponse{Message: valueobjects.ErrPasswordPolicy.Error(), Errors: errs}, true
+}
+
+func client(c *gin.Context) auth.LoginClient {
+	return auth.LoginClient{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
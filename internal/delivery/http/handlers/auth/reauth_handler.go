@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/dto"
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/handlers/httperr"
+	"github.com/dhanarrizky/Golang-template/internal/usecase/auth"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type ReauthHandler struct {
+	reauthUsecase auth.ReauthUsecase
+	validate      *validator.Validate
+}
+
+func NewReauthHandler(
+	reauthUsecase auth.ReauthUsecase,
+	validate *validator.Validate,
+) *ReauthHandler {
+	return &ReauthHandler{
+		reauthUsecase: reauthUsecase,
+		validate:      validate,
+	}
+}
+
+// POST /auth/reauthenticate (require auth middleware)
+func (h *ReauthHandler) Reauthenticate(c *gin.Context) {
+	userID := c.GetString("user_id") // dari middleware auth
+
+	var req dto.ReauthenticateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Invalid request",
+		})
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		errs := map[string]string{}
+		for _, e := range err.(validator.ValidationErrors) {
+			errs[e.Field()] = e.Tag()
+		}
+
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Validation failed",
+			Errors:  errs,
+		})
+		return
+	}
+
+	result, err := h.reauthUsecase.Reauthenticate(
+		c.Request.Context(),
+		userID,
+		req.Password,
+		req.Code,
+	)
+	if err != nil {
+		if httperr.PasswordHasherBusy(c, err) {
+			return
+		}
+
+		status := http.StatusUnauthorized
+		switch {
+		case errors.Is(err, auth.ErrReauthCredentialRequired),
+			errors.Is(err, auth.ErrSecondFactorNotEnrolled):
+			status = http.StatusBadRequest
+		case errors.Is(err, auth.ErrInvalidReauthCredentials),
+			errors.Is(err, auth.ErrReauthUserNotFound):
+			status = http.StatusUnauthorized
+		case errors.Is(err, auth.ErrReauthUserIdentifierDecode):
+			status = http.StatusInternalServerError
+		}
+
+		c.JSON(status, dto.ErrorResponse{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ReauthenticateResponse{
+		AccessToken: result.AccessToken,
+		ExpiresAt:   result.ExpiresAt,
+		AuthTime:    result.AuthTime,
+		ACR:         result.ACR,
+	})
+}
@@ -0,0 +1,200 @@
+package devmail
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/dto"
+	emailUC "github.com/dhanarrizky/Golang-template/internal/usecase/email"
+)
+
+// DevMailHandler: mail catcher, hanya didaftarkan saat development
+type DevMailHandler struct {
+	usecase emailUC.DevMailUsecase
+}
+
+func NewDevMailHandler(usecase emailUC.DevMailUsecase) *DevMailHandler {
+	return &DevMailHandler{usecase: usecase}
+}
+
+var inboxPage = template.Must(template.New("inbox").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Mail catcher</title>
+<style>
+body{font-family:sans-serif;margin:24px;color:#111}
+table{border-collapse:collapse;width:100%}
+td,th{border-bottom:1px solid #e5e7eb;padding:8px;text-align:left;font-size:14px}
+code{background:#f3f4f6;padding:2px 4px}
+</style>
+</head>
+<body>
+<h2>Mail catcher ({{len .}})</h2>
+<form method="post" action="clear"><button>Clear</button></form>
+<table>
+<tr><th>#</th><th>To</th><th>Subject</th><th>Code</th><th>Sent</th><th></th></tr>
+{{range .}}<tr>
+<td>{{.ID}}</td>
+<td>{{range .To}}{{.}} {{end}}</td>
+<td>{{.Subject}}</td>
+<td>{{range .Codes}}<code>{{.}}</code> {{end}}</td>
+<td>{{.SentAt.Format "15:04:05"}}</td>
+<td><a href="{{.ID}}/html" target="_blank">html</a> · <a href="{{.ID}}/text" target="_blank">text</a> · <a href="{{.ID}}">json</a></td>
+</tr>{{else}}<tr><td colspan="6">No messages yet</td></tr>{{end}}
+</table>
+</body>
+</html>`))
+
+// GET /dev/mail/ui (development only)
+func (h *DevMailHandler) Inbox(c *gin.Context) {
+	entries, err := h.usecase.List(c.Request.Context(), c.Query("to"), 100)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusOK)
+	_ = inboxPage.Execute(c.Writer, entries)
+}
+
+// GET /dev/mail?to=&limit= (development only)
+func (h *DevMailHandler) List(c *gin.Context) {
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Message: "Validation failed",
+				Errors:  map[string]string{"limit": "min"},
+			})
+			return
+		}
+		limit = n
+	}
+
+	entries, err := h.usecase.List(c.Request.Context(), c.Query("to"), limit)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	items := make([]dto.DevMailSummaryResponse, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, toDevMailSummary(e))
+	}
+
+	c.JSON(http.StatusOK, dto.DevMailListResponse{Items: items})
+}
+
+// GET /dev/mail/latest?to= (development only)
+func (h *DevMailHandler) Latest(c *gin.Context) {
+	entry, err := h.usecase.Latest(c.Request.Context(), c.Query("to"))
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDevMailResponse(entry))
+}
+
+// GET /dev/mail/:id (development only)
+func (h *DevMailHandler) Get(c *gin.Context) {
+	entry, ok := h.find(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, toDevMailResponse(entry))
+}
+
+// GET /dev/mail/:id/html (development only)
+func (h *DevMailHandler) HTML(c *gin.Context) {
+	entry, ok := h.find(c)
+	if !ok {
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(entry.HTML))
+}
+
+// GET /dev/mail/:id/text (development only)
+func (h *DevMailHandler) Text(c *gin.Context) {
+	entry, ok := h.find(c)
+	if !ok {
+		return
+	}
+
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(entry.Text))
+}
+
+// DELETE /dev/mail, POST /dev/mail/clear (development only)
+func (h *DevMailHandler) Clear(c *gin.Context) {
+	if err := h.usecase.Clear(c.Request.Context()); err != nil {
+		h.error(c, err)
+		return
+	}
+
+	// form "Clear" di halaman UI kembali ke inbox
+	if c.Request.Method == http.MethodPost {
+		c.Redirect(http.StatusSeeOther, "ui")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Message: "Mailbox cleared",
+	})
+}
+
+func (h *DevMailHandler) find(c *gin.Context) (*emailUC.DevMailEntry, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Invalid message id",
+		})
+		return nil, false
+	}
+
+	entry, err := h.usecase.Get(c.Request.Context(), id)
+	if err != nil {
+		h.error(c, err)
+		return nil, false
+	}
+
+	return entry, true
+}
+
+func (h *DevMailHandler) error(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, emailUC.ErrDevMailNotFound) {
+		status = http.StatusNotFound
+	}
+
+	c.JSON(status, dto.ErrorResponse{
+		Message: err.Error(),
+	})
+}
+
+func toDevMailSummary(e *emailUC.DevMailEntry) dto.DevMailSummaryResponse {
+	return dto.DevMailSummaryResponse{
+		ID:      e.ID,
+		From:    e.From,
+		To:      e.To,
+		Subject: e.Subject,
+		SentAt:  e.SentAt,
+	}
+}
+
+func toDevMailResponse(e *emailUC.DevMailEntry) dto.DevMailResponse {
+	return dto.DevMailResponse{
+		DevMailSummaryResponse: toDevMailSummary(e),
+		HTML:                   e.HTML,
+		Text:                   e.Text,
+		Codes:                  e.Codes,
+		Links:                  e.Links,
+		Tokens:                 e.Tokens,
+	}
+}
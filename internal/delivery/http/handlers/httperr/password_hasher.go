@@ -0,0 +1,24 @@
+// Package httperr berisi pemetaan error yang dipakai bersama oleh beberapa handler
+package httperr
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/dto"
+	userPorts "github.com/dhanarrizky/Golang-template/internal/ports/users"
+)
+
+// PasswordHasherBusy: pool hashing penuh → 503 + Retry-After, client boleh mengulang request.
+// Return true jika respon sudah ditulis.
+func PasswordHasherBusy(c *gin.Context, err error) bool {
+	if !errors.Is(err, userPorts.ErrPasswordHasherBusy) {
+		return false
+	}
+
+	c.Header("Retry-After", "1")
+	c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Message: err.Error()})
+	return true
+}
@@ -0,0 +1,125 @@
+package outbox
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/dto"
+	emailUC "github.com/dhanarrizky/Golang-template/internal/usecase/email"
+)
+
+type EmailOutboxHandler struct {
+	usecase emailUC.EmailOutboxUsecase
+}
+
+func NewEmailOutboxHandler(usecase emailUC.EmailOutboxUsecase) *EmailOutboxHandler {
+	return &EmailOutboxHandler{usecase: usecase}
+}
+
+// GET /email-outbox?status=&limit=&offset= (admin only)
+func (h *EmailOutboxHandler) List(c *gin.Context) {
+	limit, offset, ok := h.paging(c)
+	if !ok {
+		return
+	}
+
+	entries, err := h.usecase.List(c.Request.Context(), c.Query("status"), limit, offset)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	items := make([]dto.EmailOutboxMessageResponse, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, toEmailOutboxResponse(e))
+	}
+
+	c.JSON(http.StatusOK, dto.EmailOutboxListResponse{Items: items})
+}
+
+// GET /email-outbox/:id (admin only)
+func (h *EmailOutboxHandler) Get(c *gin.Context) {
+	entry, err := h.usecase.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toEmailOutboxResponse(entry))
+}
+
+// POST /email-outbox/:id/replay (admin only)
+func (h *EmailOutboxHandler) Replay(c *gin.Context) {
+	if err := h.usecase.Replay(c.Request.Context(), c.Param("id")); err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.MessageResponse{
+		Message: "Message queued for delivery",
+	})
+}
+
+func (h *EmailOutboxHandler) paging(c *gin.Context) (int, int, bool) {
+	limit, offset := 0, 0
+	errs := map[string]string{}
+
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			errs["limit"] = "min"
+		}
+		limit = n
+	}
+	if v := c.Query("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			errs["offset"] = "min"
+		}
+		offset = n
+	}
+
+	if len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Validation failed",
+			Errors:  errs,
+		})
+		return 0, 0, false
+	}
+
+	return limit, offset, true
+}
+
+func (h *EmailOutboxHandler) error(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, emailUC.ErrOutboxMessageNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, emailUC.ErrOutboxIDDecode),
+		errors.Is(err, emailUC.ErrOutboxInvalidStatus):
+		status = http.StatusBadRequest
+	case errors.Is(err, emailUC.ErrOutboxMessageNotReplayable):
+		status = http.StatusConflict
+	}
+
+	c.JSON(status, dto.ErrorResponse{
+		Message: err.Error(),
+	})
+}
+
+func toEmailOutboxResponse(e *emailUC.EmailOutboxEntry) dto.EmailOutboxMessageResponse {
+	return dto.EmailOutboxMessageResponse{
+		ID:            e.ID,
+		Kind:          e.Kind,
+		Recipient:     e.Recipient,
+		Status:        e.Status,
+		Attempts:      e.Attempts,
+		NextAttemptAt: e.NextAttemptAt,
+		LastError:     e.LastError,
+		SentAt:        e.SentAt,
+		CreatedAt:     e.CreatedAt,
+	}
+}
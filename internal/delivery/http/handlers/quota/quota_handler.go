@@ -0,0 +1,226 @@
+package quota
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/dto"
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/middleware"
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	"github.com/dhanarrizky/Golang-template/internal/usecase/quota"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type QuotaHandler struct {
+	quotaUsecase quota.QuotaUsecase
+	validate     *validator.Validate
+}
+
+func NewQuotaHandler(
+	quotaUsecase quota.QuotaUsecase,
+	validate *validator.Validate,
+) *QuotaHandler {
+	return &QuotaHandler{
+		quotaUsecase: quotaUsecase,
+		validate:     validate,
+	}
+}
+
+// GET /users/me/usage
+func (h *QuotaHandler) Me(c *gin.Context) {
+	subject, ok := middleware.QuotaSubjectFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	h.usage(c, subject)
+}
+
+// GET /quotas/:type/:id/usage (admin only)
+func (h *QuotaHandler) Usage(c *gin.Context) {
+	h.usage(c, subjectFromParams(c))
+}
+
+// GET /quota-plans (admin only)
+func (h *QuotaHandler) ListPlans(c *gin.Context) {
+	plans, err := h.quotaUsecase.ListPlans(c.Request.Context())
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	items := make([]dto.QuotaPlanResponse, 0, len(plans))
+	for _, p := range plans {
+		items = append(items, toQuotaPlanResponse(p))
+	}
+
+	c.JSON(http.StatusOK, dto.ListQuotaPlanResponse{
+		Items: items,
+		Total: int64(len(items)),
+	})
+}
+
+// PUT /roles/:id/quota-plan (admin only)
+func (h *QuotaHandler) SetRolePlan(c *gin.Context) {
+	var req dto.SetQuotaPlanRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	plan, err := h.quotaUsecase.SetRolePlan(
+		c.Request.Context(),
+		c.Param("id"),
+		req.Name,
+		req.DailyLimit,
+		req.MonthlyLimit,
+	)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toQuotaPlanResponse(plan))
+}
+
+// DELETE /roles/:id/quota-plan (admin only)
+func (h *QuotaHandler) RemoveRolePlan(c *gin.Context) {
+	if err := h.quotaUsecase.RemoveRolePlan(c.Request.Context(), c.Param("id")); err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "Quota plan removed"})
+}
+
+// PUT /quotas/:type/:id/override (admin only)
+func (h *QuotaHandler) SetOverride(c *gin.Context) {
+	var req dto.SetQuotaOverrideRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	err := h.quotaUsecase.SetOverride(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		quota.QuotaOverrideInput{
+			Subject:      subjectFromParams(c),
+			DailyLimit:   req.DailyLimit,
+			MonthlyLimit: req.MonthlyLimit,
+			Reason:       req.Reason,
+			ExpiresAt:    req.ExpiresAt,
+		},
+	)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "Quota override saved"})
+}
+
+// DELETE /quotas/:type/:id/override (admin only)
+func (h *QuotaHandler) RemoveOverride(c *gin.Context) {
+	if err := h.quotaUsecase.RemoveOverride(c.Request.Context(), subjectFromParams(c)); err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "Quota override removed"})
+}
+
+func (h *QuotaHandler) usage(c *gin.Context, subject valueobjects.QuotaSubject) {
+	status, err := h.quotaUsecase.GetUsage(c.Request.Context(), subject)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	resp := dto.QuotaUsageResponse{
+		SubjectType: string(status.Subject.Type),
+		SubjectID:   status.Subject.ID,
+		Plan:        status.Plan,
+		Source:      status.Source,
+		Exhausted:   !status.Allowed,
+		Windows:     make([]dto.QuotaWindowResponse, 0, len(status.Windows)),
+	}
+	for _, w := range status.Windows {
+		resp.Windows = append(resp.Windows, dto.QuotaWindowResponse{
+			Period:    string(w.Period),
+			Limit:     w.Limit,
+			Used:      w.Used,
+			Remaining: w.Remaining,
+			ResetAt:   w.ResetAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *QuotaHandler) bind(c *gin.Context, req any) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Invalid request",
+		})
+		return false
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		errs := map[string]string{}
+		for _, e := range err.(validator.ValidationErrors) {
+			errs[e.Field()] = e.Tag()
+		}
+
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Validation failed",
+			Errors:  errs,
+		})
+		return false
+	}
+
+	return true
+}
+
+func (h *QuotaHandler) error(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, quota.ErrQuotaSubjectNotFound),
+		errors.Is(err, quota.ErrQuotaRoleNotFound),
+		errors.Is(err, quota.ErrQuotaPlanNotFound),
+		errors.Is(err, quota.ErrQuotaOverrideNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, quota.ErrQuotaInvalidSubject),
+		errors.Is(err, quota.ErrQuotaRoleIDDecode),
+		errors.Is(err, quota.ErrQuotaNegativeLimit),
+		errors.Is(err, quota.ErrQuotaEmptyOverride),
+		errors.Is(err, quota.ErrQuotaReasonIsRequired),
+		errors.Is(err, quota.ErrQuotaOverrideExpired),
+		errors.Is(err, quota.ErrQuotaPlanNameRequired):
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, dto.ErrorResponse{
+		Message: err.Error(),
+	})
+}
+
+// subjectFromParams: :type = user | service_account | api_key, :id = public id
+func subjectFromParams(c *gin.Context) valueobjects.QuotaSubject {
+	return valueobjects.QuotaSubject{
+		Type: valueobjects.QuotaSubjectType(c.Param("type")),
+		ID:   c.Param("id"),
+	}
+}
+
+func toQuotaPlanResponse(p *quota.QuotaPlanEntry) dto.QuotaPlanResponse {
+	return dto.QuotaPlanResponse{
+		RoleID:       p.RoleID,
+		Name:         p.Name,
+		DailyLimit:   p.DailyLimit,
+		MonthlyLimit: p.MonthlyLimit,
+		UpdatedAt:    p.UpdatedAt,
+	}
+}
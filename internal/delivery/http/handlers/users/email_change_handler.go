@@ -0,0 +1,130 @@
+package users
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/dto"
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/handlers/httperr"
+	"github.com/dhanarrizky/Golang-template/internal/usecase/user"
+)
+
+type EmailChangeHandler struct {
+	usecase  user.EmailChangeUsecase
+	validate *validator.Validate
+}
+
+func NewEmailChangeHandler(usecase user.EmailChangeUsecase, validate *validator.Validate) *EmailChangeHandler {
+	return &EmailChangeHandler{
+		usecase:  usecase,
+		validate: validate,
+	}
+}
+
+// POST /users/me/email
+func (h *EmailChangeHandler) Request(c *gin.Context) {
+	var req dto.ChangeEmailRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	pending, err := h.usecase.RequestChange(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		req.NewEmail,
+		req.Password,
+	)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.ChangeEmailResponse{
+		Message:      "Check your new email address to confirm the change",
+		PendingEmail: pending.NewEmail,
+		ExpiresAt:    pending.ExpiresAt,
+	})
+}
+
+// POST /users/me/verify-email (link dari email ke alamat baru)
+func (h *EmailChangeHandler) Confirm(c *gin.Context) {
+	var req dto.EmailTokenRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	if err := h.usecase.Confirm(c.Request.Context(), c.GetString("user_id"), req.Token); err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Message: "Email address updated",
+	})
+}
+
+// POST /auth/email-change/revert (link dari email ke alamat lama)
+func (h *EmailChangeHandler) Revert(c *gin.Context) {
+	var req dto.EmailTokenRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	if err := h.usecase.Revert(c.Request.Context(), req.Token); err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Message: "Email change reverted. If you didn't request it, change your password now.",
+	})
+}
+
+func (h *EmailChangeHandler) bind(c *gin.Context, req any) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request"})
+		return false
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		errs := map[string]string{}
+		for _, e := range err.(validator.ValidationErrors) {
+			errs[e.Field()] = e.Tag()
+		}
+
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Validation failed",
+			Errors:  errs,
+		})
+		return false
+	}
+
+	return true
+}
+
+func (h *EmailChangeHandler) error(c *gin.Context, err error) {
+	if httperr.PasswordHasherBusy(c, err) {
+		return
+	}
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, user.ErrUserNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, user.ErrInvalidPassword):
+		status = http.StatusUnauthorized
+	case errors.Is(err, user.ErrEmailTaken),
+		errors.Is(err, user.ErrEmailRevertConflict):
+		status = http.StatusConflict
+	case errors.Is(err, user.ErrEmailChangeSameAddress),
+		errors.Is(err, user.ErrInvalidEmailChangeToken):
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, dto.ErrorResponse{
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,98 @@
+package users
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/dto"
+	"github.com/dhanarrizky/Golang-template/internal/usecase/user"
+)
+
+type EmailVerificationHandler struct {
+	usecase  user.EmailVerificationUsecase
+	validate *validator.Validate
+}
+
+func NewEmailVerificationHandler(usecase user.EmailVerificationUsecase, validate *validator.Validate) *EmailVerificationHandler {
+	return &EmailVerificationHandler{
+		usecase:  usecase,
+		validate: validate,
+	}
+}
+
+// POST /auth/verify-email
+func (h *EmailVerificationHandler) Verify(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	var err error
+	if req.Token != "" {
+		err = h.usecase.Verify(c.Request.Context(), req.Token)
+	} else {
+		err = h.usecase.VerifyCode(c.Request.Context(), req.Email, req.Code)
+	}
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.VerifyEmailResponse{
+		Message: "Email address verified",
+	})
+}
+
+// POST /auth/verify-email/resend
+func (h *EmailVerificationHandler) Resend(c *gin.Context) {
+	var req dto.ResendVerificationRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	if err := h.usecase.Resend(c.Request.Context(), req.Email); err != nil {
+		h.error(c, err)
+		return
+	}
+
+	// respon sama untuk email terdaftar maupun tidak, termasuk saat cooldown
+	c.JSON(http.StatusOK, dto.ResendVerificationResponse{
+		Message: "If the account exists and is not verified yet, a verification email has been sent",
+	})
+}
+
+func (h *EmailVerificationHandler) bind(c *gin.Context, req any) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request"})
+		return false
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		errs := map[string]string{}
+		for _, e := range err.(validator.ValidationErrors) {
+			errs[e.Field()] = e.Tag()
+		}
+
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Validation failed",
+			Errors:  errs,
+		})
+		return false
+	}
+
+	return true
+}
+
+func (h *EmailVerificationHandler) error(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, user.ErrInvalidVerificationToken) {
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, dto.ErrorResponse{
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,150 @@
+package users
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/dto"
+	"github.com/dhanarrizky/Golang-template/internal/usecase/user"
+)
+
+type PhoneHandler struct {
+	usecase  user.PhoneUsecase
+	validate *validator.Validate
+}
+
+func NewPhoneHandler(usecase user.PhoneUsecase, validate *validator.Validate) *PhoneHandler {
+	return &PhoneHandler{
+		usecase:  usecase,
+		validate: validate,
+	}
+}
+
+// PUT /users/me/phone
+func (h *PhoneHandler) Set(c *gin.Context) {
+	var req dto.SetPhoneRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	pending, err := h.usecase.SetPhone(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		req.Phone,
+		req.Channel,
+	)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.SetPhoneResponse{
+		Message:   "Enter the code we sent to your phone to verify it",
+		Phone:     pending.Phone,
+		Channel:   pending.Channel,
+		ExpiresAt: pending.ExpiresAt,
+	})
+}
+
+// POST /users/me/phone/verify
+func (h *PhoneHandler) Verify(c *gin.Context) {
+	var req dto.VerifyPhoneRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	if err := h.usecase.VerifyPhone(c.Request.Context(), c.GetString("user_id"), req.Code); err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Message: "Phone number verified",
+	})
+}
+
+// DELETE /users/me/phone
+func (h *PhoneHandler) Remove(c *gin.Context) {
+	if err := h.usecase.RemovePhone(c.Request.Context(), c.GetString("user_id")); err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Message: "Phone number removed, notifications go to your email",
+	})
+}
+
+// PUT /users/me/notification-channel
+func (h *PhoneHandler) SetChannel(c *gin.Context) {
+	var req dto.NotificationChannelRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	if err := h.usecase.SetPreferredChannel(c.Request.Context(), c.GetString("user_id"), req.Channel); err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{
+		Message: "Notification channel updated",
+	})
+}
+
+func (h *PhoneHandler) bind(c *gin.Context, req any) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request"})
+		return false
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		errs := map[string]string{}
+		for _, e := range err.(validator.ValidationErrors) {
+			errs[e.Field()] = e.Tag()
+		}
+
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Validation failed",
+			Errors:  errs,
+		})
+		return false
+	}
+
+	return true
+}
+
+func (h *PhoneHandler) error(c *gin.Context, err error) {
+	var cooldown *user.VerificationCooldownError
+	if errors.As(err, &cooldown) {
+		retryAfter := int(math.Ceil(cooldown.RetryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		c.JSON(http.StatusTooManyRequests, dto.ErrorResponse{
+			Message: "A code was sent recently, try again later",
+		})
+		return
+	}
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, user.ErrUserNotFound),
+		errors.Is(err, user.ErrNoPhone):
+		status = http.StatusNotFound
+	case errors.Is(err, user.ErrInvalidPhone),
+		errors.Is(err, user.ErrInvalidPhoneCode),
+		errors.Is(err, user.ErrChannelNotAvailable):
+		status = http.StatusBadRequest
+	case errors.Is(err, user.ErrPhoneNotVerified),
+		errors.Is(err, user.ErrPhoneAlreadyVerified):
+		status = http.StatusConflict
+	}
+
+	c.JSON(status, dto.ErrorResponse{
+		Message: err.Error(),
+	})
+}
@@ -1,13 +1,17 @@
 package users
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 
 	"github.com/dhanarrizky/Golang-template/internal/delivery/http/dto"
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/handlers/httperr"
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
 	"github.com/dhanarrizky/Golang-template/internal/usecase/user"
+	mailer "github.com/dhanarrizky/Golang-template/pkg/email"
 )
 
 type UserHandler struct {
@@ -24,7 +28,7 @@ func NewUserHandler(usecase user.UserUsecase, validate *validator.Validate) *Use
 
 // GET /users
 func (h *UserHandler) List(c *gin.Context) {
-	users, err := h.usecase.get(c.Request.Context())
+	users, err := h.usecase.GetList(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Message: "Failed to fetch users",
@@ -53,11 +57,26 @@ func (h *UserHandler) Create(c *gin.Context) {
 
 	user, err := h.usecase.Register(
 		c.Request.Context(),
-		req.Email,
 		req.Username,
+		req.Email,
 		req.Password,
+		// bahasa email mengikuti browser saat registrasi
+		mailer.PreferredLocale(c.GetHeader("Accept-Language")),
 	)
 	if err != nil {
+		var policyErr *valueobjects.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			errs := make(map[string]string, len(policyErr.Violations))
+			for _, v := range policyErr.Violations {
+				errs[v.Code] = v.Message
+			}
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: valueobjects.ErrPasswordPolicy.Error(), Errors: errs})
+			return
+		}
+		if httperr.PasswordHasherBusy(c, err) {
+			return
+		}
+
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
 		return
 	}
@@ -91,31 +110,6 @@ func (h *UserHandler) GetByID(c *gin.Context) {
 	c.JSON(http.StatusOK, user)
 }
 
-// POST /users/me/verify-email
-func (h *UserHandler) VerifyEmail(c *gin.Context) {
-	userID := c.GetString("user_id")
-
-	var req dto.UpdateProfileRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request"})
-		return
-	}
-
-	if err := h.validate.Struct(req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Validation failed"})
-		return
-	}
-
-	if err := h.usecase.UpdateProfile(c.Request.Context(), userID, req.Username); err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusOK, dto.UpdateProfileResponse{
-		Message: "Profile updated successfully",
-	})
-}
-
 // PUT /users/me
 func (h *UserHandler) Update(c *gin.Context) {
 	userID := c.GetString("user_id")
@@ -0,0 +1,107 @@
+package users
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	userPorts "github.com/dhanarrizky/Golang-template/internal/ports/users"
+	passwordUC "github.com/dhanarrizky/Golang-template/internal/usecase/password"
+	"github.com/dhanarrizky/Golang-template/internal/usecase/user"
+)
+
+// fake hanya mengisi method yang dipakai Register, sisanya nil (panic jika terpanggil)
+type fakeUserRepo struct {
+	userPorts.UserRepository
+	created *domain.User
+}
+
+func (r *fakeUserRepo) GetByEmailOrUsername(ctx context.Context, identifier string) (*domain.User, error) {
+	return nil, nil
+}
+
+func (r *fakeUserRepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return nil, nil
+}
+
+func (r *fakeUserRepo) Create(ctx context.Context, u *domain.User) error {
+	u.ID = 1
+	r.created = u
+	return nil
+}
+
+type fakeHasher struct {
+	userPorts.PasswordHasher
+}
+
+func (fakeHasher) HashPassword(ctx context.Context, password []byte) (string, error) {
+	return "hashed", nil
+}
+
+type fakePolicy struct {
+	passwordUC.PasswordPolicyUsecase
+	info valueobjects.PasswordUserInfo
+}
+
+func (p *fakePolicy) Validate(ctx context.Context, password string, info valueobjects.PasswordUserInfo) error {
+	p.info = info
+	return nil
+}
+
+type fakeCodec struct{}
+
+func (fakeCodec) Encode(id uint64) (string, error) { return "u1", nil }
+func (fakeCodec) Decode(id string) (uint64, error) { return 1, nil }
+
+type fakeVerification struct {
+	user.EmailVerificationUsecase
+	sentTo []string
+}
+
+func (v *fakeVerification) SendVerification(ctx context.Context, u *domain.User) error {
+	v.sentTo = append(v.sentTo, u.Email)
+	return nil
+}
+
+func TestUserHandler_CreateSendsVerificationToEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &fakeUserRepo{}
+	policy := &fakePolicy{}
+	verification := &fakeVerification{}
+	uc := user.NewUserUsecase(repo, nil, fakeHasher{}, policy, fakeCodec{}, nil, nil, verification)
+
+	r := gin.New()
+	r.POST("/users", NewUserHandler(uc, validator.New()).Create)
+
+	body := `{"username":"alice","email":"alice@example.com","password":"correct horse battery staple"}`
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "id-ID,id;q=0.9")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body)
+	}
+
+	if len(verification.sentTo) != 1 || verification.sentTo[0] != "alice@example.com" {
+		t.Fatalf("verification sent to %v, want [alice@example.com]", verification.sentTo)
+	}
+	if repo.created.Username != "alice" || repo.created.Email != "alice@example.com" {
+		t.Fatalf("created username %q email %q", repo.created.Username, repo.created.Email)
+	}
+	if repo.created.Locale != "id-ID" {
+		t.Fatalf("locale = %q, want id-ID", repo.created.Locale)
+	}
+	if policy.info.Username != "alice" || policy.info.Email != "alice@example.com" {
+		t.Fatalf("password policy got %+v", policy.info)
+	}
+}
@@ -42,6 +42,15 @@ func AuthMiddleware(tokenSigner ports.TokenSigner) gin.HandlerFunc {
 		c.Set("userID", payload.UserID)
 		c.Set("tokenID", payload.TokenID)
 		c.Set("tokenExpiresAt", payload.ExpiresAt)
+		c.Set("authTime", payload.AuthTime)
+		c.Set("acr", payload.ACR)
+		// handlers membaca "user_id"
+		c.Set("user_id", payload.UserID)
+		// token impersonation: user_id = user yang di-impersonate, actorID = admin
+		c.Set("actorID", payload.ActorID)
+		c.Set("impersonating", payload.IsImpersonated())
+		// jenis identitas untuk kuota (kosong = user)
+		c.Set("subjectType", payload.SubjectType)
 
 		c.Next()
 	}
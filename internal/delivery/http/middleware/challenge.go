@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+	"github.com/gin-gonic/gin"
+)
+
+// ChallengeResponseHeader carries the solved challenge (CAPTCHA token or "token:nonce" for PoW)
+const ChallengeResponseHeader = "X-Challenge-Response"
+
+// ChallengePolicy decides when a client becomes suspicious. Counters are per
+// client IP and route, reset after Window or after a solved challenge.
+type ChallengePolicy struct {
+	Window      time.Duration
+	MaxRequests int // requests allowed before a challenge is required
+	MaxFailures int // failed responses (4xx) allowed before a challenge is required
+}
+
+type challengeActivity struct {
+	requests int
+	failures int
+	reset    time.Time
+}
+
+type challengeTracker struct {
+	mu     sync.Mutex
+	store  map[string]*challengeActivity
+	policy ChallengePolicy
+}
+
+// get returns the current window for key; caller must hold mu
+func (t *challengeTracker) get(key string, now time.Time) *challengeActivity {
+	a, ok := t.store[key]
+	if !ok || now.After(a.reset) {
+		a = &challengeActivity{reset: now.Add(t.policy.Window)}
+		t.store[key] = a
+	}
+
+	// drop stale entries so the map doesn't grow unbounded
+	if len(t.store) > 10000 {
+		for k, v := range t.store {
+			if now.After(v.reset) {
+				delete(t.store, k)
+			}
+		}
+	}
+
+	return a
+}
+
+// RequireChallenge only asks for a solved challenge once the client crossed
+// the policy thresholds. A nil verifier disables the check.
+func RequireChallenge(verifier ports.ChallengeVerifier, policy ChallengePolicy) gin.HandlerFunc {
+	if verifier == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	tracker := &challengeTracker{
+		store:  make(map[string]*challengeActivity),
+		policy: policy,
+	}
+
+	return func(c *gin.Context) {
+		key := c.ClientIP() + "|" + c.FullPath()
+		now := time.Now()
+
+		tracker.mu.Lock()
+		a := tracker.get(key, now)
+		suspicious := a.requests >= policy.MaxRequests || a.failures >= policy.MaxFailures
+		tracker.mu.Unlock()
+
+		if suspicious {
+			solved := false
+			if solution := c.GetHeader(ChallengeResponseHeader); solution != "" {
+				ok, err := verifier.Verify(c.Request.Context(), solution, c.ClientIP())
+				if err != nil {
+					log.Printf("challenge verification failed: %v", err)
+				}
+				solved = ok
+			}
+
+			if !solved {
+				abortWithChallenge(c, verifier)
+				return
+			}
+
+			tracker.mu.Lock()
+			a = tracker.get(key, now)
+			a.requests, a.failures = 0, 0
+			tracker.mu.Unlock()
+		}
+
+		tracker.mu.Lock()
+		tracker.get(key, now).requests++
+		tracker.mu.Unlock()
+
+		c.Next()
+
+		if status := c.Writer.Status(); status >= 400 && status < 500 {
+			tracker.mu.Lock()
+			tracker.get(key, time.Now()).failures++
+			tracker.mu.Unlock()
+		}
+	}
+}
+
+func abortWithChallenge(c *gin.Context, verifier ports.ChallengeVerifier) {
+	ch, err := verifier.Issue(c.Request.Context())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"error": "challenge unavailable",
+		})
+		return
+	}
+
+	challenge := gin.H{"provider": ch.Provider}
+	if ch.SiteKey != "" {
+		challenge["site_key"] = ch.SiteKey
+	}
+	if ch.Token != "" {
+		challenge["token"] = ch.Token
+		challenge["difficulty"] = ch.Difficulty
+		challenge["expires_at"] = ch.ExpiresAt
+	}
+
+	c.AbortWithStatusJSON(http.StatusPreconditionRequired, gin.H{
+		"error":              "challenge required",
+		"challenge_required": true,
+		"challenge":          challenge,
+	})
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ForbidImpersonation blocks sensitive actions (password change, MFA changes,
+// account deletion) for impersonation tokens. Must run after AuthMiddleware.
+func ForbidImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetBool("impersonating") {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "action not allowed while impersonating",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ImpersonationAuditor records requests made under impersonation and tells
+// whether an impersonation token was stopped (implemented by
+// auth.ImpersonationUsecase).
+type ImpersonationAuditor interface {
+	RecordRequest(ctx context.Context, actorID, subjectID, method, path string, statusCode int, ipAddress, userAgent string) error
+	IsActive(ctx context.Context, tokenID string) (bool, error)
+}
+
+// AuditImpersonation rejects impersonation tokens whose session was stopped
+// (before they expire) and writes every request made with an impersonation
+// token, rejected ones included, to the audit log. Must run after
+// AuthMiddleware.
+func AuditImpersonation(auditor ImpersonationAuditor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetBool("impersonating") {
+			active, err := auditor.IsActive(c.Request.Context(), c.GetString("tokenID"))
+			switch {
+			case err != nil:
+				log.Printf("impersonation status check failed: %v", err)
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+					"error": "impersonation status unavailable",
+				})
+			case !active:
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+					"error": "impersonation ended",
+				})
+			}
+		}
+
+		c.Next()
+
+		if !c.GetBool("impersonating") {
+			return
+		}
+
+		err := auditor.RecordRequest(
+			c.Request.Context(),
+			c.GetString("actorID"),
+			c.GetString("user_id"),
+			c.Request.Method,
+			c.Request.URL.Path,
+			c.Writer.Status(),
+			c.ClientIP(),
+			c.Request.UserAgent(),
+		)
+		if err != nil {
+			log.Printf("impersonation audit failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	"github.com/gin-gonic/gin"
+)
+
+// QuotaEnforcer consumes one unit of the caller's daily/monthly quota
+type QuotaEnforcer interface {
+	Consume(ctx context.Context, subject valueobjects.QuotaSubject) (*valueobjects.QuotaStatus, error)
+}
+
+// QuotaSubjectFromContext returns the identity placed by AuthMiddleware.
+// Tokens without a "sub_type" claim are treated as users.
+func QuotaSubjectFromContext(c *gin.Context) (valueobjects.QuotaSubject, bool) {
+	id := c.GetString("user_id")
+	if id == "" {
+		return valueobjects.QuotaSubject{}, false
+	}
+
+	subjectType := valueobjects.QuotaSubjectType(c.GetString("subjectType"))
+	if subjectType == "" {
+		subjectType = valueobjects.QuotaSubjectUser
+	}
+
+	return valueobjects.QuotaSubject{Type: subjectType, ID: id}, true
+}
+
+// EnforceQuota must run after AuthMiddleware. A nil enforcer disables the check;
+// store errors fail open like the rate limiter.
+func EnforceQuota(enforcer QuotaEnforcer) gin.HandlerFunc {
+	if enforcer == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		subject, ok := QuotaSubjectFromContext(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		status, err := enforcer.Consume(c.Request.Context(), subject)
+		if err != nil {
+			log.Printf("quota check failed for %s:%s: %v", subject.Type, subject.ID, err)
+			c.Next()
+			return
+		}
+
+		now := time.Now()
+		if w := status.Tightest(); w != nil {
+			c.Header("X-Quota-Limit", strconv.FormatInt(w.Limit, 10))
+			c.Header("X-Quota-Remaining", strconv.FormatInt(w.Remaining, 10))
+			c.Header("X-Quota-Reset", strconv.Itoa(ceilSeconds(w.ResetAt.Sub(now))))
+			c.Header("X-Quota-Period", string(w.Period))
+		}
+
+		if !status.Allowed {
+			// window habis dengan reset paling lama menentukan kapan boleh mencoba lagi
+			var exhausted *valueobjects.QuotaWindow
+			for i := range status.Windows {
+				w := &status.Windows[i]
+				if w.Limit > 0 && w.Remaining == 0 &&
+					(exhausted == nil || w.ResetAt.After(exhausted.ResetAt)) {
+					exhausted = w
+				}
+			}
+
+			body := gin.H{"error": "quota exceeded"}
+			if exhausted != nil {
+				c.Header("Retry-After", strconv.Itoa(ceilSeconds(exhausted.ResetAt.Sub(now))))
+				body["period"] = exhausted.Period
+				body["reset_at"] = exhausted.ResetAt
+			}
+
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, body)
+			return
+		}
+
+		c.Next()
+	}
+}
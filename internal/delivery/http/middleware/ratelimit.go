@@ -1,84 +1,86 @@
 package middleware
 
 import (
+	"log"
+	"math"
 	"net/http"
-	"sync"
+	"strconv"
 	"time"
-	"fmt"
 
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
 	"github.com/gin-gonic/gin"
 )
 
-// Simple in-memory rate limiter per key (IP or API key).
-// Not distributed — for single-process use. For multi-instance, plug Redis or other store.
-type simpleBucket struct {
-	remaining int
-	reset     time.Time
-}
-
-type rateLimiterStore struct {
-	mu    sync.Mutex
-	store map[string]*simpleBucket
-	// configuration
-	limit  int
-	window time.Duration
-}
-
-func newRateLimiterStore(limit int, window time.Duration) *rateLimiterStore {
-	return &rateLimiterStore{
-		store:  make(map[string]*simpleBucket),
-		limit:  limit,
-		window: window,
+// RateLimitMiddleware enforces policy per key. keyFunc decides the identity
+// (IP, user, API key); an empty key falls back to the client IP.
+// A nil limiter or a policy without limit disables the check.
+//
+// Headers follow the IETF RateLimit header fields draft:
+// RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset (delta seconds) and
+// RateLimit-Policy, plus Retry-After on 429.
+func RateLimitMiddleware(
+	limiter ports.RateLimiter,
+	policy valueobjects.RateLimitPolicy,
+	keyFunc func(c *gin.Context) string,
+) gin.HandlerFunc {
+	if limiter == nil || policy.Limit <= 0 {
+		return func(c *gin.Context) { c.Next() }
 	}
-}
 
-// RateLimitMiddleware returns a gin middleware. keyFunc decides the identity (IP, header, etc).
-// limit = number of requests per window (e.g., 60 per minute).
-func RateLimitMiddleware(limit int, window time.Duration, keyFunc func(c *gin.Context) string) gin.HandlerFunc {
-	store := newRateLimiterStore(limit, window)
+	policyHeader := strconv.Itoa(policy.Limit) + ";w=" + strconv.Itoa(int(policy.Window.Seconds()))
 
 	return func(c *gin.Context) {
 		key := keyFunc(c)
 		if key == "" {
 			key = c.ClientIP()
 		}
-		now := time.Now()
 
-		store.mu.Lock()
-		b, ok := store.store[key]
-		if !ok || now.After(b.reset) {
-			b = &simpleBucket{
-				remaining: store.limit - 1,
-				reset:     now.Add(store.window),
-			}
-			store.store[key] = b
-			store.mu.Unlock()
-		} else {
-			if b.remaining <= 0 {
-				// rate limited
-				resetIn := int(b.reset.Sub(now).Seconds())
-				store.mu.Unlock()
-				c.Header("Retry-After", string(resetIn))
-				c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-					"error":      "rate limit exceeded",
-					"retry_after": resetIn,
-				})
-				return
-			}
-			b.remaining--
-			store.mu.Unlock()
+		result, err := limiter.Allow(c.Request.Context(), key, policy)
+		if err != nil {
+			// fail open: store down tidak boleh mematikan seluruh API
+			log.Printf("rate limiter unavailable (policy %s): %v", policy.Name, err)
+			c.Next()
+			return
 		}
 
-		// set headers for visibility
-		c.Header("X-RateLimit-Limit", itoa(store.limit))
-		c.Header("X-RateLimit-Remaining", itoa(store.store[key].remaining))
-		c.Header("X-RateLimit-Reset", store.store[key].reset.Format(time.RFC3339))
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+		c.Header("RateLimit-Policy", policyHeader)
+
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":       "rate limit exceeded",
+				"retry_after": retryAfter,
+			})
+			return
+		}
 
 		c.Next()
 	}
 }
 
-// helper simple itoa for small integers
-func itoa(i int) string {
-	return fmt.Sprintf("%d", i)
+// RateLimitByIP keys requests by client IP
+func RateLimitByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// RateLimitByUser keys authenticated requests by user, anonymous ones by IP.
+// Must run after AuthMiddleware to see the user.
+func RateLimitByUser(c *gin.Context) string {
+	if userID := c.GetString("user_id"); userID != "" {
+		return "user:" + userID
+	}
+	return RateLimitByIP(c)
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
 }
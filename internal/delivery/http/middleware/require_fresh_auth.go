@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireFreshAuth rejects requests whose token was not obtained by an
+// interactive authentication within maxAge. Must run after AuthMiddleware.
+// The challenge follows RFC 9470 (OAuth 2.0 Step Up Authentication).
+func RequireFreshAuth(maxAge time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		authTime, _ := c.Get("authTime")
+		t, ok := authTime.(time.Time)
+
+		if !ok || t.IsZero() || time.Since(t) > maxAge {
+			c.Header("WWW-Authenticate", fmt.Sprintf(
+				`Bearer error="insufficient_user_authentication", error_description="more recent authentication is required", max_age=%d`,
+				int(maxAge.Seconds()),
+			))
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error":           "reauthentication required",
+				"reauth_required": true,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	"github.com/gin-gonic/gin"
+)
+
+// PasswordChangeChecker reports whether the user has to change their password
+// before using the API (the current password was found in a data breach, has
+// expired, or an admin required a change)
+type PasswordChangeChecker interface {
+	MustChangePassword(ctx context.Context, userID string) (bool, error)
+}
+
+// RequirePasswordChange must run after AuthMiddleware. While a password change
+// is pending only the routes in exemptPaths (gin route patterns, e.g.
+// "/v1/auth/password/change") are reachable. Only user tokens are checked.
+// A nil checker disables the check.
+func RequirePasswordChange(checker PasswordChangeChecker, exemptPaths ...string) gin.HandlerFunc {
+	if checker == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	exempt := make(map[string]struct{}, len(exemptPaths))
+	for _, p := range exemptPaths {
+		exempt[p] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		if _, ok := exempt[c.FullPath()]; ok {
+			c.Next()
+			return
+		}
+
+		subject, ok := QuotaSubjectFromContext(c)
+		if !ok || subject.Type != valueobjects.QuotaSubjectUser {
+			c.Next()
+			return
+		}
+
+		must, err := checker.MustChangePassword(c.Request.Context(), subject.ID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": "failed to check password status",
+			})
+			return
+		}
+
+		if must {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "password change required",
+				"code":  "password_change_required",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
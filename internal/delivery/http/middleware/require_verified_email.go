@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	"github.com/gin-gonic/gin"
+)
+
+// EmailVerificationChecker reports whether the user's email address is verified
+type EmailVerificationChecker interface {
+	IsVerified(ctx context.Context, userID string) (bool, error)
+}
+
+// RequireVerifiedEmail must run after AuthMiddleware. Routes listed in
+// exemptPaths (gin route patterns, e.g. "/v1/auth/logout") stay reachable so an
+// unverified user can still see their profile and sign out. Only user tokens
+// are checked; service accounts and API keys have no mailbox to verify.
+// A nil checker disables the check.
+func RequireVerifiedEmail(checker EmailVerificationChecker, exemptPaths ...string) gin.HandlerFunc {
+	if checker == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	exempt := make(map[string]struct{}, len(exemptPaths))
+	for _, p := range exemptPaths {
+		exempt[p] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		if _, ok := exempt[c.FullPath()]; ok {
+			c.Next()
+			return
+		}
+
+		subject, ok := QuotaSubjectFromContext(c)
+		if !ok || subject.Type != valueobjects.QuotaSubjectUser {
+			c.Next()
+			return
+		}
+
+		verified, err := checker.IsVerified(c.Request.Context(), subject.ID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": "failed to check email verification",
+			})
+			return
+		}
+
+		if !verified {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "email not verified",
+				"code":  "email_unverified",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
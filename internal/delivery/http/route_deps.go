@@ -4,9 +4,14 @@ import (
 	"github.com/go-playground/validator/v10"
 
 	"github.com/dhanarrizky/Golang-template/internal/config"
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
 	ports "github.com/dhanarrizky/Golang-template/internal/ports/auth"
+	"github.com/dhanarrizky/Golang-template/internal/ports/others"
+	userPorts "github.com/dhanarrizky/Golang-template/internal/ports/users"
 	authUC "github.com/dhanarrizky/Golang-template/internal/usecase/auth"
 	emailUC "github.com/dhanarrizky/Golang-template/internal/usecase/email"
+	passwordUC "github.com/dhanarrizky/Golang-template/internal/usecase/password"
+	quotaUC "github.com/dhanarrizky/Golang-template/internal/usecase/quota"
 	roleUC "github.com/dhanarrizky/Golang-template/internal/usecase/roles"
 	userUC "github.com/dhanarrizky/Golang-template/internal/usecase/user"
 )
@@ -29,15 +34,37 @@ type RouteDeps struct {
 	JwtSigner *ports.TokenSigner  // JWT signer
 	Validator *validator.Validate // Validator (e.g., go-playground/validator)
 	Config    *config.Config      // Config struct dengan JWTSecret, CORSAllowedOrigins, IsDevelopment()
+	// ChallengeVerifier: CAPTCHA / proof-of-work untuk endpoint publik (nil = dimatikan)
+	ChallengeVerifier others.ChallengeVerifier
+	// RateLimiter: memory / Redis store (nil = dimatikan), policy per nama ("default", "login", "otp", ...)
+	RateLimiter       others.RateLimiter
+	RateLimitPolicies map[string]valueobjects.RateLimitPolicy
 	// EmailSender emailUC.OTPUsecase  // Tambahan: Interface untuk send email (e.g., gomail)
 
-	LoginUC    authUC.LoginUsecase    // UseCase untuk login
-	TokenUC    authUC.TokenUsecase    // UseCase untuk token
-	PasswordUC authUC.PasswordUsecase // UseCase untuk password
-	UserUC     userUC.UserUsecase     // UseCase untuk user
-	RoleUC     roleUC.RoleUsecase     // UseCase untuk role
-	OTPUC      emailUC.OTPUsecase     // Tambahan: UseCase untuk OTP (generate, verify, resend)
-	// ForgotPasswordUC                        // Tambahan: UseCase untuk forgot password (send OTP, reset)
-	SessionUC authUC.SessionUsecase // Tambahan: UseCase untuk session management
-	// Tambah lain jika perlu, seperti RateLimiter untuk OTP/resend
+	LoginUC         authUC.LoginUsecase         // UseCase untuk login
+	ReauthUC        authUC.ReauthUsecase        // UseCase untuk step-up / re-autentikasi
+	ImpersonationUC authUC.ImpersonationUsecase // UseCase untuk admin "login as" user
+	AccountLockUC   authUC.AccountLockUsecase   // UseCase untuk lock/unlock akun
+	LoginHistoryUC  authUC.LoginHistoryUsecase  // UseCase untuk history login attempt
+	QuotaUC         quotaUC.QuotaUsecase        // UseCase untuk kuota harian/bulanan (nil = dimatikan)
+	TokenUC         authUC.TokenUsecase         // UseCase untuk token
+	PasswordUC      authUC.PasswordUsecase      // UseCase untuk password
+	UserUC          userUC.UserUsecase          // UseCase untuk user
+	EmailChangeUC   userUC.EmailChangeUsecase   // UseCase untuk ganti email (verifikasi alamat baru)
+	RoleUC          roleUC.RoleUsecase          // UseCase untuk role
+	OTPUC           emailUC.OTPUsecase          // Tambahan: UseCase untuk OTP (generate, verify, resend)
+	SessionUC       authUC.SessionUsecase       // Tambahan: UseCase untuk session management
+
+	// EmailVerificationUC: verifikasi email setelah registrasi (link / OTP)
+	EmailVerificationUC userUC.EmailVerificationUsecase
+	// EmailOutboxUC: inspeksi & replay outbox email (admin)
+	EmailOutboxUC emailUC.EmailOutboxUsecase
+	// DevMailUC: mail catcher untuk development (nil di luar development)
+	DevMailUC emailUC.DevMailUsecase
+	// PhoneUC: nomor HP + channel notifikasi (email / sms / whatsapp)
+	PhoneUC userUC.PhoneUsecase
+	// PasswordPolicyUC: aturan password baru (register, change, reset)
+	PasswordPolicyUC passwordUC.PasswordPolicyUsecase
+	// PasswordHashingMonitor: metrik antrian hashing password (admin)
+	PasswordHashingMonitor userPorts.PasswordHashingMonitor
 }
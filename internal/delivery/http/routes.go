@@ -1,9 +1,13 @@
 package http
 
 import (
+	"time"
+
 	"github.com/dhanarrizky/Golang-template/internal/delivery/http/handlers/auth"
-	"github.com/dhanarrizky/Golang-template/internal/delivery/http/handlers/otp"      // Tambahan untuk OTP handler
-	"github.com/dhanarrizky/Golang-template/internal/delivery/http/handlers/password" // Adjust untuk forgot password
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/handlers/devmail"
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/handlers/otp" // Tambahan untuk OTP handler
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/handlers/outbox"
+	"github.com/dhanarrizky/Golang-template/internal/delivery/http/handlers/quota"
 	"github.com/dhanarrizky/Golang-template/internal/delivery/http/handlers/roles"
 	"github.com/dhanarrizky/Golang-template/internal/delivery/http/handlers/session" // Tambahan untuk session handler
 	"github.com/dhanarrizky/Golang-template/internal/delivery/http/handlers/users"
@@ -50,14 +54,47 @@ func RegisterRoutes(r *gin.Engine, d RouteDeps) {
 		d.TokenUC,
 		d.Config.JWTSecret,
 	)
-	passwordHandler := auth.NewPasswordHandler( // Ini untuk change password
+	reauthHandler := auth.NewReauthHandler(
+		d.ReauthUC,
+		d.Validator,
+	)
+	impersonationHandler := auth.NewImpersonationHandler(
+		d.ImpersonationUC,
+		d.Validator,
+	)
+	accountLockHandler := auth.NewAccountLockHandler(
+		d.AccountLockUC,
+		d.Validator,
+	)
+	loginHistoryHandler := auth.NewLoginHistoryHandler(d.LoginHistoryUC)
+	challengeHandler := auth.NewChallengeHandler(d.ChallengeVerifier)
+	quotaHandler := quota.NewQuotaHandler(
+		d.QuotaUC,
+		d.Validator,
+	)
+	passwordHandler := auth.NewPasswordHandler( // forgot, reset & change password
 		d.PasswordUC,
+		d.PasswordPolicyUC,
+		d.PasswordHashingMonitor,
 		d.Validator,
 	)
 	userHandler := users.NewUserHandler(
 		d.UserUC,
 		d.Validator,
 	)
+	emailChangeHandler := users.NewEmailChangeHandler(
+		d.EmailChangeUC,
+		d.Validator,
+	)
+	emailVerificationHandler := users.NewEmailVerificationHandler(
+		d.EmailVerificationUC,
+		d.Validator,
+	)
+	phoneHandler := users.NewPhoneHandler(
+		d.PhoneUC,
+		d.Validator,
+	)
+	emailOutboxHandler := outbox.NewEmailOutboxHandler(d.EmailOutboxUC)
 	roleHandler := roles.NewRoleHandler(
 		d.RoleUC,
 		d.Validator,
@@ -69,49 +106,137 @@ func RegisterRoutes(r *gin.Engine, d RouteDeps) {
 		d.Validator,
 		d.EmailSender, // Untuk send OTP via email
 	)
-	forgotHandler := password.NewForgotPasswordHandler( // Asumsikan di package password atau auth
-		d.ForgotPasswordUC,
-		d.Validator,
-		d.EmailSender,
-	)
 	sessionHandler := session.NewSessionHandler(d.SessionUC) // Tambahan dari session management
 
+	// operasi sensitif wajib auth_time yang masih segar (step-up)
+	stepUpMaxAge, err := time.ParseDuration(d.Config.StepUpMaxAge)
+	if err != nil {
+		stepUpMaxAge = 5 * time.Minute
+	}
+	freshAuth := middleware.RequireFreshAuth(stepUpMaxAge)
+	// operasi sensitif (password, MFA, hapus akun) tidak boleh lewat token impersonation
+	noImpersonation := middleware.ForbidImpersonation()
+
+	// bot challenge hanya diminta setelah IP melewati ambang aktivitas mencurigakan
+	challengeWindow, err := time.ParseDuration(d.Config.ChallengeWindow)
+	if err != nil {
+		challengeWindow = 10 * time.Minute
+	}
+	botChallenge := middleware.RequireChallenge(d.ChallengeVerifier, middleware.ChallengePolicy{
+		Window:      challengeWindow,
+		MaxRequests: d.Config.ChallengeMaxRequests,
+		MaxFailures: d.Config.ChallengeMaxFailures,
+	})
+
+	// rate limit per policy dari RATE_LIMIT_POLICIES; policy yang tidak ada = tanpa limit
+	rateLimit := func(name string, keyFunc func(c *gin.Context) string) gin.HandlerFunc {
+		return middleware.RateLimitMiddleware(d.RateLimiter, d.RateLimitPolicies[name], keyFunc)
+	}
+	limitLogin := rateLimit("login", middleware.RateLimitByIP)
+	limitOTP := rateLimit("otp", middleware.RateLimitByIP)
+	limitRegister := rateLimit("register", middleware.RateLimitByIP)
+	limitPasswordReset := rateLimit("password_reset", middleware.RateLimitByIP)
+	limitPasswordStrength := rateLimit("password_strength", middleware.RateLimitByIP) // estimasi kekuatan makan CPU
+
+	// LOGIN_UNVERIFIED_POLICY=allow → email belum terverifikasi tetap dapat akses penuh.
+	// Selain itu hanya endpoint di bawah yang boleh diakses sebelum verifikasi.
+	var verifiedChecker middleware.EmailVerificationChecker
+	if d.Config.LoginUnverifiedPolicy != "allow" && d.EmailVerificationUC != nil {
+		verifiedChecker = d.EmailVerificationUC
+	}
+	requireVerifiedEmail := middleware.RequireVerifiedEmail(
+		verifiedChecker,
+		"/v1/auth/logout",
+		"/v1/auth/logout-all",
+		"/v1/auth/me",
+		"/v1/auth/reauthenticate",
+		"/v1/auth/impersonation/stop",
+		"/v1/users/me",
+		"/v1/users/me/email", // salah ketik email saat daftar → ganti alamat
+		"/v1/users/me/verify-email",
+	)
+
+	// password bocor di data breach, expired, atau diminta admin → hanya boleh ganti password,
+	// lihat profil dan logout sampai password diganti
+	var passwordChangeChecker middleware.PasswordChangeChecker
+	if d.PasswordUC != nil {
+		passwordChangeChecker = d.PasswordUC
+	}
+	requirePasswordChange := middleware.RequirePasswordChange(
+		passwordChangeChecker,
+		"/v1/auth/logout",
+		"/v1/auth/logout-all",
+		"/v1/auth/me",
+		"/v1/auth/reauthenticate",
+		"/v1/auth/password/change",
+		"/v1/users/me",
+	)
+
 	// =====================================================
 	// PUBLIC ROUTES
 	// =====================================================
 	public := r.Group("/v1")
+	public.Use(rateLimit("default", middleware.RateLimitByIP))
 	{
-		public.POST("/auth/login", authHandler.Login)
+		public.GET("/auth/challenge", challengeHandler.Issue)
+		public.POST("/auth/login", limitLogin, botChallenge, authHandler.Login)
 		public.POST("/auth/refresh", tokenHandler.Refresh)
+		public.POST("/auth/unlock", accountLockHandler.UnlockWithToken)     // link dari email lockout
+		public.POST("/auth/email-change/revert", emailChangeHandler.Revert) // link dari email ke alamat lama
+		// verifikasi email setelah registrasi (link atau email + kode OTP)
+		public.POST("/auth/verify-email", limitOTP, emailVerificationHandler.Verify)
+		public.POST("/auth/verify-email/resend", limitOTP, botChallenge, emailVerificationHandler.Resend)
 		// register
-		public.POST("/users", userHandler.Create) // Setelah create, trigger send OTP di use case
+		public.POST("/users", limitRegister, botChallenge, userHandler.Create) // Setelah create, trigger send OTP di use case
 
 		// Tambahan untuk OTP dan Forgot Password
-		public.POST("/auth/verify-otp", otpHandler.Verify)          // Verify OTP untuk aktivasi
-		public.POST("/auth/resend-otp", otpHandler.Resend)          // Resend OTP (rate limited di middleware jika perlu)
-		public.POST("/auth/forgot-password", forgotHandler.Request) // Send OTP untuk reset
-		public.POST("/auth/reset-password", forgotHandler.Reset)    // Reset password setelah verify OTP
+		public.POST("/auth/verify-otp", limitOTP, otpHandler.Verify)                                   // Verify OTP untuk aktivasi
+		public.POST("/auth/resend-otp", limitOTP, botChallenge, otpHandler.Resend)                     // Resend OTP
+		public.POST("/auth/forgot-password", limitPasswordReset, botChallenge, passwordHandler.Forgot) // Kirim link / OTP reset
+		public.POST("/auth/reset-password", limitPasswordReset, passwordHandler.Reset)                 // Reset dengan token link / email + OTP
+		public.GET("/auth/password-policy", passwordHandler.Policy)                                    // aturan password untuk form register / reset / change
+		public.POST("/auth/password-strength", limitPasswordStrength, passwordHandler.Strength)        // meter kekuatan password (skor 0-4 + saran)
 	}
 
 	// =====================================================
 	// PROTECTED ROUTES
 	// =====================================================
 	protected := r.Group("/v1")
-	protected.Use(middleware.AuthMiddleware(*d.JwtSigner))
+	protected.Use(
+		middleware.AuthMiddleware(*d.JwtSigner),
+		rateLimit("default", middleware.RateLimitByUser),
+		middleware.EnforceQuota(d.QuotaUC),
+		middleware.AuditImpersonation(d.ImpersonationUC),
+		requireVerifiedEmail,
+		requirePasswordChange,
+	)
 	{
 		// auth
 		protected.POST("/auth/logout", authHandler.Logout)
 		protected.POST("/auth/logout-all", authHandler.LogoutAll)
 		protected.GET("/auth/me", authHandler.Me)
+		protected.POST("/auth/reauthenticate", noImpersonation, reauthHandler.Reauthenticate)
+		protected.POST("/auth/impersonation/stop", impersonationHandler.Stop)
 		// password
-		protected.POST("/auth/password/change", passwordHandler.Change)
+		protected.POST("/auth/password/change", noImpersonation, freshAuth, passwordHandler.Change)
 		// user (self)
 		protected.GET("/users/me", userHandler.Me)
 		protected.PUT("/users/me", userHandler.Update)
-		protected.DELETE("/users/me", userHandler.Delete) // Soft delete
+		protected.DELETE("/users/me", noImpersonation, freshAuth, userHandler.Delete) // Soft delete
+		protected.GET("/users/me/login-history", loginHistoryHandler.Me)
+		if d.QuotaUC != nil {
+			protected.GET("/users/me/usage", quotaHandler.Me)
+		}
+
+		// ganti email: link verifikasi ke alamat baru, email baru dipakai setelah dikonfirmasi
+		protected.POST("/users/me/email", noImpersonation, emailChangeHandler.Request)
+		protected.POST("/users/me/verify-email", emailChangeHandler.Confirm)
 
-		// Tambahan untuk verify email jika change email
-		protected.POST("/users/me/verify-email", userHandler.VerifyEmail) // Asumsikan method baru di UserHandler
+		// nomor HP (kode verifikasi via SMS / WhatsApp) + channel untuk OTP & alert keamanan
+		protected.PUT("/users/me/phone", noImpersonation, limitOTP, phoneHandler.Set)
+		protected.POST("/users/me/phone/verify", noImpersonation, limitOTP, phoneHandler.Verify)
+		protected.DELETE("/users/me/phone", noImpersonation, freshAuth, phoneHandler.Remove)
+		protected.PUT("/users/me/notification-channel", noImpersonation, phoneHandler.SetChannel)
 
 		// Tambahan untuk session management
 		protected.GET("/auth/sessions", sessionHandler.List)
@@ -125,14 +250,28 @@ func RegisterRoutes(r *gin.Engine, d RouteDeps) {
 	admin := r.Group("/v1")
 	admin.Use(
 		middleware.AuthMiddleware(*d.JwtSigner),
+		rateLimit("default", middleware.RateLimitByUser),
+		middleware.AuditImpersonation(d.ImpersonationUC),
 		middleware.RequireRole("admin"),
+		requirePasswordChange,
 	)
 	{
 		// user management
 		admin.GET("/users", userHandler.List) // optional, dengan pagination
 		admin.GET("/users/:id", userHandler.GetByID)
-		admin.PUT("/users/:id", userHandler.Update)
-		admin.DELETE("/users/:id/permanent", userHandler.PermanentDelete)
+		admin.PUT("/users/:id", freshAuth, userHandler.Update) // bisa mengubah email
+		admin.DELETE("/users/:id/permanent", freshAuth, userHandler.PermanentDelete)
+		admin.POST("/users/:id/impersonate", freshAuth, impersonationHandler.Start)
+		admin.POST("/users/:id/lock", accountLockHandler.Lock)
+		admin.POST("/users/:id/unlock", accountLockHandler.Unlock)
+		// reset password oleh admin: policy & history password tetap berlaku
+		admin.POST("/users/:id/password", freshAuth, passwordHandler.AdminReset)
+		// paksa ganti password saat request berikutnya, per user atau bulk (all = semua kecuali diri sendiri)
+		admin.POST("/users/:id/require-password-change", passwordHandler.RequireChange)
+		admin.POST("/users/require-password-change", freshAuth, passwordHandler.RequireChangeBulk)
+		admin.GET("/login-attempts", loginHistoryHandler.Search) // filter: user_id, ip, outcome, from, to
+		// metrik pool hashing password: antrian, waktu tunggu & request yang ditolak 503
+		admin.GET("/security/password-hashing", passwordHandler.HashingStats)
 
 		// Tambahan untuk assign role (jika belum include di update)
 		admin.POST("/users/:id/assign-role", roleHandler.AssignRole) // Method baru
@@ -142,6 +281,43 @@ func RegisterRoutes(r *gin.Engine, d RouteDeps) {
 		admin.POST("/roles", roleHandler.Create)
 		admin.PUT("/roles/:id", roleHandler.Update)
 		admin.DELETE("/roles/:id", roleHandler.Delete)
+
+		// quota: plan per role, override per subject (user / service_account / api_key)
+		if d.QuotaUC != nil {
+			admin.GET("/quota-plans", quotaHandler.ListPlans)
+			admin.PUT("/roles/:id/quota-plan", quotaHandler.SetRolePlan)
+			admin.DELETE("/roles/:id/quota-plan", quotaHandler.RemoveRolePlan)
+			admin.GET("/quotas/:type/:id/usage", quotaHandler.Usage)
+			admin.PUT("/quotas/:type/:id/override", quotaHandler.SetOverride)
+			admin.DELETE("/quotas/:type/:id/override", quotaHandler.RemoveOverride)
+		}
+
+		// email outbox: cek pesan gagal (status=dead) lalu kirim ulang
+		admin.GET("/email-outbox", emailOutboxHandler.List)
+		admin.GET("/email-outbox/:id", emailOutboxHandler.Get)
+		admin.POST("/email-outbox/:id/replay", emailOutboxHandler.Replay)
+	}
+
+	// =====================================================
+	// DEVELOPMENT ROUTES
+	// =====================================================
+	// mail catcher: lihat email keluar (OTP, link verifikasi, reset password)
+	// tanpa container MailHog; tanpa auth, jadi hanya aktif jika DEV_MAIL_CATCHER=true
+	// di development (DevMailUC nil selain itu)
+	if d.Config.IsDevelopment() && d.Config.DevMailCatcher && d.DevMailUC != nil {
+		devMailHandler := devmail.NewDevMailHandler(d.DevMailUC)
+
+		dev := r.Group("/v1/dev")
+		{
+			dev.GET("/mail", devMailHandler.List)
+			dev.GET("/mail/ui", devMailHandler.Inbox)
+			dev.GET("/mail/latest", devMailHandler.Latest) // ?to=user@example.com
+			dev.GET("/mail/:id", devMailHandler.Get)
+			dev.GET("/mail/:id/html", devMailHandler.HTML)
+			dev.GET("/mail/:id/text", devMailHandler.Text)
+			dev.DELETE("/mail", devMailHandler.Clear)
+			dev.POST("/mail/clear", devMailHandler.Clear) // form di /mail/ui
+		}
 	}
 
 	// =====================================================
@@ -0,0 +1,24 @@
+package auth
+
+import "time"
+
+type AccountLockAction string
+
+const (
+	AccountLockActionLock   AccountLockAction = "lock"
+	AccountLockActionUnlock AccountLockAction = "unlock"
+)
+
+// AccountLockEvent adalah jejak audit lock/unlock akun.
+// ActorID nil = dilakukan oleh sistem (lockout otomatis, cooldown, link email).
+type AccountLockEvent struct {
+	ID      uint64
+	UserID  uint64
+	ActorID *uint64
+
+	Action      AccountLockAction
+	Reason      string
+	LockedUntil *time.Time
+
+	CreatedAt time.Time
+}
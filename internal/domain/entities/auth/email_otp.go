@@ -2,16 +2,32 @@ package auth
 
 import "time"
 
+// Purpose OTP: kode untuk satu tujuan tidak bisa dipakai di tujuan lain
+const (
+	EmailOTPPurposeSignup        = "signup"
+	EmailOTPPurposeLogin         = "login" // step-up saat login berisiko
+	EmailOTPPurposePasswordReset = "password_reset"
+	EmailOTPPurposeEmailChange   = "email_change"
+)
+
 type EmailOTP struct {
 	ID uint64
 
-	Email string
+	Email   string
+	Purpose string
 
 	OTPHash   string
 	ExpiredAt time.Time
 
+	// Attempts = jumlah percobaan kode yang salah
+	Attempts int
+
 	IPAddress string
 	UserAgent string
 
 	CreatedAt time.Time
 }
+
+func (o *EmailOTP) IsExpired(now time.Time) bool {
+	return !now.Before(o.ExpiredAt)
+}
@@ -0,0 +1,56 @@
+package auth
+
+import "time"
+
+// Status pesan di outbox
+const (
+	EmailOutboxStatusPending    = "pending"    // menunggu dikirim / retry
+	EmailOutboxStatusProcessing = "processing" // sedang diambil worker (lease)
+	EmailOutboxStatusSent       = "sent"
+	EmailOutboxStatusDead       = "dead" // gagal setelah MaxAttempts, perlu replay manual
+)
+
+// Jenis pesan = method EmailSender yang dipanggil worker
+const (
+	EmailOutboxKindOTP                     = "otp"
+	EmailOutboxKindResetPassword           = "reset_password"
+	EmailOutboxKindPasswordChanged         = "password_changed"
+	EmailOutboxKindPasswordExpiry          = "password_expiry"
+	EmailOutboxKindEmailVerification       = "email_verification"
+	EmailOutboxKindLoginAlert              = "login_alert"
+	EmailOutboxKindAccountLocked           = "account_locked"
+	EmailOutboxKindEmailChangeVerification = "email_change_verification"
+	EmailOutboxKindEmailChangeNotice       = "email_change_notice"
+
+	// pesan teks ke nomor E.164 lewat MessageChannel, payload {"text": ...};
+	// lewat outbox juga supaya dapat retry yang sama dengan email.
+	// Nilainya sama dengan nama channel (valueobjects.ChannelSMS / ChannelWhatsApp)
+	EmailOutboxKindSMS      = "sms"
+	EmailOutboxKindWhatsApp = "whatsapp"
+)
+
+// EmailOutboxMessage ditulis dalam transaksi yang sama dengan perubahan bisnis,
+// lalu dikirim worker di background. Payload = JSON argumen EmailSender.
+type EmailOutboxMessage struct {
+	ID             uint64
+	IdempotencyKey string
+
+	Kind      string
+	Recipient string
+	// Locale bahasa template email penerima, kosong = EMAIL_LOCALE
+	Locale  string
+	Payload string
+
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	SentAt        *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (m *EmailOutboxMessage) IsDead() bool {
+	return m.Status == EmailOutboxStatusDead
+}
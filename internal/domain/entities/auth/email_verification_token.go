@@ -2,10 +2,34 @@ package auth
 
 import "time"
 
+// Tujuan token verifikasi email
+const (
+	EmailVerificationPurposeSignup = "signup"
+	// link ke alamat baru, email baru baru dipakai setelah dikonfirmasi
+	EmailVerificationPurposeChange = "change_email"
+	// link ke alamat lama untuk membatalkan / mengembalikan perubahan email
+	EmailVerificationPurposeRevert = "revert_email"
+	// kode ke nomor telepon baru; Email berisi nomor E.164 yang diverifikasi
+	EmailVerificationPurposePhone = "verify_phone"
+)
+
 type EmailVerificationToken struct {
-	ID        uint64
-	UserID    uint64
+	ID     uint64
+	UserID uint64
+
+	Purpose string
+	// Email adalah alamat yang dituju token: alamat baru (change_email)
+	// atau alamat lama yang akan dipulihkan (revert_email)
+	Email string
+
 	TokenHash string
 	ExpiresAt time.Time
 	CreatedAt time.Time
+
+	// Attempts = kode salah untuk token ini (metode otp)
+	Attempts int
+}
+
+func (t *EmailVerificationToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
 }
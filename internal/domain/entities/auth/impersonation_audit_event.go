@@ -0,0 +1,35 @@
+package auth
+
+import "time"
+
+type ImpersonationAction string
+
+const (
+	ImpersonationActionStart   ImpersonationAction = "start"
+	ImpersonationActionStop    ImpersonationAction = "stop"
+	ImpersonationActionRequest ImpersonationAction = "request"
+)
+
+// ImpersonationAuditEvent mencatat setiap start/stop impersonation dan setiap
+// request yang dilakukan admin atas nama user lain
+type ImpersonationAuditEvent struct {
+	ID        uint64
+	ActorID   uint64 // admin yang melakukan impersonation
+	SubjectID uint64 // user yang di-impersonate
+
+	Action ImpersonationAction
+	Reason string
+
+	// TokenID = jti token impersonation (start / stop); event stop membuat token ditolak
+	TokenID string
+
+	// hanya terisi untuk ImpersonationActionRequest
+	Method     string
+	Path       string
+	StatusCode int
+
+	IPAddress string
+	UserAgent string
+
+	CreatedAt time.Time
+}
@@ -2,6 +2,31 @@ package auth
 
 import "time"
 
+// Outcome login attempt (disimpan di Reason)
+const (
+	LoginOutcomeSuccess            = "success"
+	LoginOutcomeInvalidCredentials = "invalid_credentials"
+	LoginOutcomeUnknownIdentifier  = "unknown_identifier"
+	LoginOutcomeInvalidStepUpCode  = "invalid_step_up_code"
+	LoginOutcomeRiskBlocked        = "risk_blocked"
+	LoginOutcomeStepUpRequired     = "step_up_required"
+	LoginOutcomeAccountLocked      = "account_locked"
+	LoginOutcomeThrottled          = "throttled"
+	LoginOutcomeEmailUnverified    = "email_unverified"
+)
+
+// LockoutCountedOutcomes adalah kegagalan yang menandakan tebakan kredensial
+// dan dihitung untuk backoff/lockout. Request yang ditolak karena throttled
+// atau akun terkunci tetap dicatat tapi tidak memperpanjang lockout.
+func LockoutCountedOutcomes() []string {
+	return []string{
+		LoginOutcomeInvalidCredentials,
+		LoginOutcomeUnknownIdentifier,
+		LoginOutcomeInvalidStepUpCode,
+		LoginOutcomeRiskBlocked,
+	}
+}
+
 type LoginAttempt struct {
 	ID       uint64
 	Identity string
@@ -0,0 +1,40 @@
+package auth
+
+import "time"
+
+type RiskDecision string
+
+const (
+	RiskDecisionAllow  RiskDecision = "allow"
+	RiskDecisionStepUp RiskDecision = "step_up"
+	RiskDecisionBlock  RiskDecision = "block"
+)
+
+// LoginRiskEvent adalah jejak audit hasil evaluasi risiko satu login
+type LoginRiskEvent struct {
+	ID     uint64
+	UserID uint64
+
+	IPAddress string
+	UserAgent string
+
+	Country   string
+	City      string
+	Latitude  *float64
+	Longitude *float64
+
+	Score    int
+	Decision RiskDecision
+	Reasons  []string
+
+	// Completed: login benar-benar selesai (allow, atau step-up berhasil).
+	// Hanya event ini yang jadi riwayat negara / lokasi terpercaya, supaya
+	// percobaan step-up yang gagal tidak membuat lokasi penyerang "dikenal"
+	Completed bool
+
+	CreatedAt time.Time
+}
+
+func (e *LoginRiskEvent) HasLocation() bool {
+	return e.Latitude != nil && e.Longitude != nil
+}
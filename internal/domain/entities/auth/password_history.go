@@ -0,0 +1,13 @@
+package auth
+
+import "time"
+
+// PasswordHistory: hash password lama user, dipakai untuk menolak password yang pernah dipakai
+type PasswordHistory struct {
+	ID     uint64
+	UserID uint64
+
+	// PasswordHash format PasswordHasher lengkap, termasuk versi pepper saat di-hash
+	PasswordHash string
+	CreatedAt    time.Time
+}
@@ -2,12 +2,28 @@ package auth
 
 import "time"
 
+// PasswordResetToken: TokenHash = hash token link atau hash kode OTP (tergantung metode reset)
 type PasswordResetToken struct {
-	ID        uint64
-	UserID    uint64
+	ID     uint64
+	UserID uint64
 
 	TokenHash string
 	ExpiresAt time.Time
 	Used      bool
+	UsedAt    *time.Time
+
+	// Attempts = kode OTP salah untuk token ini
+	Attempts int
+
+	IPAddress string
 	CreatedAt time.Time
 }
+
+func (t *PasswordResetToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// IsActive: belum dipakai dan belum expired
+func (t *PasswordResetToken) IsActive(now time.Time) bool {
+	return !t.Used && !t.IsExpired(now)
+}
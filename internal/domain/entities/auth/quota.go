@@ -0,0 +1,51 @@
+package auth
+
+import "time"
+
+// QuotaPlan melekat ke role; limit 0 = tanpa batas
+type QuotaPlan struct {
+	ID     uint64
+	RoleID uint64
+	Name   string
+
+	DailyLimit   int64
+	MonthlyLimit int64
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// QuotaOverride dari admin untuk satu subject (user / service account / API key).
+// Limit nil = ikut plan.
+type QuotaOverride struct {
+	ID          uint64
+	SubjectType string
+	SubjectID   string
+
+	DailyLimit   *int64
+	MonthlyLimit *int64
+
+	Reason    string
+	CreatedBy uint64
+	ExpiresAt *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (o *QuotaOverride) IsActive(now time.Time) bool {
+	return o.ExpiresAt == nil || now.Before(*o.ExpiresAt)
+}
+
+// QuotaUsage adalah snapshot counter Redis yang di-flush ke Postgres
+type QuotaUsage struct {
+	ID          uint64
+	SubjectType string
+	SubjectID   string
+
+	Period      string
+	PeriodStart time.Time
+	Count       int64
+
+	UpdatedAt time.Time
+}
@@ -1,6 +1,10 @@
 package auth
 
-import "time"
+import (
+	"time"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+)
 
 type User struct {
 	ID uint64
@@ -11,9 +15,30 @@ type User struct {
 	PasswordHash  string
 	Name          *string
 
+	// MustChangePassword: akses dibatasi sampai password diganti
+	// (password ditemukan di data breach, sudah expired, atau diminta admin)
+	MustChangePassword bool
+	// PasswordChangedAt dasar perhitungan umur password (PASSWORD_MAX_AGE_DAYS)
+	PasswordChangedAt time.Time
+	// PasswordExpiryWarnedAt: email peringatan expiry sudah dikirim untuk password saat ini
+	PasswordExpiryWarnedAt *time.Time
+
+	// Phone dalam format E.164 (+6281234567890), nil = belum diisi
+	Phone         *string
+	PhoneVerified bool
+	// PreferredChannel untuk OTP, MFA dan alert keamanan: email | sms | whatsapp
+	PreferredChannel string
+	// Locale bahasa template email (id, en-US, ...), kosong = EMAIL_LOCALE
+	Locale string
+
 	RoleID uint64
 	Locked bool
 
+	// LockedUntil nil = terkunci sampai di-unlock manual (admin / link email)
+	LockedUntil     *time.Time
+	LockReason      string
+	UnlockTokenHash *string
+
 	CreatedAt time.Time
 	UpdatedAt *time.Time
 	DeletedAt *time.Time
@@ -25,8 +50,53 @@ func (u *User) VerifyEmail() {
 	u.EmailVerified = true
 }
 
-func (u *User) ChangePassword(hash string) {
+// SetPhone: nomor baru selalu harus diverifikasi ulang; channel non-email
+// kembali ke email sampai nomor baru terverifikasi
+func (u *User) SetPhone(phone *string) {
+	u.Phone = phone
+	u.PhoneVerified = false
+	u.PreferredChannel = valueobjects.ChannelEmail
+}
+
+func (u *User) VerifyPhone() {
+	u.PhoneVerified = true
+}
+
+// NotificationChannel: channel yang benar-benar dipakai, SMS / WhatsApp hanya
+// jika nomor sudah terverifikasi
+func (u *User) NotificationChannel() string {
+	switch u.PreferredChannel {
+	case valueobjects.ChannelSMS, valueobjects.ChannelWhatsApp:
+		if u.Phone != nil && u.PhoneVerified {
+			return u.PreferredChannel
+		}
+	}
+	return valueobjects.ChannelEmail
+}
+
+func (u *User) ChangePassword(hash string, now time.Time) {
 	u.PasswordHash = hash
+	u.MustChangePassword = false
+	u.PasswordChangedAt = now
+	u.PasswordExpiryWarnedAt = nil
+}
+
+// PasswordExpiresAt nil jika password tidak punya umur maksimal (maxAge 0)
+func (u *User) PasswordExpiresAt(maxAge time.Duration) *time.Time {
+	if maxAge <= 0 || u.PasswordChangedAt.IsZero() {
+		return nil
+	}
+	t := u.PasswordChangedAt.Add(maxAge)
+	return &t
+}
+
+func (u *User) PasswordExpired(maxAge time.Duration, now time.Time) bool {
+	expiresAt := u.PasswordExpiresAt(maxAge)
+	return expiresAt != nil && !now.Before(*expiresAt)
+}
+
+func (u *User) RequirePasswordChange() {
+	u.MustChangePassword = true
 }
 
 func (u *User) SoftDelete(now time.Time) {
@@ -36,3 +106,24 @@ func (u *User) SoftDelete(now time.Time) {
 func (u *User) IsDeleted() bool {
 	return u.DeletedAt != nil
 }
+
+// IsLocked: lock dengan cooldown otomatis berakhir setelah LockedUntil
+func (u *User) IsLocked(now time.Time) bool {
+	if !u.Locked {
+		return false
+	}
+	return u.LockedUntil == nil || now.Before(*u.LockedUntil)
+}
+
+func (u *User) Lock(reason string, until *time.Time) {
+	u.Locked = true
+	u.LockReason = reason
+	u.LockedUntil = until
+}
+
+func (u *User) Unlock() {
+	u.Locked = false
+	u.LockReason = ""
+	u.LockedUntil = nil
+	u.UnlockTokenHash = nil
+}
@@ -0,0 +1,22 @@
+package valueobjects
+
+import "time"
+
+const (
+	ChallengeProviderPoW     = "pow"     // proof-of-work self-hosted
+	ChallengeProviderCaptcha = "captcha" // hCaptcha / Turnstile / reCAPTCHA
+)
+
+// Challenge dikirim ke client saat request publik butuh bukti "bukan bot"
+type Challenge struct {
+	Provider string
+
+	// captcha: site key untuk widget di client
+	SiteKey string
+
+	// pow: cari nonce sehingga sha256(Token + ":" + nonce) punya
+	// minimal Difficulty leading zero bit, lalu kirim "Token:nonce"
+	Token      string
+	Difficulty int
+	ExpiresAt  time.Time
+}
@@ -15,3 +15,16 @@ func NewEmail(value string) (Email, error) {
 	}
 	return Email(value), nil
 }
+
+// Masked: "jo***@example.com", untuk ditampilkan di notifikasi
+func (e Email) Masked() string {
+	local, domain, ok := strings.Cut(string(e), "@")
+	if !ok {
+		return "***"
+	}
+
+	if len(local) > 2 {
+		local = local[:2]
+	}
+	return local + "***@" + domain
+}
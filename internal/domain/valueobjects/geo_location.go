@@ -0,0 +1,33 @@
+package valueobjects
+
+import "math"
+
+const earthRadiusKm = 6371.0
+
+type GeoLocation struct {
+	Country   string
+	City      string
+	Latitude  float64
+	Longitude float64
+}
+
+// DistanceKm menghitung jarak great-circle (haversine) antar dua lokasi
+func (g GeoLocation) DistanceKm(other GeoLocation) float64 {
+	lat1 := g.Latitude * math.Pi / 180
+	lat2 := other.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (other.Longitude - g.Longitude) * math.Pi / 180
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+type IPReputation string
+
+const (
+	IPReputationClean      IPReputation = "clean"
+	IPReputationSuspicious IPReputation = "suspicious"
+	IPReputationMalicious  IPReputation = "malicious"
+)
@@ -42,7 +42,7 @@ var (
 	ErrPasswordNoDigit   = errors.New("password must contain at least one digit")
 	ErrPasswordNoSpecial = errors.New("password must contain at least one special character")
 	ErrPasswordCommon    = errors.New("password is too common or easily guessable")
-	ErrPasswordPwned     = errors.New("password has been exposed in a data breach") // optional future integration
+	ErrPasswordPwned     = errors.New("password has been exposed in a data breach")
 )
 
 // NewPassword membuat hashed password dari plain text dengan validasi ketat.
@@ -52,31 +52,11 @@ func NewPassword(plain string, config *PasswordConfig) (Password, error) {
 		config = &DefaultPasswordConfig
 	}
 
-	// 1. Validasi panjang
-	if len(plain) < 12 {
-		return "", ErrPasswordTooShort
-	}
-	if len(plain) > 128 {
-		return "", ErrPasswordTooLong
-	}
-
-	// 2. Validasi komposisi karakter (entropy tinggi)
-	if !hasLower(plain) {
-		return "", ErrPasswordNoLower
-	}
-	if !hasUpper(plain) {
-		return "", ErrPasswordNoUpper
-	}
-	if !hasDigit(plain) {
-		return "", ErrPasswordNoDigit
-	}
-	if !hasSpecial(plain) {
-		return "", ErrPasswordNoSpecial
+	// 1-3. Validasi panjang & komposisi (policy dari config dicek PasswordPolicyUsecase)
+	if err := DefaultPasswordPolicy().Validate(plain, PasswordUserInfo{}); err != nil {
+		return "", err
 	}
 
-	// 3. Optional: cek common password (bisa tambah zxcvbn atau list top 100k)
-	// if isCommonPassword(plain) { return "", ErrPasswordCommon }
-
 	// 4. Generate salt acak
 	salt := make([]byte, config.SaltLen)
 	if _, err := randReader.Read(salt); err != nil {
@@ -0,0 +1,334 @@
+package valueobjects
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+var ErrPasswordPolicy = errors.New("password does not meet the password policy")
+
+// Mode policy password
+const (
+	// PasswordPolicyModeNIST: panjang, blocklist & konteks user saja, tanpa aturan komposisi (NIST SP 800-63B)
+	PasswordPolicyModeNIST = "nist"
+	// PasswordPolicyModeComposition: + wajib huruf kecil / besar / angka / simbol sesuai Require*
+	PasswordPolicyModeComposition = "composition"
+)
+
+// Kode rule, stabil supaya frontend bisa memetakan ke teks terjemahan sendiri
+const (
+	PasswordRuleMinLength = "min_length"
+	PasswordRuleMaxLength = "max_length"
+	PasswordRuleLower     = "lowercase"
+	PasswordRuleUpper     = "uppercase"
+	PasswordRuleDigit     = "digit"
+	PasswordRuleSpecial   = "special"
+	PasswordRuleRepeated  = "repeated"
+	PasswordRuleBlocklist = "blocklist"
+	PasswordRuleUserInfo  = "user_info"
+	PasswordRuleBreached  = "breached"
+	PasswordRuleStrength  = "strength"
+	PasswordRuleReused    = "reused"
+)
+
+// PasswordRule: satu aturan policy, juga dipakai sebagai violation
+type PasswordRule struct {
+	Code    string
+	Message string
+}
+
+// PasswordPolicyError berisi SEMUA rule yang dilanggar, bukan hanya yang pertama
+type PasswordPolicyError struct {
+	Violations []PasswordRule
+}
+
+func (e *PasswordPolicyError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e *PasswordPolicyError) Unwrap() error {
+	return ErrPasswordPolicy
+}
+
+// Is: errors.Is(err, ErrPasswordPwned) true kalau password ditemukan di data breach
+func (e *PasswordPolicyError) Is(target error) bool {
+	if target != ErrPasswordPwned {
+		return false
+	}
+	for _, v := range e.Violations {
+		if v.Code == PasswordRuleBreached {
+			return true
+		}
+	}
+	return false
+}
+
+// PasswordUserInfo: data user untuk cek password tidak mengandung username / email
+type PasswordUserInfo struct {
+	Username string
+	Email    string
+}
+
+// PasswordBlocklist: password umum / terlarang, disimpan lowercase
+type PasswordBlocklist map[string]struct{}
+
+func NewPasswordBlocklist(words []string) PasswordBlocklist {
+	list := make(PasswordBlocklist, len(words))
+	for _, w := range words {
+		if w = strings.ToLower(strings.TrimSpace(w)); w != "" {
+			list[w] = struct{}{}
+		}
+	}
+	return list
+}
+
+// Contains tidak peka huruf besar/kecil dan mengabaikan angka / simbol di
+// akhir, jadi "Password123!" tetap kena kalau "password" ada di list
+func (b PasswordBlocklist) Contains(plain string) bool {
+	if len(b) == 0 {
+		return false
+	}
+
+	lower := strings.ToLower(plain)
+	if _, ok := b[lower]; ok {
+		return true
+	}
+
+	base := strings.TrimRightFunc(lower, func(r rune) bool {
+		return unicode.IsDigit(r) || unicode.IsPunct(r) || unicode.IsSymbol(r)
+	})
+	if base == "" || base == lower {
+		return false
+	}
+	_, ok := b[base]
+	return ok
+}
+
+// PasswordPolicy dikonfigurasi dari config (PASSWORD_POLICY_*), dipakai register,
+// change dan reset password
+type PasswordPolicy struct {
+	Mode      string
+	MinLength int
+	MaxLength int // 0 = tanpa batas
+
+	// hanya berlaku di mode composition
+	RequireLower   bool
+	RequireUpper   bool
+	RequireDigit   bool
+	RequireSpecial bool
+
+	// MaxRepeated: karakter sama berturut-turut maksimal, 0 = tidak dicek
+	MaxRepeated int
+
+	// DisallowUserInfo: password tidak boleh mengandung username / bagian lokal email
+	DisallowUserInfo bool
+
+	Blocklist PasswordBlocklist
+
+	// BreachCheck: password dicek ke dataset breach (HIBP). Tidak dicek di Validate
+	// karena butuh dataset / API, lihat PasswordPolicyUsecase
+	BreachCheck bool
+	// BreachMinCount: jumlah kemunculan minimal di data breach sebelum ditolak
+	BreachMinCount int
+
+	// MinStrength: skor estimasi kekuatan (0-4) minimal, 0 = tidak dicek.
+	// Sama seperti breach, dicek di PasswordPolicyUsecase
+	MinStrength int
+
+	// HistorySize: jumlah password lama (selain password saat ini) yang tidak boleh
+	// dipakai lagi, 0 = hanya password saat ini. Dicek di PasswordPolicyUsecase
+	HistorySize int
+
+	// MaxAge: umur maksimal password sebelum wajib diganti, 0 = tidak pernah expired
+	MaxAge time.Duration
+	// ExpiryWarning: peringatan dikirim sejauh ini sebelum password expired
+	ExpiryWarning time.Duration
+}
+
+// DefaultPasswordPolicy sama dengan aturan lama NewPassword: 12-128 karakter + komposisi
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		Mode:             PasswordPolicyModeComposition,
+		MinLength:        12,
+		MaxLength:        128,
+		RequireLower:     true,
+		RequireUpper:     true,
+		RequireDigit:     true,
+		RequireSpecial:   true,
+		DisallowUserInfo: true,
+	}
+}
+
+// Rules daftar aturan yang aktif, untuk ditampilkan frontend
+func (p PasswordPolicy) Rules() []PasswordRule {
+	codes := []string{PasswordRuleMinLength}
+	if p.MaxLength > 0 {
+		codes = append(codes, PasswordRuleMaxLength)
+	}
+	if p.composition() {
+		if p.RequireLower {
+			codes = append(codes, PasswordRuleLower)
+		}
+		if p.RequireUpper {
+			codes = append(codes, PasswordRuleUpper)
+		}
+		if p.RequireDigit {
+			codes = append(codes, PasswordRuleDigit)
+		}
+		if p.RequireSpecial {
+			codes = append(codes, PasswordRuleSpecial)
+		}
+	}
+	if p.MaxRepeated > 0 {
+		codes = append(codes, PasswordRuleRepeated)
+	}
+	if len(p.Blocklist) > 0 {
+		codes = append(codes, PasswordRuleBlocklist)
+	}
+	if p.DisallowUserInfo {
+		codes = append(codes, PasswordRuleUserInfo)
+	}
+	if p.BreachCheck {
+		codes = append(codes, PasswordRuleBreached)
+	}
+	if p.MinStrength > 0 {
+		codes = append(codes, PasswordRuleStrength)
+	}
+	if p.HistorySize > 0 {
+		codes = append(codes, PasswordRuleReused)
+	}
+
+	rules := make([]PasswordRule, len(codes))
+	for i, code := range codes {
+		rules[i] = p.Rule(code)
+	}
+	return rules
+}
+
+// Validate return *PasswordPolicyError (errors.Is ErrPasswordPolicy) berisi semua violation
+func (p PasswordPolicy) Validate(plain string, user PasswordUserInfo) error {
+	var codes []string
+
+	// panjang dihitung per karakter (rune), bukan byte
+	length := utf8.RuneCountInString(plain)
+	if length < p.MinLength {
+		codes = append(codes, PasswordRuleMinLength)
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		codes = append(codes, PasswordRuleMaxLength)
+	}
+
+	if p.composition() {
+		if p.RequireLower && !hasLower(plain) {
+			codes = append(codes, PasswordRuleLower)
+		}
+		if p.RequireUpper && !hasUpper(plain) {
+			codes = append(codes, PasswordRuleUpper)
+		}
+		if p.RequireDigit && !hasDigit(plain) {
+			codes = append(codes, PasswordRuleDigit)
+		}
+		if p.RequireSpecial && !hasSpecial(plain) {
+			codes = append(codes, PasswordRuleSpecial)
+		}
+	}
+
+	if p.MaxRepeated > 0 && longestRun(plain) > p.MaxRepeated {
+		codes = append(codes, PasswordRuleRepeated)
+	}
+	if p.Blocklist.Contains(plain) {
+		codes = append(codes, PasswordRuleBlocklist)
+	}
+	if p.DisallowUserInfo && containsUserInfo(plain, user) {
+		codes = append(codes, PasswordRuleUserInfo)
+	}
+
+	if len(codes) == 0 {
+		return nil
+	}
+
+	violations := make([]PasswordRule, len(codes))
+	for i, code := range codes {
+		violations[i] = p.Rule(code)
+	}
+	return &PasswordPolicyError{Violations: violations}
+}
+
+func (p PasswordPolicy) composition() bool {
+	return p.Mode == PasswordPolicyModeComposition
+}
+
+// Rule aturan dengan pesan sesuai konfigurasi policy
+func (p PasswordPolicy) Rule(code string) PasswordRule {
+	var msg string
+	switch code {
+	case PasswordRuleMinLength:
+		msg = fmt.Sprintf("password must be at least %d characters", p.MinLength)
+	case PasswordRuleMaxLength:
+		msg = fmt.Sprintf("password cannot exceed %d characters", p.MaxLength)
+	case PasswordRuleLower:
+		msg = "password must contain at least one lowercase letter"
+	case PasswordRuleUpper:
+		msg = "password must contain at least one uppercase letter"
+	case PasswordRuleDigit:
+		msg = "password must contain at least one digit"
+	case PasswordRuleSpecial:
+		msg = "password must contain at least one special character"
+	case PasswordRuleRepeated:
+		msg = fmt.Sprintf("password cannot repeat the same character more than %d times in a row", p.MaxRepeated)
+	case PasswordRuleBlocklist:
+		msg = "password is too common or easily guessable"
+	case PasswordRuleUserInfo:
+		msg = "password cannot contain your username or email"
+	case PasswordRuleBreached:
+		msg = ErrPasswordPwned.Error()
+	case PasswordRuleStrength:
+		msg = fmt.Sprintf("password strength must be at least %d of 4", p.MinStrength)
+	case PasswordRuleReused:
+		msg = fmt.Sprintf("password cannot be one of your last %d passwords", p.HistorySize)
+	}
+	return PasswordRule{Code: code, Message: msg}
+}
+
+func longestRun(s string) int {
+	longest, run := 0, 0
+	var prev rune
+	for i, r := range []rune(s) {
+		if i > 0 && r == prev {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+		prev = r
+	}
+	return longest
+}
+
+// containsUserInfo: potongan < 3 karakter diabaikan supaya tidak false positive
+func containsUserInfo(plain string, user PasswordUserInfo) bool {
+	lower := strings.ToLower(plain)
+
+	parts := []string{user.Username}
+	if local, _, ok := strings.Cut(user.Email, "@"); ok {
+		parts = append(parts, local)
+	}
+
+	for _, part := range parts {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if utf8.RuneCountInString(part) >= 3 && strings.Contains(lower, part) {
+			return true
+		}
+	}
+	return false
+}
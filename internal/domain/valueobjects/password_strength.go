@@ -0,0 +1,11 @@
+package valueobjects
+
+// PasswordStrength hasil estimasi kekuatan password (gaya zxcvbn)
+type PasswordStrength struct {
+	Score        int     // 0 (sangat mudah ditebak) - 4 (sangat sulit ditebak)
+	GuessesLog10 float64 // log10 perkiraan jumlah tebakan
+	// CrackTime perkiraan waktu crack offline dengan hash lambat (10rb tebakan/detik), mis. "3 hours"
+	CrackTime   string
+	Warning     string
+	Suggestions []string
+}
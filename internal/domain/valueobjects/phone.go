@@ -0,0 +1,77 @@
+package valueobjects
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidPhoneNumber = errors.New("invalid phone number")
+
+// Channel pengiriman OTP / MFA / alert keamanan
+const (
+	ChannelEmail    = "email"
+	ChannelSMS      = "sms"
+	ChannelWhatsApp = "whatsapp"
+)
+
+func IsValidChannel(channel string) bool {
+	switch channel {
+	case ChannelEmail, ChannelSMS, ChannelWhatsApp:
+		return true
+	}
+	return false
+}
+
+// PhoneNumber selalu dalam format E.164: "+" diikuti 8-15 digit, tanpa spasi
+type PhoneNumber string
+
+// NewPhoneNumber menormalisasi input user ke E.164.
+// "0812-3456-7890" + defaultCountryCode "62" → "+6281234567890",
+// "0062 812..." → "+62812...", "+62 (812) 3456 7890" → "+6281234567890"
+func NewPhoneNumber(raw, defaultCountryCode string) (PhoneNumber, error) {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(raw) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		case r == ' ', r == '-', r == '.', r == '(', r == ')':
+			// pemisah yang umum diketik user
+		default:
+			return "", ErrInvalidPhoneNumber
+		}
+	}
+	number := b.String()
+
+	switch {
+	case strings.HasPrefix(number, "+"):
+	case strings.HasPrefix(number, "00"):
+		number = "+" + number[2:]
+	case strings.HasPrefix(number, "0") && defaultCountryCode != "":
+		// nomor nasional: buang trunk prefix 0
+		number = "+" + strings.TrimPrefix(defaultCountryCode, "+") + number[1:]
+	default:
+		return "", ErrInvalidPhoneNumber
+	}
+
+	digits := number[1:]
+	if len(digits) < 8 || len(digits) > 15 || digits[0] == '0' {
+		return "", ErrInvalidPhoneNumber
+	}
+
+	return PhoneNumber(number), nil
+}
+
+func (p PhoneNumber) String() string {
+	return string(p)
+}
+
+// Masked: "+62*******7890", untuk ditampilkan di response / notifikasi
+func (p PhoneNumber) Masked() string {
+	s := string(p)
+	if len(s) < 8 {
+		return "***"
+	}
+	return s[:3] + strings.Repeat("*", len(s)-7) + s[len(s)-4:]
+}
@@ -0,0 +1,88 @@
+package valueobjects
+
+import "time"
+
+// Jenis identitas yang dikenai kuota (klaim "sub_type" di access token)
+type QuotaSubjectType string
+
+const (
+	QuotaSubjectUser           QuotaSubjectType = "user"
+	QuotaSubjectServiceAccount QuotaSubjectType = "service_account"
+	QuotaSubjectAPIKey         QuotaSubjectType = "api_key"
+)
+
+// QuotaSubject: identitas dari AuthMiddleware, ID = public id
+type QuotaSubject struct {
+	Type QuotaSubjectType
+	ID   string
+}
+
+type QuotaPeriod string
+
+const (
+	QuotaPeriodDaily   QuotaPeriod = "daily"
+	QuotaPeriodMonthly QuotaPeriod = "monthly"
+)
+
+// Bounds mengembalikan awal & akhir periode (UTC) yang memuat now
+func (p QuotaPeriod) Bounds(now time.Time) (time.Time, time.Time) {
+	now = now.UTC()
+
+	switch p {
+	case QuotaPeriodMonthly:
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	default:
+		start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 0, 1)
+	}
+}
+
+// QuotaLimits: 0 = tanpa batas
+type QuotaLimits struct {
+	Daily   int64
+	Monthly int64
+}
+
+func (l QuotaLimits) For(p QuotaPeriod) int64 {
+	if p == QuotaPeriodMonthly {
+		return l.Monthly
+	}
+	return l.Daily
+}
+
+type QuotaWindow struct {
+	Period    QuotaPeriod
+	Limit     int64 // 0 = tanpa batas
+	Used      int64
+	Remaining int64 // -1 jika tanpa batas
+	ResetAt   time.Time
+}
+
+func (w QuotaWindow) Exceeded() bool {
+	return w.Limit > 0 && w.Used > w.Limit
+}
+
+type QuotaStatus struct {
+	Subject QuotaSubject
+	Plan    string
+	// Source: "plan", "override" atau "default"
+	Source  string
+	Allowed bool
+	Windows []QuotaWindow
+}
+
+// Tightest: window terbatas dengan sisa paling sedikit (untuk header)
+func (s *QuotaStatus) Tightest() *QuotaWindow {
+	var tightest *QuotaWindow
+	for i := range s.Windows {
+		w := &s.Windows[i]
+		if w.Limit <= 0 {
+			continue
+		}
+		if tightest == nil || w.Remaining < tightest.Remaining {
+			tightest = w
+		}
+	}
+	return tightest
+}
@@ -0,0 +1,39 @@
+package valueobjects
+
+import "time"
+
+type RateLimitAlgorithm string
+
+const (
+	RateLimitTokenBucket   RateLimitAlgorithm = "token_bucket"
+	RateLimitSlidingWindow RateLimitAlgorithm = "sliding_window" // sliding window counter
+	RateLimitGCRA          RateLimitAlgorithm = "gcra"           // generic cell rate algorithm
+)
+
+// RateLimitPolicy: Limit request per Window.
+// Burst hanya dipakai token bucket & GCRA (0 = sama dengan Limit).
+type RateLimitPolicy struct {
+	Name      string
+	Algorithm RateLimitAlgorithm
+	Limit     int
+	Window    time.Duration
+	Burst     int
+}
+
+func (p RateLimitPolicy) BurstSize() int {
+	if p.Burst > 0 {
+		return p.Burst
+	}
+	return p.Limit
+}
+
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+
+	// RetryAfter: kapan request berikutnya boleh (hanya jika !Allowed)
+	RetryAfter time.Duration
+	// ResetAfter: kapan kuota kembali penuh
+	ResetAfter time.Duration
+}
@@ -2,8 +2,30 @@ package valueobjects
 
 import "time"
 
+// Authentication Context Class Reference (klaim "acr")
+const (
+	ACRPassword    = "pwd" // single factor (password)
+	ACRMultiFactor = "mfa" // password + second factor / step-up
+)
+
 type TokenPayload struct {
 	UserID    string
 	TokenID   string
 	ExpiresAt time.Time
+
+	// AuthTime adalah waktu user terakhir benar-benar membuktikan identitas
+	// (bukan waktu token di-refresh). Zero value = tidak diketahui.
+	AuthTime time.Time
+	ACR      string
+
+	// ActorID adalah public id admin dari klaim "act" (RFC 8693),
+	// hanya terisi pada token impersonation
+	ActorID string
+
+	// SubjectType dari klaim "sub_type": user (default), service_account, api_key
+	SubjectType string
+}
+
+func (p *TokenPayload) IsImpersonated() bool {
+	return p.ActorID != ""
 }
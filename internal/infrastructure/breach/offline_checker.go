@@ -0,0 +1,211 @@
+package breach
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+)
+
+const (
+	// prefixCount is the number of 5-hex-char range prefixes (16^5)
+	prefixCount = 1 << 20
+
+	indexMagic      = "PWNDIDX1"
+	indexHeaderSize = len(indexMagic) + 8 + 8 // magic, dataset size, dataset mtime
+)
+
+var ErrDatasetNotSorted = errors.New("breach dataset is not ordered by hash")
+
+// OfflineChecker looks passwords up in a locally stored Pwned Passwords
+// dataset, so no hash (not even a prefix) leaves the server. Two layouts
+// are supported:
+//
+//   - a directory of range files named by the 5-char prefix ("ABCDE" or
+//     "ABCDE.txt") holding "SUFFIX:COUNT" lines, as served by the range API;
+//   - a single file of "HASH:COUNT" lines ordered by hash. A sidecar index
+//     (path + ".idx") stores the byte offset of every prefix, so a lookup is
+//     two small reads instead of a scan.
+type OfflineChecker struct {
+	dir string
+
+	dataset *os.File
+	index   *os.File
+}
+
+// NewOfflineChecker opens the dataset at path. For the single-file layout a
+// missing or stale index is rebuilt first, which reads the whole dataset
+// once; run BuildIndex ahead of deployment to keep startup fast.
+func NewOfflineChecker(path string) (ports.BreachedPasswordChecker, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return &OfflineChecker{dir: path}, nil
+	}
+
+	if fresh, err := indexFresh(path, info); err != nil || !fresh {
+		if err := BuildIndex(path); err != nil {
+			return nil, err
+		}
+	}
+
+	dataset, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := os.Open(indexPath(path))
+	if err != nil {
+		dataset.Close()
+		return nil, err
+	}
+
+	return &OfflineChecker{dataset: dataset, index: index}, nil
+}
+
+func (c *OfflineChecker) Count(ctx context.Context, password string) (int, error) {
+	prefix, suffix := hashPassword(password)
+
+	if c.dir != "" {
+		return c.countInRangeFile(prefix, suffix)
+	}
+
+	n, err := strconv.ParseUint(prefix, 16, 32)
+	if err != nil {
+		return 0, err
+	}
+
+	// offsets n and n+1 bound the block of lines with this prefix
+	var offsets [16]byte
+	if _, err := c.index.ReadAt(offsets[:], int64(indexHeaderSize)+int64(n)*8); err != nil {
+		return 0, err
+	}
+	start := int64(binary.BigEndian.Uint64(offsets[:8]))
+	end := int64(binary.BigEndian.Uint64(offsets[8:]))
+	if end <= start {
+		return 0, nil
+	}
+
+	section := io.NewSectionReader(c.dataset, start, end-start)
+	return findInRange(section, prefix+suffix)
+}
+
+func (c *OfflineChecker) countInRangeFile(prefix, suffix string) (int, error) {
+	for _, name := range []string{prefix + ".txt", prefix} {
+		f, err := os.Open(filepath.Join(c.dir, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		count, err := findInRange(f, suffix)
+		f.Close()
+		return count, err
+	}
+
+	// no range file means no hash with this prefix
+	return 0, nil
+}
+
+// BuildIndex writes the prefix offset index for a dataset ordered by hash.
+// The index is written to a temp file and renamed, so a running process never
+// sees a partial index.
+func BuildIndex(datasetPath string) error {
+	f, err := os.Open(datasetPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	offsets := make([]uint64, prefixCount+1)
+	next := 0 // first prefix whose offset is not set yet
+	var pos uint64
+
+	reader := bufio.NewReaderSize(f, 1<<20)
+	for {
+		line, err := reader.ReadSlice('\n')
+		if errors.Is(err, bufio.ErrBufferFull) {
+			return fmt.Errorf("breach dataset: line too long at offset %d", pos)
+		}
+		if len(line) >= prefixLen {
+			n, perr := strconv.ParseUint(string(line[:prefixLen]), 16, 32)
+			if perr == nil {
+				if int(n)+1 < next {
+					return ErrDatasetNotSorted
+				}
+				for ; next <= int(n); next++ {
+					offsets[next] = pos
+				}
+			}
+		}
+		pos += uint64(len(line))
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for ; next <= prefixCount; next++ {
+		offsets[next] = pos
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, indexHeaderSize+len(offsets)*8))
+	buf.WriteString(indexMagic)
+	_ = binary.Write(buf, binary.BigEndian, uint64(info.Size()))
+	_ = binary.Write(buf, binary.BigEndian, uint64(info.ModTime().UnixNano()))
+	_ = binary.Write(buf, binary.BigEndian, offsets)
+
+	tmp := indexPath(datasetPath) + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, indexPath(datasetPath))
+}
+
+// indexFresh reports whether the index exists and was built from a dataset
+// with the same size and mtime.
+func indexFresh(datasetPath string, info fs.FileInfo) (bool, error) {
+	f, err := os.Open(indexPath(datasetPath))
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	header := make([]byte, indexHeaderSize)
+	if _, err := io.ReadFull(f, header); err != nil {
+		return false, err
+	}
+
+	magicLen := len(indexMagic)
+	if string(header[:magicLen]) != indexMagic {
+		return false, nil
+	}
+	size := binary.BigEndian.Uint64(header[magicLen : magicLen+8])
+	mtime := binary.BigEndian.Uint64(header[magicLen+8:])
+
+	return size == uint64(info.Size()) && mtime == uint64(info.ModTime().UnixNano()), nil
+}
+
+func indexPath(datasetPath string) string {
+	return datasetPath + ".idx"
+}
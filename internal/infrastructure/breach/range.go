@@ -0,0 +1,49 @@
+package breach
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// prefixLen is the k-anonymity prefix used by the Pwned Passwords range API
+const prefixLen = 5
+
+// hashPassword returns the upper-case SHA-1 hex of the password split into the
+// 5-char range prefix and the 35-char suffix.
+func hashPassword(password string) (prefix, suffix string) {
+	sum := sha1.Sum([]byte(password))
+	h := strings.ToUpper(hex.EncodeToString(sum[:]))
+	return h[:prefixLen], h[prefixLen:]
+}
+
+// findInRange scans "HASH:COUNT" lines (range API / range file format) and
+// returns the count of the line whose hash equals want. Padding entries
+// (count 0) are reported as not found.
+func findInRange(r io.Reader, want string) (int, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		hash, count, ok := parseLine(scanner.Text())
+		if !ok || !strings.EqualFold(hash, want) {
+			continue
+		}
+		return count, nil
+	}
+	return 0, scanner.Err()
+}
+
+func parseLine(line string) (string, int, bool) {
+	hash, rawCount, ok := strings.Cut(strings.TrimSpace(line), ":")
+	if !ok {
+		return "", 0, false
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(rawCount))
+	if err != nil {
+		return "", 0, false
+	}
+	return hash, count, true
+}
@@ -0,0 +1,55 @@
+package breach
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+)
+
+const PwnedPasswordsAPIURL = "https://api.pwnedpasswords.com"
+
+// RangeAPIChecker queries the Pwned Passwords range API. Only the first five
+// characters of the SHA-1 hash leave the process; responses are padded so
+// the response size does not reveal the prefix either.
+type RangeAPIChecker struct {
+	client  *http.Client
+	baseURL string
+}
+
+func NewRangeAPIChecker(baseURL string, timeout time.Duration) ports.BreachedPasswordChecker {
+	if baseURL == "" {
+		baseURL = PwnedPasswordsAPIURL
+	}
+
+	return &RangeAPIChecker{
+		client:  &http.Client{Timeout: timeout},
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+func (c *RangeAPIChecker) Count(ctx context.Context, password string) (int, error) {
+	prefix, suffix := hashPassword(password)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/range/"+prefix, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Add-Padding", "true")
+	req.Header.Set("User-Agent", "golang-template-breach-check")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("pwned passwords range: unexpected status %d", resp.StatusCode)
+	}
+
+	return findInRange(resp.Body, suffix)
+}
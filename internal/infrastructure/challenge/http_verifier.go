@@ -0,0 +1,86 @@
+package challenge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+)
+
+const (
+	HCaptchaVerifyURL  = "https://api.hcaptcha.com/siteverify"
+	TurnstileVerifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+)
+
+// HTTPVerifier verifies CAPTCHA tokens against a siteverify-style endpoint
+// (hCaptcha, Cloudflare Turnstile and reCAPTCHA share the same contract).
+type HTTPVerifier struct {
+	client    *http.Client
+	verifyURL string
+	secret    string
+	siteKey   string
+}
+
+func NewHTTPVerifier(verifyURL, secret, siteKey string, timeout time.Duration) ports.ChallengeVerifier {
+	return &HTTPVerifier{
+		client:    &http.Client{Timeout: timeout},
+		verifyURL: verifyURL,
+		secret:    secret,
+		siteKey:   siteKey,
+	}
+}
+
+type siteVerifyResponse struct {
+	Success    bool     `json:"success"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+// Issue only exposes the site key; the widget creates the challenge client-side
+func (v *HTTPVerifier) Issue(ctx context.Context) (*valueobjects.Challenge, error) {
+	return &valueobjects.Challenge{
+		Provider: valueobjects.ChallengeProviderCaptcha,
+		SiteKey:  v.siteKey,
+	}, nil
+}
+
+func (v *HTTPVerifier) Verify(ctx context.Context, solution, remoteIP string) (bool, error) {
+	if solution == "" {
+		return false, nil
+	}
+
+	form := url.Values{}
+	form.Set("secret", v.secret)
+	form.Set("response", solution)
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("challenge verify: unexpected status %d", resp.StatusCode)
+	}
+
+	var result siteVerifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, err
+	}
+
+	return result.Success, nil
+}
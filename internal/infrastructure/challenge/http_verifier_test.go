@@ -0,0 +1,134 @@
+package challenge
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+)
+
+func TestHTTPVerifier_Verify(t *testing.T) {
+	var got http.Request
+	var form map[string]string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = *r
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm: %v", err)
+		}
+		form = map[string]string{
+			"secret":   r.PostForm.Get("secret"),
+			"response": r.PostForm.Get("response"),
+			"remoteip": r.PostForm.Get("remoteip"),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostForm.Get("response") == "good" {
+			_, _ = w.Write([]byte(`{"success":true}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"success":false,"error-codes":["invalid-input-response"]}`))
+	}))
+	defer srv.Close()
+
+	v := NewHTTPVerifier(srv.URL, "site-secret", "site-key", time.Second)
+
+	ok, err := v.Verify(context.Background(), "good", "203.0.113.7")
+	if err != nil || !ok {
+		t.Fatalf("Verify(good) = %v, %v; want true", ok, err)
+	}
+
+	if got.Method != http.MethodPost {
+		t.Errorf("method = %s, want POST", got.Method)
+	}
+	if ct := got.Header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	want := map[string]string{"secret": "site-secret", "response": "good", "remoteip": "203.0.113.7"}
+	for k, v := range want {
+		if form[k] != v {
+			t.Errorf("form %s = %q, want %q", k, form[k], v)
+		}
+	}
+
+	ok, err = v.Verify(context.Background(), "bad", "")
+	if err != nil || ok {
+		t.Fatalf("Verify(bad) = %v, %v; want false without error", ok, err)
+	}
+	if form["remoteip"] != "" {
+		t.Errorf("remoteip sent without a client IP: %q", form["remoteip"])
+	}
+}
+
+func TestHTTPVerifier_EmptySolution(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer srv.Close()
+
+	v := NewHTTPVerifier(srv.URL, "site-secret", "site-key", time.Second)
+
+	ok, err := v.Verify(context.Background(), "", "203.0.113.7")
+	if err != nil || ok {
+		t.Fatalf("Verify(\"\") = %v, %v; want false", ok, err)
+	}
+	if calls.Load() != 0 {
+		t.Fatal("empty solution was sent to the provider")
+	}
+}
+
+func TestHTTPVerifier_Errors(t *testing.T) {
+	cases := map[string]http.HandlerFunc{
+		"server error": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		},
+		"rate limited": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		},
+		"invalid json": func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`<html>`))
+		},
+		"timeout": func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+		},
+	}
+
+	for name, handler := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(handler)
+			defer srv.Close()
+
+			v := NewHTTPVerifier(srv.URL, "site-secret", "site-key", 50*time.Millisecond)
+
+			// a provider failure is an error, never a pass: the middleware then
+			// asks for a new challenge
+			ok, err := v.Verify(context.Background(), "token", "")
+			if err == nil {
+				t.Fatal("Verify returned no error")
+			}
+			if ok {
+				t.Fatal("Verify passed on a provider error")
+			}
+		})
+	}
+}
+
+func TestHTTPVerifier_Issue(t *testing.T) {
+	v := NewHTTPVerifier("http://unused", "site-secret", "site-key", time.Second)
+
+	c, err := v.Issue(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Provider != valueobjects.ChallengeProviderCaptcha || c.SiteKey != "site-key" {
+		t.Fatalf("Issue = %+v", c)
+	}
+	if c.Token != "" {
+		t.Fatal("secret material in a CAPTCHA challenge")
+	}
+}
@@ -0,0 +1,145 @@
+package challenge
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+)
+
+// PoWVerifier is a self-hosted hashcash-style challenge. Tokens are stateless
+// (HMAC-signed difficulty + expiry); only solved tokens are remembered to
+// prevent replay until they expire.
+type PoWVerifier struct {
+	secret     []byte
+	difficulty int
+	ttl        time.Duration
+
+	mu   sync.Mutex
+	used map[string]time.Time // token → expiry
+}
+
+func NewPoWVerifier(secret string, difficulty int, ttl time.Duration) ports.ChallengeVerifier {
+	return &PoWVerifier{
+		secret:     []byte(secret),
+		difficulty: difficulty,
+		ttl:        ttl,
+		used:       make(map[string]time.Time),
+	}
+}
+
+func (v *PoWVerifier) Issue(ctx context.Context) (*valueobjects.Challenge, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(v.ttl)
+	payload := fmt.Sprintf("%d:%d:%s", v.difficulty, expiresAt.Unix(), hex.EncodeToString(nonce))
+
+	return &valueobjects.Challenge{
+		Provider:   valueobjects.ChallengeProviderPoW,
+		Token:      encode([]byte(payload)) + "." + encode(v.sign(payload)),
+		Difficulty: v.difficulty,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+func (v *PoWVerifier) Verify(ctx context.Context, solution, remoteIP string) (bool, error) {
+	i := strings.LastIndexByte(solution, ':')
+	if i <= 0 {
+		return false, nil
+	}
+	token := solution[:i]
+
+	payloadB64, sigB64, ok := strings.Cut(token, ".")
+	if !ok {
+		return false, nil
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(payloadB64)
+	if err != nil {
+		return false, nil
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(sigB64)
+	if err != nil || !hmac.Equal(sig, v.sign(string(payload))) {
+		return false, nil
+	}
+
+	parts := strings.SplitN(string(payload), ":", 3)
+	if len(parts) != 3 {
+		return false, nil
+	}
+	difficulty, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return false, nil
+	}
+	expUnix, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return false, nil
+	}
+
+	now := time.Now()
+	expiresAt := time.Unix(expUnix, 0)
+	if now.After(expiresAt) {
+		return false, nil
+	}
+
+	digest := sha256.Sum256([]byte(solution))
+	if leadingZeroBits(digest[:]) < difficulty {
+		return false, nil
+	}
+
+	return v.markUsed(token, expiresAt, now), nil
+}
+
+// markUsed returns false if the token was already redeemed
+func (v *PoWVerifier) markUsed(token string, expiresAt, now time.Time) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for t, exp := range v.used {
+		if now.After(exp) {
+			delete(v.used, t)
+		}
+	}
+
+	if _, seen := v.used[token]; seen {
+		return false
+	}
+	v.used[token] = expiresAt
+	return true
+}
+
+func (v *PoWVerifier) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func leadingZeroBits(b []byte) int {
+	n := 0
+	for _, x := range b {
+		if x == 0 {
+			n += 8
+			continue
+		}
+		return n + bits.LeadingZeros8(x)
+	}
+	return n
+}
@@ -0,0 +1,148 @@
+package challenge
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+)
+
+// solve does what the client widget does: find a counter whose
+// sha256("<token>:<counter>") has enough leading zero bits
+func solve(t *testing.T, token string, difficulty int) string {
+	t.Helper()
+
+	for i := 0; i < 1<<24; i++ {
+		solution := token + ":" + strconv.Itoa(i)
+		digest := sha256.Sum256([]byte(solution))
+		if leadingZeroBits(digest[:]) >= difficulty {
+			return solution
+		}
+	}
+	t.Fatalf("no solution found for difficulty %d", difficulty)
+	return ""
+}
+
+func issue(t *testing.T, v *PoWVerifier) *valueobjects.Challenge {
+	t.Helper()
+
+	c, err := v.Issue(context.Background())
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	return c
+}
+
+func newTestPoWVerifier(difficulty int, ttl time.Duration) *PoWVerifier {
+	return NewPoWVerifier("test-secret", difficulty, ttl).(*PoWVerifier)
+}
+
+func TestPoWVerifier_Issue(t *testing.T) {
+	v := newTestPoWVerifier(8, time.Minute)
+	c := issue(t, v)
+
+	if c.Provider != valueobjects.ChallengeProviderPoW {
+		t.Errorf("Provider = %q, want %q", c.Provider, valueobjects.ChallengeProviderPoW)
+	}
+	if c.Difficulty != 8 {
+		t.Errorf("Difficulty = %d, want 8", c.Difficulty)
+	}
+	if d := time.Until(c.ExpiresAt); d <= 0 || d > time.Minute {
+		t.Errorf("ExpiresAt in %s, want within the ttl", d)
+	}
+	if strings.Count(c.Token, ".") != 1 {
+		t.Errorf("Token = %q, want <payload>.<signature>", c.Token)
+	}
+	if other := issue(t, v); other.Token == c.Token {
+		t.Error("two challenges share a token")
+	}
+}
+
+func TestPoWVerifier_Verify(t *testing.T) {
+	ctx := context.Background()
+	v := newTestPoWVerifier(8, time.Minute)
+	c := issue(t, v)
+	solution := solve(t, c.Token, c.Difficulty)
+
+	ok, err := v.Verify(ctx, solution, "")
+	if err != nil || !ok {
+		t.Fatalf("Verify(solution) = %v, %v; want true", ok, err)
+	}
+
+	// a solved token cannot be redeemed twice
+	if ok, _ := v.Verify(ctx, solution, ""); ok {
+		t.Fatal("replayed solution accepted")
+	}
+}
+
+func TestPoWVerifier_Rejects(t *testing.T) {
+	ctx := context.Background()
+	v := newTestPoWVerifier(8, time.Minute)
+	other := newTestPoWVerifier(8, time.Minute)
+	other.secret = []byte("other-secret")
+
+	c := issue(t, v)
+	payloadB64, sigB64, _ := strings.Cut(c.Token, ".")
+	payload := mustDecode(t, payloadB64)
+
+	// first counter that does NOT meet the difficulty
+	unsolved := c.Token + ":0"
+	for i := 1; ; i++ {
+		digest := sha256.Sum256([]byte(unsolved))
+		if leadingZeroBits(digest[:]) < c.Difficulty {
+			break
+		}
+		unsolved = c.Token + ":" + strconv.Itoa(i)
+	}
+
+	// difficulty lowered to 0 but the original signature kept
+	lowered := "0" + payload[strings.IndexByte(payload, ':'):]
+
+	cases := map[string]string{
+		"empty":            "",
+		"no counter":       c.Token,
+		"not enough work":  unsolved,
+		"foreign secret":   solve(t, payloadB64+"."+encode(other.sign(payload)), 8),
+		"tampered payload": solve(t, encode([]byte(lowered))+"."+sigB64, 0),
+		"not base64":       "!!!.???:1",
+		"no signature":     payloadB64 + ":1",
+		"bad payload":      solve(t, encode([]byte("x"))+"."+encode(v.sign("x")), 8),
+	}
+
+	for name, solution := range cases {
+		t.Run(name, func(t *testing.T) {
+			ok, err := v.Verify(ctx, solution, "")
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if ok {
+				t.Fatalf("Verify(%q) = true, want false", solution)
+			}
+		})
+	}
+}
+
+func TestPoWVerifier_Expired(t *testing.T) {
+	v := newTestPoWVerifier(0, -time.Second)
+	c := issue(t, v)
+
+	ok, err := v.Verify(context.Background(), solve(t, c.Token, 0), "")
+	if err != nil || ok {
+		t.Fatalf("Verify(expired) = %v, %v; want false", ok, err)
+	}
+}
+
+func mustDecode(t *testing.T, s string) string {
+	t.Helper()
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatalf("decode %q: %v", s, err)
+	}
+	return string(b)
+}
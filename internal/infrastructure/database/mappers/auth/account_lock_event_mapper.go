@@ -0,0 +1,38 @@
+package auth
+
+import (
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
+)
+
+func ToDomainAccountLockEvent(m *model.AccountLockEvent) *domain.AccountLockEvent {
+	if m == nil {
+		return nil
+	}
+
+	return &domain.AccountLockEvent{
+		ID:          m.ID,
+		UserID:      m.UserID,
+		ActorID:     m.ActorID,
+		Action:      domain.AccountLockAction(m.Action),
+		Reason:      m.Reason,
+		LockedUntil: m.LockedUntil,
+		CreatedAt:   m.CreatedAt,
+	}
+}
+
+func ToModelAccountLockEvent(d *domain.AccountLockEvent) *model.AccountLockEvent {
+	if d == nil {
+		return nil
+	}
+
+	return &model.AccountLockEvent{
+		ID:          d.ID,
+		UserID:      d.UserID,
+		ActorID:     d.ActorID,
+		Action:      string(d.Action),
+		Reason:      d.Reason,
+		LockedUntil: d.LockedUntil,
+		CreatedAt:   d.CreatedAt,
+	}
+}
@@ -14,8 +14,10 @@ func ToDomainEmailOTP(m *model.EmailOTP) *domain.EmailOTP {
 	return &domain.EmailOTP{
 		ID:        m.ID,
 		Email:     m.Email,
+		Purpose:   m.Purpose,
 		OTPHash:   m.OTPHash,
 		ExpiredAt: m.ExpiredAt,
+		Attempts:  m.Attempts,
 		IPAddress: m.IPAddress,
 		UserAgent: m.UserAgent,
 		CreatedAt: m.CreatedAt,
@@ -31,8 +33,10 @@ func ToModelEmailOTP(d *domain.EmailOTP) *model.EmailOTP {
 	return &model.EmailOTP{
 		ID:        d.ID,
 		Email:     d.Email,
+		Purpose:   d.Purpose,
 		OTPHash:   d.OTPHash,
 		ExpiredAt: d.ExpiredAt,
+		Attempts:  d.Attempts,
 		IPAddress: d.IPAddress,
 		UserAgent: d.UserAgent,
 		// CreatedAt biarkan GORM
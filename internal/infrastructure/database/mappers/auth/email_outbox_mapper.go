@@ -0,0 +1,50 @@
+package auth
+
+import (
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
+)
+
+func ToDomainEmailOutboxMessage(m *model.EmailOutboxMessage) *domain.EmailOutboxMessage {
+	if m == nil {
+		return nil
+	}
+
+	return &domain.EmailOutboxMessage{
+		ID:             m.ID,
+		IdempotencyKey: m.IdempotencyKey,
+		Kind:           m.Kind,
+		Recipient:      m.Recipient,
+		Locale:         m.Locale,
+		Payload:        m.Payload,
+		Status:         m.Status,
+		Attempts:       m.Attempts,
+		NextAttemptAt:  m.NextAttemptAt,
+		LastError:      m.LastError,
+		SentAt:         m.SentAt,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+func ToModelEmailOutboxMessage(d *domain.EmailOutboxMessage) *model.EmailOutboxMessage {
+	if d == nil {
+		return nil
+	}
+
+	return &model.EmailOutboxMessage{
+		ID:             d.ID,
+		IdempotencyKey: d.IdempotencyKey,
+		Kind:           d.Kind,
+		Recipient:      d.Recipient,
+		Locale:         d.Locale,
+		Payload:        d.Payload,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastError:      d.LastError,
+		SentAt:         d.SentAt,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
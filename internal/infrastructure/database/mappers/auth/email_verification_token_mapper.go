@@ -0,0 +1,40 @@
+package auth
+
+import (
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
+)
+
+func ToDomainEmailVerificationToken(m *model.EmailVerificationToken) *domain.EmailVerificationToken {
+	if m == nil {
+		return nil
+	}
+
+	return &domain.EmailVerificationToken{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		Purpose:   m.Purpose,
+		Email:     m.Email,
+		TokenHash: m.TokenHash,
+		ExpiresAt: m.ExpiresAt,
+		CreatedAt: m.CreatedAt,
+		Attempts:  m.Attempts,
+	}
+}
+
+func ToModelEmailVerificationToken(d *domain.EmailVerificationToken) *model.EmailVerificationToken {
+	if d == nil {
+		return nil
+	}
+
+	return &model.EmailVerificationToken{
+		ID:        d.ID,
+		UserID:    d.UserID,
+		Purpose:   d.Purpose,
+		Email:     d.Email,
+		TokenHash: d.TokenHash,
+		ExpiresAt: d.ExpiresAt,
+		CreatedAt: d.CreatedAt,
+		Attempts:  d.Attempts,
+	}
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
+)
+
+func ToDomainImpersonationAuditEvent(m *model.ImpersonationAuditEvent) *domain.ImpersonationAuditEvent {
+	if m == nil {
+		return nil
+	}
+
+	return &domain.ImpersonationAuditEvent{
+		ID:         m.ID,
+		ActorID:    m.ActorID,
+		SubjectID:  m.SubjectID,
+		Action:     domain.ImpersonationAction(m.Action),
+		Reason:     m.Reason,
+		TokenID:    m.TokenID,
+		Method:     m.Method,
+		Path:       m.Path,
+		StatusCode: m.StatusCode,
+		IPAddress:  m.IPAddress,
+		UserAgent:  m.UserAgent,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+func ToModelImpersonationAuditEvent(d *domain.ImpersonationAuditEvent) *model.ImpersonationAuditEvent {
+	if d == nil {
+		return nil
+	}
+
+	return &model.ImpersonationAuditEvent{
+		ID:         d.ID,
+		ActorID:    d.ActorID,
+		SubjectID:  d.SubjectID,
+		Action:     string(d.Action),
+		Reason:     d.Reason,
+		TokenID:    d.TokenID,
+		Method:     d.Method,
+		Path:       d.Path,
+		StatusCode: d.StatusCode,
+		IPAddress:  d.IPAddress,
+		UserAgent:  d.UserAgent,
+		CreatedAt:  d.CreatedAt,
+	}
+}
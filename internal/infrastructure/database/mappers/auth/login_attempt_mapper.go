@@ -40,6 +40,6 @@ func ToModelLoginAttempt(d *domain.LoginAttempt) *model.LoginAttempt {
 		UserAgent:     d.UserAgent,
 		Success:       d.Success,
 		FailureReason: d.Reason,
-		// CreatedAt → biarkan GORM
+		CreatedAt:     d.CreatedAt, // zero → diisi GORM (autoCreateTime)
 	}
 }
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"strings"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
+)
+
+func ToDomainLoginRiskEvent(m *model.LoginRiskEvent) *domain.LoginRiskEvent {
+	if m == nil {
+		return nil
+	}
+
+	var reasons []string
+	if m.Reasons != "" {
+		reasons = strings.Split(m.Reasons, ",")
+	}
+
+	return &domain.LoginRiskEvent{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		IPAddress: m.IPAddress,
+		UserAgent: m.UserAgent,
+		Country:   m.Country,
+		City:      m.City,
+		Latitude:  m.Latitude,
+		Longitude: m.Longitude,
+		Score:     m.Score,
+		Decision:  domain.RiskDecision(m.Decision),
+		Reasons:   reasons,
+		Completed: m.Completed,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+func ToModelLoginRiskEvent(d *domain.LoginRiskEvent) *model.LoginRiskEvent {
+	if d == nil {
+		return nil
+	}
+
+	return &model.LoginRiskEvent{
+		ID:        d.ID,
+		UserID:    d.UserID,
+		IPAddress: d.IPAddress,
+		UserAgent: d.UserAgent,
+		Country:   d.Country,
+		City:      d.City,
+		Latitude:  d.Latitude,
+		Longitude: d.Longitude,
+		Score:     d.Score,
+		Decision:  string(d.Decision),
+		Reasons:   strings.Join(d.Reasons, ","),
+		Completed: d.Completed,
+		CreatedAt: d.CreatedAt,
+	}
+}
@@ -0,0 +1,34 @@
+package auth
+
+import (
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
+)
+
+// ================= TO DOMAIN =================
+func ToDomainPasswordHistory(m *model.PasswordHistory) *domain.PasswordHistory {
+	if m == nil {
+		return nil
+	}
+
+	return &domain.PasswordHistory{
+		ID:           m.ID,
+		UserID:       m.UserID,
+		PasswordHash: m.PasswordHash,
+		CreatedAt:    m.CreatedAt,
+	}
+}
+
+// ================= TO MODEL =================
+func ToModelPasswordHistory(d *domain.PasswordHistory) *model.PasswordHistory {
+	if d == nil {
+		return nil
+	}
+
+	return &model.PasswordHistory{
+		ID:           d.ID,
+		UserID:       d.UserID,
+		PasswordHash: d.PasswordHash,
+		// CreatedAt biarkan GORM
+	}
+}
@@ -1,36 +1,44 @@
 package auth
 
-// import (
-// 	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
-// 	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
-// )
+import (
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
+)
 
-// func ToDomainPasswordResetToken(m *model.PasswordResetToken) *domain.PasswordResetToken {
-// 	if m == nil {
-// 		return nil
-// 	}
+// ================= TO DOMAIN =================
+func ToDomainPasswordResetToken(m *model.PasswordResetToken) *domain.PasswordResetToken {
+	if m == nil {
+		return nil
+	}
 
-// 	return &domain.PasswordResetToken{
-// 		ID:        m.ID,
-// 		UserID:    m.UserID,
-// 		TokenHash: m.TokenHash,
-// 		ExpiresAt: m.ExpiresAt,
-// 		Used:      m.Used,
-// 		CreatedAt: m.CreatedAt,
-// 	}
-// }
+	return &domain.PasswordResetToken{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		TokenHash: m.TokenHash,
+		ExpiresAt: m.ExpiresAt,
+		Used:      m.Used,
+		UsedAt:    m.UsedAt,
+		Attempts:  m.Attempts,
+		IPAddress: m.IPAddress,
+		CreatedAt: m.CreatedAt,
+	}
+}
 
-// func ToModelPasswordResetToken(d *domain.PasswordResetToken) *model.PasswordResetToken {
-// 	if d == nil {
-// 		return nil
-// 	}
+// ================= TO MODEL =================
+func ToModelPasswordResetToken(d *domain.PasswordResetToken) *model.PasswordResetToken {
+	if d == nil {
+		return nil
+	}
 
-// 	return &model.PasswordResetToken{
-// 		ID:        d.ID,
-// 		UserID:    d.UserID,
-// 		TokenHash: d.TokenHash,
-// 		ExpiresAt: d.ExpiresAt,
-// 		Used:      d.Used,
-// 		CreatedAt: d.CreatedAt,
-// 	}
-// }
+	return &model.PasswordResetToken{
+		ID:        d.ID,
+		UserID:    d.UserID,
+		TokenHash: d.TokenHash,
+		ExpiresAt: d.ExpiresAt,
+		Used:      d.Used,
+		UsedAt:    d.UsedAt,
+		Attempts:  d.Attempts,
+		IPAddress: d.IPAddress,
+		// CreatedAt biarkan GORM
+	}
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
+)
+
+func ToDomainQuotaPlan(m *model.QuotaPlan) *domain.QuotaPlan {
+	if m == nil {
+		return nil
+	}
+
+	return &domain.QuotaPlan{
+		ID:           m.ID,
+		RoleID:       m.RoleID,
+		Name:         m.Name,
+		DailyLimit:   m.DailyLimit,
+		MonthlyLimit: m.MonthlyLimit,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
+
+func ToModelQuotaPlan(d *domain.QuotaPlan) *model.QuotaPlan {
+	if d == nil {
+		return nil
+	}
+
+	return &model.QuotaPlan{
+		ID:           d.ID,
+		RoleID:       d.RoleID,
+		Name:         d.Name,
+		DailyLimit:   d.DailyLimit,
+		MonthlyLimit: d.MonthlyLimit,
+		CreatedAt:    d.CreatedAt,
+		UpdatedAt:    d.UpdatedAt,
+	}
+}
+
+func ToDomainQuotaOverride(m *model.QuotaOverride) *domain.QuotaOverride {
+	if m == nil {
+		return nil
+	}
+
+	return &domain.QuotaOverride{
+		ID:           m.ID,
+		SubjectType:  m.SubjectType,
+		SubjectID:    m.SubjectID,
+		DailyLimit:   m.DailyLimit,
+		MonthlyLimit: m.MonthlyLimit,
+		Reason:       m.Reason,
+		CreatedBy:    m.CreatedBy,
+		ExpiresAt:    m.ExpiresAt,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
+
+func ToModelQuotaOverride(d *domain.QuotaOverride) *model.QuotaOverride {
+	if d == nil {
+		return nil
+	}
+
+	return &model.QuotaOverride{
+		ID:           d.ID,
+		SubjectType:  d.SubjectType,
+		SubjectID:    d.SubjectID,
+		DailyLimit:   d.DailyLimit,
+		MonthlyLimit: d.MonthlyLimit,
+		Reason:       d.Reason,
+		CreatedBy:    d.CreatedBy,
+		ExpiresAt:    d.ExpiresAt,
+		CreatedAt:    d.CreatedAt,
+		UpdatedAt:    d.UpdatedAt,
+	}
+}
+
+func ToModelQuotaUsage(d *domain.QuotaUsage) *model.QuotaUsage {
+	if d == nil {
+		return nil
+	}
+
+	return &model.QuotaUsage{
+		ID:          d.ID,
+		SubjectType: d.SubjectType,
+		SubjectID:   d.SubjectID,
+		Period:      d.Period,
+		PeriodStart: d.PeriodStart,
+		Count:       d.Count,
+		UpdatedAt:   d.UpdatedAt,
+	}
+}
@@ -19,15 +19,27 @@ func ToDomainUser(m *model.User) *domain.User {
 	}
 
 	return &domain.User{
-		ID:            m.ID,
-		Email:         m.Email,
-		EmailVerified: m.EmailVerified,
-		PasswordHash:  m.PasswordHash,
-		Name:          m.Name,
-		RoleID:        m.RoleID,
-		CreatedAt:     m.CreatedAt,
-		UpdatedAt:     m.UpdatedAt,
-		DeletedAt:     deletedAt,
+		ID:              m.ID,
+		Email:           m.Email,
+		EmailVerified:   m.EmailVerified,
+		PasswordHash:    m.PasswordHash,
+		Name:            m.Name,
+		Phone:           m.Phone,
+		PhoneVerified:   m.PhoneVerified,
+		RoleID:          m.RoleID,
+		Locked:          m.Locked,
+		LockedUntil:     m.LockedUntil,
+		LockReason:      m.LockReason,
+		UnlockTokenHash: m.UnlockTokenHash,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+		DeletedAt:       deletedAt,
+
+		PreferredChannel:       m.PreferredChannel,
+		Locale:                 m.Locale,
+		MustChangePassword:     m.MustChangePassword,
+		PasswordChangedAt:      m.PasswordChangedAt,
+		PasswordExpiryWarnedAt: m.PasswordExpiryWarnedAt,
 	}
 }
 
@@ -37,14 +49,26 @@ func ToModelUser(d *domain.User) *model.User {
 	}
 
 	m := &model.User{
-		ID:            d.ID,
-		Email:         d.Email,
-		EmailVerified: d.EmailVerified,
-		PasswordHash:  d.PasswordHash,
-		Name:          d.Name,
-		RoleID:        d.RoleID,
-		CreatedAt:     d.CreatedAt,
-		UpdatedAt:     d.UpdatedAt,
+		ID:              d.ID,
+		Email:           d.Email,
+		EmailVerified:   d.EmailVerified,
+		PasswordHash:    d.PasswordHash,
+		Name:            d.Name,
+		Phone:           d.Phone,
+		PhoneVerified:   d.PhoneVerified,
+		RoleID:          d.RoleID,
+		Locked:          d.Locked,
+		LockedUntil:     d.LockedUntil,
+		LockReason:      d.LockReason,
+		UnlockTokenHash: d.UnlockTokenHash,
+		CreatedAt:       d.CreatedAt,
+		UpdatedAt:       d.UpdatedAt,
+
+		PreferredChannel:       d.PreferredChannel,
+		Locale:                 d.Locale,
+		MustChangePassword:     d.MustChangePassword,
+		PasswordChangedAt:      d.PasswordChangedAt,
+		PasswordExpiryWarnedAt: d.PasswordExpiryWarnedAt,
 	}
 
 	if d.DeletedAt != nil {
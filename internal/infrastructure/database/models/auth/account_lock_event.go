@@ -0,0 +1,15 @@
+package auth
+
+import "time"
+
+type AccountLockEvent struct {
+	ID      uint64  `gorm:"primaryKey;autoIncrement;type:bigserial"`
+	UserID  uint64  `gorm:"not null;index:idx_ale_user_created,priority:1"`
+	ActorID *uint64 `gorm:"index"`
+
+	Action      string `gorm:"size:16;not null"`
+	Reason      string `gorm:"size:255"`
+	LockedUntil *time.Time
+
+	CreatedAt time.Time `gorm:"autoCreateTime;index:idx_ale_user_created,priority:2"`
+}
@@ -5,15 +5,17 @@ import "time"
 type EmailOTP struct {
 	ID uint64 `gorm:"primaryKey"`
 
-	Email string `gorm:"size:255;index;not null"`
+	Email   string `gorm:"size:255;not null;index:idx_email_otp_email_purpose"`
+	Purpose string `gorm:"size:32;not null;default:signup;index:idx_email_otp_email_purpose"`
 
 	OTPHash string `gorm:"size:255;not null"`
 
 	ExpiredAt time.Time `gorm:"index;not null"`
 
+	Attempts int `gorm:"not null;default:0"`
+
 	IPAddress string `gorm:"size:45"`
 	UserAgent string `gorm:"size:512"`
-	Purpose   string `gorm:"size:512"`
 
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 }
@@ -0,0 +1,26 @@
+package auth
+
+import "time"
+
+type EmailOutboxMessage struct {
+	ID             uint64 `gorm:"primaryKey;autoIncrement;type:bigserial"`
+	IdempotencyKey string `gorm:"size:255;not null;uniqueIndex"`
+
+	Kind      string `gorm:"size:64;not null"`
+	Recipient string `gorm:"size:255;not null"`
+	Locale    string `gorm:"size:16"`
+	Payload   string `gorm:"type:jsonb;not null"`
+
+	Status        string     `gorm:"size:16;not null;default:pending;index:idx_email_outbox_due,priority:1"`
+	Attempts      int        `gorm:"not null;default:0"`
+	NextAttemptAt time.Time  `gorm:"not null;index:idx_email_outbox_due,priority:2"`
+	LastError     string     `gorm:"type:text"`
+	SentAt        *time.Time `gorm:""`
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (EmailOutboxMessage) TableName() string {
+	return "email_outbox"
+}
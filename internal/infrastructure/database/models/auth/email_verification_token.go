@@ -0,0 +1,17 @@
+package auth
+
+import "time"
+
+type EmailVerificationToken struct {
+	ID     uint64 `gorm:"primaryKey;autoIncrement;type:bigserial"`
+	UserID uint64 `gorm:"not null;index:idx_evt_user_purpose,priority:1"`
+
+	Purpose string `gorm:"size:32;not null;default:signup;index:idx_evt_user_purpose,priority:2"`
+	Email   string `gorm:"size:255"`
+
+	TokenHash string    `gorm:"size:255;not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+
+	Attempts int `gorm:"not null;default:0"`
+}
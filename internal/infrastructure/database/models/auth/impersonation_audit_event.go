@@ -0,0 +1,23 @@
+package auth
+
+import "time"
+
+type ImpersonationAuditEvent struct {
+	ID        uint64 `gorm:"primaryKey;autoIncrement;type:bigserial"`
+	ActorID   uint64 `gorm:"not null;index"`
+	SubjectID uint64 `gorm:"not null;index:idx_iae_subject_created,priority:1"`
+
+	Action string `gorm:"size:16;not null"`
+	Reason string `gorm:"size:255"`
+
+	TokenID string `gorm:"size:64;index"`
+
+	Method     string `gorm:"size:10"`
+	Path       string `gorm:"type:text"`
+	StatusCode int
+
+	IPAddress string `gorm:"size:60"`
+	UserAgent string `gorm:"type:text"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime;index:idx_iae_subject_created,priority:2"`
+}
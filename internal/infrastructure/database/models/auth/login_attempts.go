@@ -28,6 +28,6 @@ type LoginAttempt struct {
 
 	Success bool `gorm:"not null"`
 
-	FailureReason string    `gorm:"size:64"`
-	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	FailureReason string    `gorm:"size:64"` // outcome, termasuk "success"
+	CreatedAt     time.Time `gorm:"autoCreateTime;index"`
 }
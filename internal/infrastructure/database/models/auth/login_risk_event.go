@@ -0,0 +1,24 @@
+package auth
+
+import "time"
+
+type LoginRiskEvent struct {
+	ID     uint64 `gorm:"primaryKey;autoIncrement;type:bigserial"`
+	UserID uint64 `gorm:"not null;index:idx_lre_user_created,priority:1"`
+
+	IPAddress string `gorm:"size:60"`
+	UserAgent string `gorm:"type:text"`
+
+	Country   string `gorm:"size:2"`
+	City      string `gorm:"size:255"`
+	Latitude  *float64
+	Longitude *float64
+
+	Score    int    `gorm:"not null"`
+	Decision string `gorm:"size:16;index;not null"`
+	Reasons  string `gorm:"type:text"`
+
+	Completed bool `gorm:"not null;default:false"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime;index:idx_lre_user_created,priority:2"`
+}
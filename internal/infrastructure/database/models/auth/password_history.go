@@ -0,0 +1,11 @@
+package auth
+
+import "time"
+
+type PasswordHistory struct {
+	ID     uint64 `gorm:"primaryKey;autoIncrement;type:bigserial"`
+	UserID uint64 `gorm:"not null;index:idx_password_histories_user_created,priority:1"`
+
+	PasswordHash string    `gorm:"type:text;not null"`
+	CreatedAt    time.Time `gorm:"autoCreateTime;index:idx_password_histories_user_created,priority:2"`
+}
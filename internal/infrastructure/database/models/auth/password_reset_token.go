@@ -0,0 +1,18 @@
+package auth
+
+import "time"
+
+type PasswordResetToken struct {
+	ID     uint64 `gorm:"primaryKey;autoIncrement;type:bigserial"`
+	UserID uint64 `gorm:"not null;index"`
+
+	TokenHash string    `gorm:"size:255;not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	Used      bool      `gorm:"not null;default:false"`
+	UsedAt    *time.Time
+
+	Attempts int `gorm:"not null;default:0"`
+
+	IPAddress string    `gorm:"size:45"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
@@ -0,0 +1,46 @@
+package auth
+
+import "time"
+
+type QuotaPlan struct {
+	ID     uint64 `gorm:"primaryKey;autoIncrement;type:bigserial"`
+	RoleID uint64 `gorm:"not null;uniqueIndex"`
+	Name   string `gorm:"size:64;not null"`
+
+	DailyLimit   int64 `gorm:"not null;default:0"`
+	MonthlyLimit int64 `gorm:"not null;default:0"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+type QuotaOverride struct {
+	ID          uint64 `gorm:"primaryKey;autoIncrement;type:bigserial"`
+	SubjectType string `gorm:"size:32;not null;uniqueIndex:idx_quota_override_subject,priority:1"`
+	SubjectID   string `gorm:"size:64;not null;uniqueIndex:idx_quota_override_subject,priority:2"`
+
+	DailyLimit   *int64
+	MonthlyLimit *int64
+
+	Reason    string `gorm:"size:255"`
+	CreatedBy uint64 `gorm:"not null"`
+	ExpiresAt *time.Time
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+type QuotaUsage struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement;type:bigserial"`
+	SubjectType string    `gorm:"size:32;not null;uniqueIndex:idx_quota_usage_subject_period,priority:1"`
+	SubjectID   string    `gorm:"size:64;not null;uniqueIndex:idx_quota_usage_subject_period,priority:2"`
+	Period      string    `gorm:"size:16;not null;uniqueIndex:idx_quota_usage_subject_period,priority:3"`
+	PeriodStart time.Time `gorm:"not null;uniqueIndex:idx_quota_usage_subject_period,priority:4"`
+	Count       int64     `gorm:"not null;default:0"`
+
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (QuotaUsage) TableName() string {
+	return "quota_usage"
+}
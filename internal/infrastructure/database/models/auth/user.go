@@ -14,10 +14,23 @@ type User struct {
 	PasswordHash  string  `gorm:"type:text;not null"`
 	Name          *string `gorm:"size:255"`
 
+	MustChangePassword     bool      `gorm:"not null;default:false"`
+	PasswordChangedAt      time.Time `gorm:"not null;default:now();index"`
+	PasswordExpiryWarnedAt *time.Time
+
+	Phone            *string `gorm:"size:20;index"`
+	PhoneVerified    bool    `gorm:"default:false"`
+	PreferredChannel string  `gorm:"size:16;not null;default:email"`
+	Locale           string  `gorm:"size:16"`
+
 	RoleID uint64 `gorm:"not null;index"`
 	Role   Role   `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
 	Locked bool   `gorm:"default:false"`
 
+	LockedUntil     *time.Time
+	LockReason      string  `gorm:"size:255"`
+	UnlockTokenHash *string `gorm:"size:255;index"`
+
 	CreatedAt time.Time
 	UpdatedAt *time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"context"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	mapper "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/mappers/auth"
+	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/auth"
+	"gorm.io/gorm"
+)
+
+type accountLockEventRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountLockEventRepository(db *gorm.DB) ports.AccountLockEventRepository {
+	return &accountLockEventRepository{db: db}
+}
+
+func (r *accountLockEventRepository) Create(
+	ctx context.Context,
+	event *domain.AccountLockEvent,
+) error {
+
+	m := mapper.ToModelAccountLockEvent(event)
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+
+	event.ID = m.ID
+	event.CreatedAt = m.CreatedAt
+	return nil
+}
+
+func (r *accountLockEventRepository) GetRecentByUser(
+	ctx context.Context,
+	userID uint64,
+	limit int,
+) ([]*domain.AccountLockEvent, error) {
+
+	var models []model.AccountLockEvent
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*domain.AccountLockEvent, 0, len(models))
+	for i := range models {
+		events = append(events, mapper.ToDomainAccountLockEvent(&models[i]))
+	}
+
+	return events, nil
+}
@@ -2,12 +2,14 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
 	mapper "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/mappers/auth"
 	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
 	ports "github.com/dhanarrizky/Golang-template/internal/ports/email"
+	dbctx "github.com/dhanarrizky/Golang-template/pkg/database"
 	"gorm.io/gorm"
 )
 
@@ -26,26 +28,48 @@ func (r *emailOTPRepository) Save(
 
 	m := mapper.ToModelEmailOTP(otp)
 
-	return r.db.WithContext(ctx).Create(m).Error
+	// ikut transaksi jika ada (OTP + outbox ditulis bersamaan)
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// OTP lama untuk tujuan yang sama tidak berlaku lagi
+		if err := tx.
+			Where("email = ? AND purpose = ?", otp.Email, otp.Purpose).
+			Delete(&model.EmailOTP{}).
+			Error; err != nil {
+			return err
+		}
+
+		return tx.Create(m).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	otp.ID = m.ID
+	otp.CreatedAt = m.CreatedAt
+	return nil
 }
 
 func (r *emailOTPRepository) FindActiveByEmail(
 	ctx context.Context,
-	email string,
+	email, purpose string,
 ) (*domain.EmailOTP, error) {
 
 	var m model.EmailOTP
 
-	err := r.db.WithContext(ctx).
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
 		Where(
-			"email = ? AND expired_at > ?",
+			"email = ? AND purpose = ? AND expired_at > ?",
 			email,
+			purpose,
 			time.Now(),
 		).
 		Order("created_at DESC").
 		First(&m).
 		Error
 
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -53,13 +77,42 @@ func (r *emailOTPRepository) FindActiveByEmail(
 	return mapper.ToDomainEmailOTP(&m), nil
 }
 
+func (r *emailOTPRepository) IncrementAttempts(
+	ctx context.Context,
+	email, purpose string,
+) (int, error) {
+
+	var attempts []int
+
+	// UPDATE ... RETURNING: increment atomik walau beberapa request verifikasi paralel
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Raw(
+			`UPDATE email_otps SET attempts = attempts + 1
+			 WHERE email = ? AND purpose = ? AND expired_at > ?
+			 RETURNING attempts`,
+			email,
+			purpose,
+			time.Now(),
+		).
+		Scan(&attempts).
+		Error
+	if err != nil {
+		return 0, err
+	}
+	if len(attempts) == 0 {
+		return 0, nil
+	}
+
+	return attempts[0], nil
+}
+
 func (r *emailOTPRepository) DeleteByEmail(
 	ctx context.Context,
-	email string,
+	email, purpose string,
 ) error {
 
-	return r.db.WithContext(ctx).
-		Where("email = ?", email).
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Where("email = ? AND purpose = ?", email, purpose).
 		Delete(&model.EmailOTP{}).
 		Error
 }
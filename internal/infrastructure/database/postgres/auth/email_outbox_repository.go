@@ -0,0 +1,224 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	mapper "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/mappers/auth"
+	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/email"
+	dbctx "github.com/dhanarrizky/Golang-template/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type emailOutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailOutboxRepository(db *gorm.DB) ports.EmailOutboxRepository {
+	return &emailOutboxRepository{db: db}
+}
+
+func (r *emailOutboxRepository) Create(
+	ctx context.Context,
+	msg *domain.EmailOutboxMessage,
+) (bool, error) {
+
+	m := mapper.ToModelEmailOutboxMessage(msg)
+
+	res := dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "idempotency_key"}},
+			DoNothing: true,
+		}).
+		Create(m)
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	msg.ID = m.ID
+	msg.CreatedAt = m.CreatedAt
+	return res.RowsAffected > 0, nil
+}
+
+func (r *emailOutboxRepository) ClaimDue(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+	leaseUntil time.Time,
+) ([]*domain.EmailOutboxMessage, error) {
+
+	var ms []model.EmailOutboxMessage
+
+	// presisi timestamp Postgres = mikrodetik; MarkSent / MarkFailed mencocokkan
+	// next_attempt_at dengan nilai ini persis
+	leaseUntil = leaseUntil.Truncate(time.Microsecond)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND next_attempt_at <= ?",
+				[]string{domain.EmailOutboxStatusPending, domain.EmailOutboxStatusProcessing},
+				now,
+			).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&ms).Error
+		if err != nil || len(ms) == 0 {
+			return err
+		}
+
+		ids := make([]uint64, len(ms))
+		for i := range ms {
+			ids[i] = ms[i].ID
+		}
+
+		return tx.Model(&model.EmailOutboxMessage{}).
+			Where("id IN ?", ids).
+			Updates(map[string]any{
+				"status":          domain.EmailOutboxStatusProcessing,
+				"next_attempt_at": leaseUntil,
+				"updated_at":      now,
+				// lease kedaluwarsa (worker crash / kirim terlalu lama) dihitung satu percobaan
+				"attempts": gorm.Expr("attempts + CASE WHEN status = ? THEN 1 ELSE 0 END", domain.EmailOutboxStatusProcessing),
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.EmailOutboxMessage, 0, len(ms))
+	for i := range ms {
+		if ms[i].Status == domain.EmailOutboxStatusProcessing {
+			ms[i].Attempts++
+		}
+		ms[i].Status = domain.EmailOutboxStatusProcessing
+		ms[i].NextAttemptAt = leaseUntil
+		result = append(result, mapper.ToDomainEmailOutboxMessage(&ms[i]))
+	}
+
+	return result, nil
+}
+
+func (r *emailOutboxRepository) MarkSent(
+	ctx context.Context,
+	id uint64,
+	leaseUntil time.Time,
+	sentAt time.Time,
+) (bool, error) {
+
+	// payload bisa berisi OTP / link sekali pakai → tidak disimpan setelah terkirim
+	res := r.claimed(ctx, id, leaseUntil).
+		Updates(map[string]any{
+			"status":     domain.EmailOutboxStatusSent,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"sent_at":    sentAt,
+			"last_error": "",
+			"payload":    "{}",
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *emailOutboxRepository) MarkFailed(
+	ctx context.Context,
+	id uint64,
+	leaseUntil time.Time,
+	attempts int,
+	nextAttemptAt time.Time,
+	lastError string,
+	dead bool,
+) (bool, error) {
+
+	status := domain.EmailOutboxStatusPending
+	if dead {
+		status = domain.EmailOutboxStatusDead
+	}
+
+	res := r.claimed(ctx, id, leaseUntil).
+		Updates(map[string]any{
+			"status":          status,
+			"attempts":        attempts,
+			"next_attempt_at": nextAttemptAt,
+			"last_error":      lastError,
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+// claimed: baris masih dipegang lease ini; setelah lease habis worker lain
+// bisa sudah mengambil (dan mengirim) pesan yang sama
+func (r *emailOutboxRepository) claimed(ctx context.Context, id uint64, leaseUntil time.Time) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Model(&model.EmailOutboxMessage{}).
+		Where("id = ? AND status = ? AND next_attempt_at = ?",
+			id,
+			domain.EmailOutboxStatusProcessing,
+			leaseUntil.Truncate(time.Microsecond),
+		)
+}
+
+func (r *emailOutboxRepository) GetByID(
+	ctx context.Context,
+	id uint64,
+) (*domain.EmailOutboxMessage, error) {
+
+	var m model.EmailOutboxMessage
+
+	err := r.db.WithContext(ctx).First(&m, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.ToDomainEmailOutboxMessage(&m), nil
+}
+
+func (r *emailOutboxRepository) List(
+	ctx context.Context,
+	status string,
+	limit, offset int,
+) ([]*domain.EmailOutboxMessage, error) {
+
+	var ms []model.EmailOutboxMessage
+
+	q := r.db.WithContext(ctx)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+
+	err := q.
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&ms).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.EmailOutboxMessage, 0, len(ms))
+	for i := range ms {
+		result = append(result, mapper.ToDomainEmailOutboxMessage(&ms[i]))
+	}
+
+	return result, nil
+}
+
+func (r *emailOutboxRepository) Replay(
+	ctx context.Context,
+	id uint64,
+	now time.Time,
+) error {
+
+	return r.db.WithContext(ctx).
+		Model(&model.EmailOutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":          domain.EmailOutboxStatusPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+		}).Error
+}
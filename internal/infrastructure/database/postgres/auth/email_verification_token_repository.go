@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	mapper "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/mappers/auth"
+	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/email"
+	dbctx "github.com/dhanarrizky/Golang-template/pkg/database"
+	"gorm.io/gorm"
+)
+
+type emailVerificationTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailVerificationTokenRepository(db *gorm.DB) ports.EmailVerificationTokenRepository {
+	return &emailVerificationTokenRepository{db: db}
+}
+
+func (r *emailVerificationTokenRepository) Create(
+	ctx context.Context,
+	token *domain.EmailVerificationToken,
+) error {
+
+	m := mapper.ToModelEmailVerificationToken(token)
+	if err := dbctx.GetDB(ctx, r.db).WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+
+	token.ID = m.ID
+	token.CreatedAt = m.CreatedAt
+	return nil
+}
+
+func (r *emailVerificationTokenRepository) GetByTokenHash(
+	ctx context.Context,
+	hash string,
+) (*domain.EmailVerificationToken, error) {
+
+	var m model.EmailVerificationToken
+
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Where("token_hash = ?", hash).
+		First(&m).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.ToDomainEmailVerificationToken(&m), nil
+}
+
+func (r *emailVerificationTokenRepository) GetLatestByUserAndPurpose(
+	ctx context.Context,
+	userID uint64,
+	purpose string,
+) (*domain.EmailVerificationToken, error) {
+
+	var m model.EmailVerificationToken
+
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Where("user_id = ? AND purpose = ?", userID, purpose).
+		Order("created_at DESC").
+		First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.ToDomainEmailVerificationToken(&m), nil
+}
+
+func (r *emailVerificationTokenRepository) IncrementAttempts(
+	ctx context.Context,
+	id uint64,
+) (int, error) {
+
+	var attempts []int
+
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Raw(
+			`UPDATE email_verification_tokens SET attempts = attempts + 1
+			 WHERE id = ?
+			 RETURNING attempts`,
+			id,
+		).
+		Scan(&attempts).
+		Error
+	if err != nil {
+		return 0, err
+	}
+	if len(attempts) == 0 {
+		return 0, nil
+	}
+
+	return attempts[0], nil
+}
+
+func (r *emailVerificationTokenRepository) DeleteByUser(
+	ctx context.Context,
+	userID uint64,
+) error {
+
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Where("user_id = ?", userID).
+		Delete(&model.EmailVerificationToken{}).Error
+}
+
+func (r *emailVerificationTokenRepository) Delete(
+	ctx context.Context,
+	id uint64,
+) error {
+
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Delete(&model.EmailVerificationToken{}, id).Error
+}
+
+func (r *emailVerificationTokenRepository) DeleteByUserAndPurpose(
+	ctx context.Context,
+	userID uint64,
+	purpose string,
+) error {
+
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Where("user_id = ? AND purpose = ?", userID, purpose).
+		Delete(&model.EmailVerificationToken{}).Error
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	mapper "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/mappers/auth"
+	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/auth"
+	"gorm.io/gorm"
+)
+
+type impersonationAuditRepository struct {
+	db *gorm.DB
+}
+
+func NewImpersonationAuditRepository(db *gorm.DB) ports.ImpersonationAuditRepository {
+	return &impersonationAuditRepository{db: db}
+}
+
+func (r *impersonationAuditRepository) Create(
+	ctx context.Context,
+	event *domain.ImpersonationAuditEvent,
+) error {
+
+	m := mapper.ToModelImpersonationAuditEvent(event)
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+
+	event.ID = m.ID
+	event.CreatedAt = m.CreatedAt
+	return nil
+}
+
+func (r *impersonationAuditRepository) GetRecentBySubject(
+	ctx context.Context,
+	subjectID uint64,
+	limit int,
+) ([]*domain.ImpersonationAuditEvent, error) {
+
+	var models []model.ImpersonationAuditEvent
+
+	err := r.db.WithContext(ctx).
+		Where("subject_id = ?", subjectID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*domain.ImpersonationAuditEvent, 0, len(models))
+	for i := range models {
+		events = append(events, mapper.ToDomainImpersonationAuditEvent(&models[i]))
+	}
+
+	return events, nil
+}
+
+func (r *impersonationAuditRepository) IsTokenStopped(
+	ctx context.Context,
+	tokenID string,
+) (bool, error) {
+
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&model.ImpersonationAuditEvent{}).
+		Where("token_id = ? AND action = ?", tokenID, string(domain.ImpersonationActionStop)).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	mapper "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/mappers/auth"
 	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
 	ports "github.com/dhanarrizky/Golang-template/internal/ports/auth"
 	"gorm.io/gorm"
@@ -13,61 +15,137 @@ type loginAttemptRepository struct {
 	db *gorm.DB
 }
 
-const (
-	maxAttempts   = 5
-	windowMinutes = 15
-)
-
 func NewLoginAttemptRepository(db *gorm.DB) ports.LoginAttemptRepository {
 	return &loginAttemptRepository{db: db}
 }
 
-func (r *loginAttemptRepository) IsRateLimited(
+type failureStats struct {
+	Count int
+	Last  *time.Time
+}
+
+func (r *loginAttemptRepository) Record(
 	ctx context.Context,
-	identifier string,
-) bool {
+	attempt *domain.LoginAttempt,
+) error {
 
-	var count int64
-	since := time.Now().Add(-windowMinutes * time.Minute)
+	m := mapper.ToModelLoginAttempt(attempt)
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+
+	attempt.ID = m.ID
+	attempt.CreatedAt = m.CreatedAt
+	return nil
+}
+
+func (r *loginAttemptRepository) CountFailuresByIdentifier(
+	ctx context.Context,
+	identifier string,
+	since time.Time,
+) (int, time.Time, error) {
 
+	// hitung ulang sejak login sukses terakhir
+	var lastSuccess *time.Time
 	err := r.db.WithContext(ctx).
 		Model(&model.LoginAttempt{}).
-		Where(
-			"identifier = ? AND success = FALSE AND created_at >= ?",
-			identifier,
-			since,
-		).
-		Count(&count).Error
-
+		Select("MAX(created_at)").
+		Where("identifier = ? AND success = TRUE", identifier).
+		Scan(&lastSuccess).Error
 	if err != nil {
-		// Fail-safe: kalau DB error, anggap limited
-		return true
+		return 0, time.Time{}, err
+	}
+
+	if lastSuccess != nil && lastSuccess.After(since) {
+		since = *lastSuccess
 	}
 
-	return count >= maxAttempts
+	return r.countFailures(ctx, "identifier = ?", identifier, since)
 }
 
-func (r *loginAttemptRepository) RecordFailedAttempt(
+func (r *loginAttemptRepository) CountFailuresByIP(
 	ctx context.Context,
-	identifier string,
-) error {
+	ipAddress string,
+	since time.Time,
+) (int, time.Time, error) {
 
-	attempt := &model.LoginAttempt{
-		Identifier: identifier,
-		Success:    false,
-		CreatedAt:  time.Now(),
+	return r.countFailures(ctx, "ip_address = ?", ipAddress, since)
+}
+
+func (r *loginAttemptRepository) countFailures(
+	ctx context.Context,
+	where string,
+	value string,
+	since time.Time,
+) (int, time.Time, error) {
+
+	var stats failureStats
+
+	err := r.db.WithContext(ctx).
+		Model(&model.LoginAttempt{}).
+		Select("COUNT(*) AS count, MAX(created_at) AS last").
+		Where(where, value).
+		Where("success = FALSE AND created_at > ?", since).
+		Where("failure_reason IN ?", domain.LockoutCountedOutcomes()).
+		Scan(&stats).Error
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	var last time.Time
+	if stats.Last != nil {
+		last = *stats.Last
 	}
 
-	return r.db.WithContext(ctx).Create(attempt).Error
+	return stats.Count, last, nil
 }
 
-func (r *loginAttemptRepository) ResetAttempts(
+func (r *loginAttemptRepository) List(
 	ctx context.Context,
-	identifier string,
-) error {
+	filter ports.LoginAttemptFilter,
+) ([]*domain.LoginAttempt, int64, error) {
+
+	query := r.db.WithContext(ctx).Model(&model.LoginAttempt{})
+
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.IPAddress != "" {
+		query = query.Where("ip_address = ?", filter.IPAddress)
+	}
+	if filter.Success != nil {
+		query = query.Where("success = ?", *filter.Success)
+	}
+	if filter.Reason != "" {
+		query = query.Where("failure_reason = ?", filter.Reason)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []model.LoginAttempt
+
+	err := query.
+		Order("created_at DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&models).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	attempts := make([]*domain.LoginAttempt, 0, len(models))
+	for i := range models {
+		attempts = append(attempts, mapper.ToDomainLoginAttempt(&models[i]))
+	}
 
-	return r.db.WithContext(ctx).
-		Where("identifier = ?", identifier).
-		Delete(&model.LoginAttempt{}).
-		Error
+	return attempts, total, nil
 }
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"context"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	mapper "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/mappers/auth"
+	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/auth"
+	"gorm.io/gorm"
+)
+
+type loginRiskEventRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginRiskEventRepository(db *gorm.DB) ports.LoginRiskEventRepository {
+	return &loginRiskEventRepository{db: db}
+}
+
+func (r *loginRiskEventRepository) Create(
+	ctx context.Context,
+	event *domain.LoginRiskEvent,
+) error {
+
+	m := mapper.ToModelLoginRiskEvent(event)
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+
+	event.ID = m.ID
+	event.CreatedAt = m.CreatedAt
+	return nil
+}
+
+func (r *loginRiskEventRepository) MarkCompleted(ctx context.Context, id uint64) error {
+	return r.db.WithContext(ctx).
+		Model(&model.LoginRiskEvent{}).
+		Where("id = ?", id).
+		Update("completed", true).Error
+}
+
+func (r *loginRiskEventRepository) GetRecentByUser(
+	ctx context.Context,
+	userID uint64,
+	limit int,
+) ([]*domain.LoginRiskEvent, error) {
+
+	var models []model.LoginRiskEvent
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*domain.LoginRiskEvent, 0, len(models))
+	for i := range models {
+		events = append(events, mapper.ToDomainLoginRiskEvent(&models[i]))
+	}
+
+	return events, nil
+}
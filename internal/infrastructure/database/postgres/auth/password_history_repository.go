@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"context"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	mapper "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/mappers/auth"
+	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/users"
+	dbctx "github.com/dhanarrizky/Golang-template/pkg/database"
+	"gorm.io/gorm"
+)
+
+type passwordHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordHistoryRepository(db *gorm.DB) ports.PasswordHistoryRepository {
+	return &passwordHistoryRepository{db: db}
+}
+
+func (r *passwordHistoryRepository) Create(
+	ctx context.Context,
+	entry *domain.PasswordHistory,
+) error {
+
+	m := mapper.ToModelPasswordHistory(entry)
+
+	// ikut transaksi ganti password
+	if err := dbctx.GetDB(ctx, r.db).WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+
+	entry.ID = m.ID
+	entry.CreatedAt = m.CreatedAt
+	return nil
+}
+
+func (r *passwordHistoryRepository) GetRecent(
+	ctx context.Context,
+	userID uint64,
+	limit int,
+) ([]*domain.PasswordHistory, error) {
+
+	var models []model.PasswordHistory
+
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*domain.PasswordHistory, 0, len(models))
+	for i := range models {
+		entries = append(entries, mapper.ToDomainPasswordHistory(&models[i]))
+	}
+	return entries, nil
+}
+
+func (r *passwordHistoryRepository) Prune(
+	ctx context.Context,
+	userID uint64,
+	keep int,
+) error {
+
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Exec(
+			`DELETE FROM password_histories
+			 WHERE user_id = ? AND id NOT IN (
+			     SELECT id FROM password_histories
+			     WHERE user_id = ?
+			     ORDER BY created_at DESC, id DESC
+			     LIMIT ?
+			 )`,
+			userID, userID, keep,
+		).Error
+}
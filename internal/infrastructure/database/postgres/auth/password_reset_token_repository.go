@@ -1,56 +1,140 @@
 package auth
 
-// import (
-// 	"context"
-
-// 	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
-// 	mapper "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/mappers/auth"
-// 	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
-// 	ports "github.com/dhanarrizky/Golang-template/internal/ports/auth"
-// 	"gorm.io/gorm"
-// )
-
-// type passwordResetTokenRepository struct {
-// 	db *gorm.DB
-// }
-
-// func NewPasswordResetTokenRepository(db *gorm.DB) ports.PasswordResetTokenRepository {
-// 	return &passwordResetTokenRepository{db: db}
-// }
-
-// func (r *passwordResetTokenRepository) Create(
-// 	ctx context.Context,
-// 	token *domain.PasswordResetToken,
-// ) error {
-
-// 	m := mapper.ToModelPasswordResetToken(token)
-// 	return r.db.WithContext(ctx).Create(m).Error
-// }
-
-// func (r *passwordResetTokenRepository) GetByTokenHash(
-// 	ctx context.Context,
-// 	hash string,
-// ) (*domain.PasswordResetToken, error) {
-
-// 	var m model.PasswordResetToken
-
-// 	err := r.db.WithContext(ctx).
-// 		Where("token_hash = ?", hash).
-// 		First(&m).Error
-// 	if err != nil {
-// 		return nil, err
-// 	}
-
-// 	return mapper.ToDomainPasswordResetToken(&m), nil
-// }
-
-// func (r *passwordResetTokenRepository) MarkUsed(
-// 	ctx context.Context,
-// 	id uint64,
-// ) error {
-
-// 	return r.db.WithContext(ctx).
-// 		Model(&model.PasswordResetToken{}).
-// 		Where("id = ?", id).
-// 		Update("used", true).Error
-// }
+import (
+	"context"
+	"errors"
+	"time"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	mapper "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/mappers/auth"
+	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/auth"
+	dbctx "github.com/dhanarrizky/Golang-template/pkg/database"
+	"gorm.io/gorm"
+)
+
+type passwordResetTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetTokenRepository(db *gorm.DB) ports.PasswordResetTokenRepository {
+	return &passwordResetTokenRepository{db: db}
+}
+
+func (r *passwordResetTokenRepository) Create(
+	ctx context.Context,
+	token *domain.PasswordResetToken,
+) error {
+
+	m := mapper.ToModelPasswordResetToken(token)
+
+	// ikut transaksi jika ada (token + outbox ditulis bersamaan)
+	if err := dbctx.GetDB(ctx, r.db).WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+
+	token.ID = m.ID
+	token.CreatedAt = m.CreatedAt
+	return nil
+}
+
+func (r *passwordResetTokenRepository) GetByTokenHash(
+	ctx context.Context,
+	hash string,
+) (*domain.PasswordResetToken, error) {
+
+	var m model.PasswordResetToken
+
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Where("token_hash = ?", hash).
+		First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.ToDomainPasswordResetToken(&m), nil
+}
+
+func (r *passwordResetTokenRepository) GetLatestActiveByUser(
+	ctx context.Context,
+	userID uint64,
+) (*domain.PasswordResetToken, error) {
+
+	var m model.PasswordResetToken
+
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Where("user_id = ? AND used = ? AND expires_at > ?", userID, false, time.Now()).
+		Order("created_at DESC").
+		First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.ToDomainPasswordResetToken(&m), nil
+}
+
+func (r *passwordResetTokenRepository) IncrementAttempts(
+	ctx context.Context,
+	id uint64,
+) (int, error) {
+
+	var attempts []int
+
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Raw(
+			`UPDATE password_reset_tokens SET attempts = attempts + 1
+			 WHERE id = ?
+			 RETURNING attempts`,
+			id,
+		).
+		Scan(&attempts).
+		Error
+	if err != nil {
+		return 0, err
+	}
+	if len(attempts) == 0 {
+		return 0, nil
+	}
+
+	return attempts[0], nil
+}
+
+func (r *passwordResetTokenRepository) MarkUsed(
+	ctx context.Context,
+	id uint64,
+) (bool, error) {
+
+	// used = false di WHERE: dua request reset paralel, hanya satu yang menang
+	res := dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Model(&model.PasswordResetToken{}).
+		Where("id = ? AND used = ?", id, false).
+		Updates(map[string]any{
+			"used":    true,
+			"used_at": time.Now(),
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+func (r *passwordResetTokenRepository) InvalidateByUser(
+	ctx context.Context,
+	userID uint64,
+) error {
+
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Model(&model.PasswordResetToken{}).
+		Where("user_id = ? AND used = ?", userID, false).
+		Updates(map[string]any{
+			"used":    true,
+			"used_at": time.Now(),
+		}).Error
+}
@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	mapper "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/mappers/auth"
+	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/auth"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ================= QUOTA PLAN =================
+
+type quotaPlanRepository struct {
+	db *gorm.DB
+}
+
+func NewQuotaPlanRepository(db *gorm.DB) ports.QuotaPlanRepository {
+	return &quotaPlanRepository{db: db}
+}
+
+func (r *quotaPlanRepository) GetByRoleID(
+	ctx context.Context,
+	roleID uint64,
+) (*domain.QuotaPlan, error) {
+
+	var m model.QuotaPlan
+
+	err := r.db.WithContext(ctx).
+		Where("role_id = ?", roleID).
+		First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.ToDomainQuotaPlan(&m), nil
+}
+
+func (r *quotaPlanRepository) List(ctx context.Context) ([]*domain.QuotaPlan, error) {
+	var models []model.QuotaPlan
+
+	if err := r.db.WithContext(ctx).Order("role_id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	plans := make([]*domain.QuotaPlan, 0, len(models))
+	for i := range models {
+		plans = append(plans, mapper.ToDomainQuotaPlan(&models[i]))
+	}
+
+	return plans, nil
+}
+
+func (r *quotaPlanRepository) Upsert(
+	ctx context.Context,
+	plan *domain.QuotaPlan,
+) error {
+
+	m := mapper.ToModelQuotaPlan(plan)
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "role_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "daily_limit", "monthly_limit", "updated_at"}),
+		}).
+		Create(m).Error
+	if err != nil {
+		return err
+	}
+
+	plan.ID = m.ID
+	plan.CreatedAt = m.CreatedAt
+	plan.UpdatedAt = m.UpdatedAt
+	return nil
+}
+
+func (r *quotaPlanRepository) DeleteByRoleID(ctx context.Context, roleID uint64) error {
+	return r.db.WithContext(ctx).
+		Where("role_id = ?", roleID).
+		Delete(&model.QuotaPlan{}).Error
+}
+
+// ================= QUOTA OVERRIDE =================
+
+type quotaOverrideRepository struct {
+	db *gorm.DB
+}
+
+func NewQuotaOverrideRepository(db *gorm.DB) ports.QuotaOverrideRepository {
+	return &quotaOverrideRepository{db: db}
+}
+
+func (r *quotaOverrideRepository) GetBySubject(
+	ctx context.Context,
+	subjectType, subjectID string,
+) (*domain.QuotaOverride, error) {
+
+	var m model.QuotaOverride
+
+	err := r.db.WithContext(ctx).
+		Where("subject_type = ? AND subject_id = ?", subjectType, subjectID).
+		First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.ToDomainQuotaOverride(&m), nil
+}
+
+func (r *quotaOverrideRepository) Upsert(
+	ctx context.Context,
+	override *domain.QuotaOverride,
+) error {
+
+	m := mapper.ToModelQuotaOverride(override)
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "subject_type"}, {Name: "subject_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"daily_limit", "monthly_limit", "reason", "created_by", "expires_at", "updated_at",
+			}),
+		}).
+		Create(m).Error
+	if err != nil {
+		return err
+	}
+
+	override.ID = m.ID
+	override.CreatedAt = m.CreatedAt
+	override.UpdatedAt = m.UpdatedAt
+	return nil
+}
+
+func (r *quotaOverrideRepository) Delete(
+	ctx context.Context,
+	subjectType, subjectID string,
+) error {
+
+	return r.db.WithContext(ctx).
+		Where("subject_type = ? AND subject_id = ?", subjectType, subjectID).
+		Delete(&model.QuotaOverride{}).Error
+}
+
+// ================= QUOTA USAGE =================
+
+type quotaUsageRepository struct {
+	db *gorm.DB
+}
+
+func NewQuotaUsageRepository(db *gorm.DB) ports.QuotaUsageRepository {
+	return &quotaUsageRepository{db: db}
+}
+
+func (r *quotaUsageRepository) GetCount(
+	ctx context.Context,
+	subjectType, subjectID, period string,
+	periodStart time.Time,
+) (int64, error) {
+
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&model.QuotaUsage{}).
+		Select("count").
+		Where("subject_type = ? AND subject_id = ? AND period = ? AND period_start = ?",
+			subjectType, subjectID, period, periodStart).
+		Scan(&count).Error
+
+	return count, err
+}
+
+func (r *quotaUsageRepository) Upsert(
+	ctx context.Context,
+	usages []*domain.QuotaUsage,
+) error {
+
+	if len(usages) == 0 {
+		return nil
+	}
+
+	models := make([]*model.QuotaUsage, 0, len(usages))
+	for _, u := range usages {
+		models = append(models, mapper.ToModelQuotaUsage(u))
+	}
+
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{
+				{Name: "subject_type"}, {Name: "subject_id"}, {Name: "period"}, {Name: "period_start"},
+			},
+			DoUpdates: clause.Set{
+				{Column: clause.Column{Name: "count"}, Value: gorm.Expr("GREATEST(quota_usage.count, excluded.count)")},
+				{Column: clause.Column{Name: "updated_at"}, Value: gorm.Expr("excluded.updated_at")},
+			},
+		}).
+		Create(&models).Error
+}
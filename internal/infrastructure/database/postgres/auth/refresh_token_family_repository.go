@@ -8,6 +8,7 @@ import (
 	mapper "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/mappers/auth"
 	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
 	ports "github.com/dhanarrizky/Golang-template/internal/ports/auth"
+	dbctx "github.com/dhanarrizky/Golang-template/pkg/database"
 	"gorm.io/gorm"
 )
 
@@ -25,7 +26,7 @@ func (r *refreshTokenFamilyRepository) Create(
 ) error {
 
 	m := mapper.ToModelRefreshTokenFamily(family)
-	return r.db.WithContext(ctx).Create(m).Error
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).Create(m).Error
 }
 
 func (r *refreshTokenFamilyRepository) GetByID(
@@ -35,7 +36,7 @@ func (r *refreshTokenFamilyRepository) GetByID(
 
 	var m model.RefreshTokenFamily
 
-	err := r.db.WithContext(ctx).
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
 		First(&m, id).Error
 	if err != nil {
 		return nil, err
@@ -51,7 +52,7 @@ func (r *refreshTokenFamilyRepository) GetByUserID(
 
 	var models []model.RefreshTokenFamily
 
-	err := r.db.WithContext(ctx).
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
 		Where("user_id = ?", userID).
 		Order("created_at DESC").
 		Find(&models).Error
@@ -78,7 +79,7 @@ func (r *refreshTokenFamilyRepository) Revoke(
 
 	now := time.Now()
 
-	return r.db.WithContext(ctx).
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
 		Model(&model.RefreshTokenFamily{}).
 		Where("id = ?", id).
 		Update("revoked_at", &now).Error
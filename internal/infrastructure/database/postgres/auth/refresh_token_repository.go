@@ -8,6 +8,7 @@ import (
 	mapper "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/mappers/auth"
 	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
 	ports "github.com/dhanarrizky/Golang-template/internal/ports/auth"
+	dbctx "github.com/dhanarrizky/Golang-template/pkg/database"
 	"gorm.io/gorm"
 )
 
@@ -25,7 +26,7 @@ func (r *refreshTokenRepository) Create(
 ) error {
 
 	m := mapper.ToModelRefreshToken(token)
-	return r.db.WithContext(ctx).Create(m).Error
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).Create(m).Error
 }
 
 func (r *refreshTokenRepository) GetByTokenHash(
@@ -35,7 +36,7 @@ func (r *refreshTokenRepository) GetByTokenHash(
 
 	var m model.RefreshToken
 
-	err := r.db.WithContext(ctx).
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
 		Where("token_hash = ?", hash).
 		First(&m).Error
 	if err != nil {
@@ -52,7 +53,7 @@ func (r *refreshTokenRepository) Revoke(
 
 	now := time.Now()
 
-	return r.db.WithContext(ctx).
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
 		Model(&model.RefreshToken{}).
 		Where("id = ?", id).
 		Update("revoked_at", &now).Error
@@ -65,7 +66,7 @@ func (r *refreshTokenRepository) RevokeByFamily(
 
 	now := time.Now()
 
-	return r.db.WithContext(ctx).
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
 		Model(&model.RefreshToken{}).
 		Where("family_id = ?", familyID).
 		Update("revoked_at", &now).Error
@@ -77,7 +78,7 @@ func (r *refreshTokenRepository) DeleteExpired(
 
 	now := time.Now()
 
-	return r.db.WithContext(ctx).
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
 		Where("expires_at < ?", now).
 		Delete(&model.RefreshToken{}).Error
 }
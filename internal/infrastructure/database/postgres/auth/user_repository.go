@@ -8,6 +8,7 @@ import (
 	mapper "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/mappers/auth"
 	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
 	ports "github.com/dhanarrizky/Golang-template/internal/ports/users"
+	dbctx "github.com/dhanarrizky/Golang-template/pkg/database"
 
 	"gorm.io/gorm"
 )
@@ -27,7 +28,7 @@ func (r *userRepository) GetByID(
 
 	var m model.User
 
-	err := r.db.WithContext(ctx).
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
 		First(&m, id).Error
 	if err != nil {
 		return nil, err
@@ -43,7 +44,7 @@ func (r *userRepository) GetByEmail(
 
 	var m model.User
 
-	err := r.db.WithContext(ctx).
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
 		Where("email = ?", email).
 		First(&m).Error
 	if err != nil {
@@ -60,7 +61,7 @@ func (r *userRepository) GetByEmailOrUsername(
 
 	var m model.User
 
-	err := r.db.WithContext(ctx).
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
 		Where(
 			"email = ? OR username = ?",
 			identifier,
@@ -81,7 +82,7 @@ func (r *userRepository) GetList(
 
 	var models []model.User
 
-	err := r.db.WithContext(ctx).
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
 		Where("deleted_at IS NULL").
 		Order("created_at DESC").
 		Find(&models).Error
@@ -104,7 +105,7 @@ func (r *userRepository) Create(
 ) error {
 
 	m := mapper.ToModelUser(user)
-	return r.db.WithContext(ctx).Create(m).Error
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).Create(m).Error
 }
 
 func (r *userRepository) Update(
@@ -113,7 +114,7 @@ func (r *userRepository) Update(
 ) error {
 
 	m := mapper.ToModelUser(user)
-	return r.db.WithContext(ctx).Save(m).Error
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).Save(m).Error
 }
 
 func (r *userRepository) UpdatePassword(
@@ -122,7 +123,24 @@ func (r *userRepository) UpdatePassword(
 	hashedPassword string,
 ) error {
 
-	return r.db.WithContext(ctx).
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"password_hash":             hashedPassword,
+			"password_changed_at":       time.Now(),
+			"password_expiry_warned_at": nil,
+			"updated_at":                time.Now(),
+		}).Error
+}
+
+func (r *userRepository) RehashPassword(
+	ctx context.Context,
+	id uint64,
+	hashedPassword string,
+) error {
+
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
 		Model(&model.User{}).
 		Where("id = ?", id).
 		Updates(map[string]interface{}{
@@ -131,13 +149,28 @@ func (r *userRepository) UpdatePassword(
 		}).Error
 }
 
+func (r *userRepository) SetMustChangePassword(
+	ctx context.Context,
+	id uint64,
+	must bool,
+) error {
+
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"must_change_password": must,
+			"updated_at":           time.Now(),
+		}).Error
+}
+
 func (r *userRepository) UpdateUsername(
 	ctx context.Context,
 	id uint64,
 	username string,
 ) error {
 
-	return r.db.WithContext(ctx).
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
 		Model(&model.User{}).
 		Where("id = ?", id).
 		Updates(map[string]interface{}{
@@ -146,6 +179,23 @@ func (r *userRepository) UpdateUsername(
 		}).Error
 }
 
+func (r *userRepository) UpdateEmail(
+	ctx context.Context,
+	id uint64,
+	email string,
+	verified bool,
+) error {
+
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"email":          email,
+			"email_verified": verified,
+			"updated_at":     time.Now(),
+		}).Error
+}
+
 func (r *userRepository) ExistsByUsernameExceptID(
 	ctx context.Context,
 	username string,
@@ -154,7 +204,7 @@ func (r *userRepository) ExistsByUsernameExceptID(
 
 	var exists bool
 
-	err := r.db.WithContext(ctx).
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
 		Model(&model.User{}).
 		Select("1").
 		Where("username = ? AND id <> ?", username, exceptID).
@@ -172,7 +222,7 @@ func (r *userRepository) ExistsByEmailExceptID(
 
 	var exists bool
 
-	err := r.db.WithContext(ctx).
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
 		Model(&model.User{}).
 		Select("1").
 		Where("email = ? AND id <> ?", email, exceptID).
@@ -189,8 +239,188 @@ func (r *userRepository) SoftDelete(
 
 	now := time.Now()
 
-	return r.db.WithContext(ctx).
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
 		Model(&model.User{}).
 		Where("id = ?", id).
 		Update("deleted_at", &now).Error
 }
+
+func (r *userRepository) Lock(
+	ctx context.Context,
+	id uint64,
+	reason string,
+	until *time.Time,
+	unlockTokenHash *string,
+) error {
+
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"locked":            true,
+			"lock_reason":       reason,
+			"locked_until":      until,
+			"unlock_token_hash": unlockTokenHash,
+			"updated_at":        time.Now(),
+		}).Error
+}
+
+func (r *userRepository) Unlock(
+	ctx context.Context,
+	id uint64,
+) error {
+
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"locked":            false,
+			"lock_reason":       "",
+			"locked_until":      nil,
+			"unlock_token_hash": nil,
+			"updated_at":        time.Now(),
+		}).Error
+}
+
+func (r *userRepository) GetByUnlockTokenHash(
+	ctx context.Context,
+	hash string,
+) (*domain.User, error) {
+
+	var m model.User
+
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Where("unlock_token_hash = ? AND locked = TRUE", hash).
+		First(&m).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.ToDomainUser(&m), nil
+}
+
+func (r *userRepository) UpdatePhone(
+	ctx context.Context,
+	id uint64,
+	phone *string,
+	verified bool,
+	preferredChannel string,
+) error {
+
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"phone":             phone,
+			"phone_verified":    verified,
+			"preferred_channel": preferredChannel,
+			"updated_at":        time.Now(),
+		}).Error
+}
+
+func (r *userRepository) UpdatePreferredChannel(
+	ctx context.Context,
+	id uint64,
+	channel string,
+) error {
+
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"preferred_channel": channel,
+			"updated_at":        time.Now(),
+		}).Error
+}
+
+func (r *userRepository) SetMustChangePasswordByIDs(
+	ctx context.Context,
+	ids []uint64,
+) (int64, error) {
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	res := dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Model(&model.User{}).
+		Where("id IN ? AND deleted_at IS NULL", ids).
+		Updates(map[string]interface{}{
+			"must_change_password": true,
+			"updated_at":           time.Now(),
+		})
+
+	return res.RowsAffected, res.Error
+}
+
+func (r *userRepository) SetMustChangePasswordAll(
+	ctx context.Context,
+	exceptID uint64,
+) (int64, error) {
+
+	res := dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Model(&model.User{}).
+		Where("id <> ? AND must_change_password = ? AND deleted_at IS NULL", exceptID, false).
+		Updates(map[string]interface{}{
+			"must_change_password": true,
+			"updated_at":           time.Now(),
+		})
+
+	return res.RowsAffected, res.Error
+}
+
+func (r *userRepository) FlagExpiredPasswords(
+	ctx context.Context,
+	changedBefore time.Time,
+) (int64, error) {
+
+	res := dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Model(&model.User{}).
+		Where("password_changed_at < ? AND must_change_password = ? AND deleted_at IS NULL", changedBefore, false).
+		Updates(map[string]interface{}{
+			"must_change_password": true,
+			"updated_at":           time.Now(),
+		})
+
+	return res.RowsAffected, res.Error
+}
+
+func (r *userRepository) GetPasswordExpiring(
+	ctx context.Context,
+	changedBefore time.Time,
+	limit int,
+) ([]*domain.User, error) {
+
+	var models []model.User
+
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Where(
+			"password_changed_at < ? AND password_expiry_warned_at IS NULL AND must_change_password = ? AND deleted_at IS NULL",
+			changedBefore, false,
+		).
+		Order("password_changed_at ASC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*domain.User, 0, len(models))
+	for i := range models {
+		users = append(users, mapper.ToDomainUser(&models[i]))
+	}
+
+	return users, nil
+}
+
+func (r *userRepository) MarkPasswordExpiryWarned(
+	ctx context.Context,
+	id uint64,
+	at time.Time,
+) error {
+
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ?", id).
+		Update("password_expiry_warned_at", at).Error
+}
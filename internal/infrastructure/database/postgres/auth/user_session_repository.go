@@ -8,6 +8,7 @@ import (
 	mapper "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/mappers/auth"
 	model "github.com/dhanarrizky/Golang-template/internal/infrastructure/database/models/auth"
 	ports "github.com/dhanarrizky/Golang-template/internal/ports/users"
+	dbctx "github.com/dhanarrizky/Golang-template/pkg/database"
 	"gorm.io/gorm"
 )
 
@@ -25,7 +26,7 @@ func (r *userSessionRepository) Create(
 ) error {
 
 	m := mapper.ToModelUserSession(session)
-	return r.db.WithContext(ctx).Create(m).Error
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).Create(m).Error
 }
 
 func (r *userSessionRepository) UpdateLastSeen(
@@ -35,7 +36,7 @@ func (r *userSessionRepository) UpdateLastSeen(
 
 	now := time.Now()
 
-	return r.db.WithContext(ctx).
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
 		Model(&model.UserSession{}).
 		Where("id = ?", id).
 		Update("last_seen_at", &now).Error
@@ -48,7 +49,7 @@ func (r *userSessionRepository) Logout(
 
 	now := time.Now()
 
-	return r.db.WithContext(ctx).
+	return dbctx.GetDB(ctx, r.db).WithContext(ctx).
 		Model(&model.UserSession{}).
 		Where("id = ?", id).
 		Update("logout_at", &now).Error
@@ -61,7 +62,7 @@ func (r *userSessionRepository) GetActiveSessions(
 
 	var models []model.UserSession
 
-	err := r.db.WithContext(ctx).
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
 		Where("user_id = ? AND logout_at IS NULL", userID).
 		Find(&models).Error
 	if err != nil {
@@ -75,3 +76,28 @@ func (r *userSessionRepository) GetActiveSessions(
 
 	return sessions, nil
 }
+
+func (r *userSessionRepository) GetRecentByUser(
+	ctx context.Context,
+	userID uint64,
+	limit int,
+) ([]*domain.UserSession, error) {
+
+	var models []model.UserSession
+
+	err := dbctx.GetDB(ctx, r.db).WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("login_at DESC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*domain.UserSession, 0, len(models))
+	for i := range models {
+		sessions = append(sessions, mapper.ToDomainUserSession(&models[i]))
+	}
+
+	return sessions, nil
+}
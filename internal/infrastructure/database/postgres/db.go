@@ -15,11 +15,21 @@ func NewPostgresDB(dsn string) (*gorm.DB, error) {
 	err = db.AutoMigrate(
 		&authModels.User{},
 		&authModels.Role{},
-		// &authModels.PasswordResetToken{},
+		&authModels.PasswordResetToken{},
+		&authModels.PasswordHistory{},
 		&authModels.RefreshTokenFamily{},
 		&authModels.RefreshToken{},
 		&authModels.UserSession{},
 		&authModels.LoginAttempt{},
+		&authModels.LoginRiskEvent{},
+		&authModels.ImpersonationAuditEvent{},
+		&authModels.AccountLockEvent{},
+		&authModels.QuotaPlan{},
+		&authModels.QuotaOverride{},
+		&authModels.QuotaUsage{},
+		&authModels.EmailOTP{},
+		&authModels.EmailVerificationToken{},
+		&authModels.EmailOutboxMessage{},
 	)
 	if err != nil {
 		return nil, err
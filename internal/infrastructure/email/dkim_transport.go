@@ -0,0 +1,40 @@
+package email
+
+import (
+	"context"
+
+	mailer "github.com/dhanarrizky/Golang-template/pkg/email"
+)
+
+// DKIMTransport signs every message before handing it to next. The MIME is
+// built once here (Date / Message-ID included) so the signed bytes are exactly
+// what SMTPTransport and FileTransport send. Sitting below Sender, it signs
+// every message type the EmailSender produces.
+//
+// HTTPTransport posts fields instead of MIME, so the signature does not reach
+// the provider: with EMAIL_TRANSPORT=http DKIM is not applied by this service
+// and must be configured in the provider's dashboard instead.
+type DKIMTransport struct {
+	next   Transport
+	signer *mailer.DKIMSigner
+}
+
+func NewDKIMTransport(next Transport, signer *mailer.DKIMSigner) *DKIMTransport {
+	return &DKIMTransport{next: next, signer: signer}
+}
+
+func (t *DKIMTransport) Send(ctx context.Context, env Envelope, msg *mailer.Message) error {
+	raw, err := mailer.BuildMIME(env.From, env.To, msg)
+	if err != nil {
+		return err
+	}
+
+	signed, err := t.signer.Sign(raw)
+	if err != nil {
+		return err
+	}
+
+	out := *msg
+	out.Raw = signed
+	return t.next.Send(ctx, env, &out)
+}
@@ -0,0 +1,82 @@
+package email
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	mailer "github.com/dhanarrizky/Golang-template/pkg/email"
+)
+
+const (
+	FileFormatEML     = "eml"     // one <timestamp>-<id>.eml per message in Dir
+	FileFormatMaildir = "maildir" // Dir/{tmp,new,cur}, readable by mutt / mail clients
+)
+
+// FileTransport writes every message to disk instead of sending it.
+// Meant for local development: open the .eml in a mail client to preview.
+type FileTransport struct {
+	dir    string
+	format string
+}
+
+func NewFileTransport(dir, format string) (*FileTransport, error) {
+	switch format {
+	case "", FileFormatEML:
+		format = FileFormatEML
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	case FileFormatMaildir:
+		for _, sub := range []string{"tmp", "new", "cur"} {
+			if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown mail file format %q", format)
+	}
+
+	return &FileTransport{dir: dir, format: format}, nil
+}
+
+func (t *FileTransport) Send(_ context.Context, env Envelope, msg *mailer.Message) error {
+	raw, err := mailer.BuildMIME(env.From, env.To, msg)
+	if err != nil {
+		return err
+	}
+
+	name, err := uniqueName()
+	if err != nil {
+		return err
+	}
+
+	if t.format == FileFormatEML {
+		return os.WriteFile(filepath.Join(t.dir, name+".eml"), raw, 0o644)
+	}
+
+	// maildir: write to tmp, then rename into new so readers never see partial files
+	tmp := filepath.Join(t.dir, "tmp", name)
+	if err := os.WriteFile(tmp, raw, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(t.dir, "new", name))
+}
+
+func uniqueName() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	host, _ := os.Hostname()
+	if host == "" {
+		host = "localhost"
+	}
+
+	return fmt.Sprintf("%d.%s.%s", time.Now().UnixNano(), hex.EncodeToString(b), host), nil
+}
@@ -0,0 +1,184 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	mailer "github.com/dhanarrizky/Golang-template/pkg/email"
+)
+
+// HTTPProvider maps a message onto a provider's send API. The transport
+// takes care of the HTTP client, timeouts and status handling.
+type HTTPProvider interface {
+	BuildRequest(ctx context.Context, endpoint, apiKey string, env Envelope, msg *mailer.Message) (*http.Request, error)
+}
+
+// HTTPProviders by EMAIL_HTTP_PROVIDER. SES is not listed: its API needs
+// SigV4 signing, use its SMTP interface through SMTPTransport instead.
+var HTTPProviders = map[string]HTTPProvider{
+	"generic":  GenericHTTPProvider{},
+	"sendgrid": SendGridProvider{},
+	"mailgun":  MailgunProvider{},
+}
+
+type HTTPTransport struct {
+	client   *http.Client
+	endpoint string
+	apiKey   string
+	provider HTTPProvider
+}
+
+func NewHTTPTransport(provider HTTPProvider, endpoint, apiKey string, timeout time.Duration) *HTTPTransport {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &HTTPTransport{
+		client:   &http.Client{Timeout: timeout},
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		provider: provider,
+	}
+}
+
+func (t *HTTPTransport) Send(ctx context.Context, env Envelope, msg *mailer.Message) error {
+	req, err := t.provider.BuildRequest(ctx, t.endpoint, t.apiKey, env, msg)
+	if err != nil {
+		return err
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("mail provider returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// ================= PROVIDERS =================
+
+// GenericHTTPProvider posts {"from","to","subject","html","text"} as JSON with
+// a bearer token. Fits small in-house relays and local stub servers.
+type GenericHTTPProvider struct{}
+
+func (GenericHTTPProvider) BuildRequest(
+	ctx context.Context,
+	endpoint, apiKey string,
+	env Envelope,
+	msg *mailer.Message,
+) (*http.Request, error) {
+
+	return jsonRequest(ctx, endpoint, "Bearer "+apiKey, map[string]any{
+		"from":    env.From,
+		"to":      env.To,
+		"subject": msg.Subject,
+		"html":    msg.HTML,
+		"text":    msg.Text,
+	})
+}
+
+// SendGridProvider uses the v3 mail/send API
+type SendGridProvider struct{}
+
+func (SendGridProvider) BuildRequest(
+	ctx context.Context,
+	endpoint, apiKey string,
+	env Envelope,
+	msg *mailer.Message,
+) (*http.Request, error) {
+
+	if endpoint == "" {
+		endpoint = "https://api.sendgrid.com/v3/mail/send"
+	}
+
+	to := make([]map[string]string, 0, len(env.To))
+	for _, addr := range env.To {
+		to = append(to, sendGridAddress(addr))
+	}
+
+	return jsonRequest(ctx, endpoint, "Bearer "+apiKey, map[string]any{
+		"personalizations": []map[string]any{{"to": to}},
+		"from":             sendGridAddress(env.From),
+		"subject":          msg.Subject,
+		"content": []map[string]string{
+			{"type": "text/plain", "value": msg.Text},
+			{"type": "text/html", "value": msg.HTML},
+		},
+	})
+}
+
+func sendGridAddress(addr string) map[string]string {
+	a, err := mail.ParseAddress(addr)
+	if err != nil {
+		return map[string]string{"email": addr}
+	}
+	if a.Name == "" {
+		return map[string]string{"email": a.Address}
+	}
+	return map[string]string{"email": a.Address, "name": a.Name}
+}
+
+// MailgunProvider posts a form to https://api.mailgun.net/v3/<domain>/messages;
+// the endpoint must include the sending domain
+type MailgunProvider struct{}
+
+func (MailgunProvider) BuildRequest(
+	ctx context.Context,
+	endpoint, apiKey string,
+	env Envelope,
+	msg *mailer.Message,
+) (*http.Request, error) {
+
+	if endpoint == "" {
+		return nil, fmt.Errorf("mailgun requires EMAIL_HTTP_ENDPOINT (https://api.mailgun.net/v3/<domain>/messages)")
+	}
+
+	form := url.Values{}
+	form.Set("from", env.From)
+	for _, to := range env.To {
+		form.Add("to", to)
+	}
+	form.Set("subject", msg.Subject)
+	form.Set("text", msg.Text)
+	form.Set("html", msg.HTML)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("api", apiKey)
+
+	return req, nil
+}
+
+func jsonRequest(ctx context.Context, endpoint, authorization string, body any) (*http.Request, error) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", authorization)
+
+	return req, nil
+}
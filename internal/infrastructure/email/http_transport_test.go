@@ -0,0 +1,212 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mailer "github.com/dhanarrizky/Golang-template/pkg/email"
+)
+
+// stubProvider records the last request a provider adapter sent
+type stubProvider struct {
+	*httptest.Server
+
+	method string
+	path   string
+	header http.Header
+	body   []byte
+}
+
+func newStubProvider(t *testing.T, status int, reply string) *stubProvider {
+	t.Helper()
+
+	s := &stubProvider{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.method = r.Method
+		s.path = r.URL.Path
+		s.header = r.Header.Clone()
+		s.body, _ = io.ReadAll(r.Body)
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(reply))
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+var testEnvelope = Envelope{
+	From: "App <no-reply@example.com>",
+	To:   []string{"alice@example.com", "bob@example.com"},
+}
+
+var testMessage = &mailer.Message{
+	Subject: "Your code",
+	HTML:    "<p>123456</p>",
+	Text:    "123456",
+}
+
+func TestHTTPTransport_Generic(t *testing.T) {
+	stub := newStubProvider(t, http.StatusAccepted, `{"id":"1"}`)
+	transport := NewHTTPTransport(GenericHTTPProvider{}, stub.URL+"/send", "key-1", time.Second)
+
+	if err := transport.Send(context.Background(), testEnvelope, testMessage); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if stub.method != http.MethodPost || stub.path != "/send" {
+		t.Errorf("request = %s %s, want POST /send", stub.method, stub.path)
+	}
+	if got := stub.header.Get("Authorization"); got != "Bearer key-1" {
+		t.Errorf("Authorization = %q", got)
+	}
+	if got := stub.header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	var body struct {
+		From    string   `json:"from"`
+		To      []string `json:"to"`
+		Subject string   `json:"subject"`
+		HTML    string   `json:"html"`
+		Text    string   `json:"text"`
+	}
+	if err := json.Unmarshal(stub.body, &body); err != nil {
+		t.Fatalf("body is not JSON: %v (%s)", err, stub.body)
+	}
+	if body.From != testEnvelope.From || strings.Join(body.To, ",") != "alice@example.com,bob@example.com" ||
+		body.Subject != "Your code" || body.HTML != "<p>123456</p>" || body.Text != "123456" {
+		t.Errorf("body = %+v", body)
+	}
+}
+
+func TestHTTPTransport_SendGrid(t *testing.T) {
+	stub := newStubProvider(t, http.StatusAccepted, "")
+	transport := NewHTTPTransport(SendGridProvider{}, stub.URL+"/v3/mail/send", "sg-key", time.Second)
+
+	if err := transport.Send(context.Background(), testEnvelope, testMessage); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if got := stub.header.Get("Authorization"); got != "Bearer sg-key" {
+		t.Errorf("Authorization = %q", got)
+	}
+
+	var body struct {
+		Personalizations []struct {
+			To []map[string]string `json:"to"`
+		} `json:"personalizations"`
+		From    map[string]string   `json:"from"`
+		Subject string              `json:"subject"`
+		Content []map[string]string `json:"content"`
+	}
+	if err := json.Unmarshal(stub.body, &body); err != nil {
+		t.Fatalf("body is not JSON: %v (%s)", err, stub.body)
+	}
+
+	if len(body.Personalizations) != 1 || len(body.Personalizations[0].To) != 2 ||
+		body.Personalizations[0].To[1]["email"] != "bob@example.com" {
+		t.Errorf("personalizations = %+v", body.Personalizations)
+	}
+	// display name split from the address
+	if body.From["email"] != "no-reply@example.com" || body.From["name"] != "App" {
+		t.Errorf("from = %v", body.From)
+	}
+	if body.Subject != "Your code" || len(body.Content) != 2 ||
+		body.Content[0]["type"] != "text/plain" || body.Content[1]["value"] != "<p>123456</p>" {
+		t.Errorf("subject / content = %q %v", body.Subject, body.Content)
+	}
+}
+
+func TestHTTPTransport_Mailgun(t *testing.T) {
+	stub := newStubProvider(t, http.StatusOK, `{"message":"Queued"}`)
+	transport := NewHTTPTransport(MailgunProvider{}, stub.URL+"/v3/mg.example.com/messages", "mg-key", time.Second)
+
+	if err := transport.Send(context.Background(), testEnvelope, testMessage); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if stub.path != "/v3/mg.example.com/messages" {
+		t.Errorf("path = %s", stub.path)
+	}
+	if got := stub.header.Get("Content-Type"); got != "application/x-www-form-urlencoded" {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(stub.body)))
+	req.Header = stub.header
+	if user, pass, ok := req.BasicAuth(); !ok || user != "api" || pass != "mg-key" {
+		t.Errorf("basic auth = %q %q %v", user, pass, ok)
+	}
+	if err := req.ParseForm(); err != nil {
+		t.Fatal(err)
+	}
+	if got := req.PostForm["to"]; strings.Join(got, ",") != "alice@example.com,bob@example.com" {
+		t.Errorf("to = %v", got)
+	}
+	if req.PostForm.Get("from") != testEnvelope.From || req.PostForm.Get("subject") != "Your code" ||
+		req.PostForm.Get("text") != "123456" || req.PostForm.Get("html") != "<p>123456</p>" {
+		t.Errorf("form = %v", req.PostForm)
+	}
+}
+
+func TestHTTPTransport_MailgunNeedsEndpoint(t *testing.T) {
+	transport := NewHTTPTransport(MailgunProvider{}, "", "mg-key", time.Second)
+
+	if err := transport.Send(context.Background(), testEnvelope, testMessage); err == nil {
+		t.Fatal("Send without an endpoint returned no error")
+	}
+}
+
+func TestHTTPTransport_Errors(t *testing.T) {
+	t.Run("rejected", func(t *testing.T) {
+		stub := newStubProvider(t, http.StatusUnauthorized, "  invalid api key\n")
+		transport := NewHTTPTransport(GenericHTTPProvider{}, stub.URL, "wrong", time.Second)
+
+		err := transport.Send(context.Background(), testEnvelope, testMessage)
+		if err == nil {
+			t.Fatal("Send returned no error")
+		}
+		// status and provider message end up in the outbox error
+		if want := "mail provider returned 401: invalid api key"; err.Error() != want {
+			t.Errorf("error = %q, want %q", err, want)
+		}
+	})
+
+	t.Run("long error body is truncated", func(t *testing.T) {
+		stub := newStubProvider(t, http.StatusInternalServerError, strings.Repeat("x", 4096))
+		transport := NewHTTPTransport(GenericHTTPProvider{}, stub.URL, "key", time.Second)
+
+		err := transport.Send(context.Background(), testEnvelope, testMessage)
+		if err == nil || len(err.Error()) > 1100 {
+			t.Fatalf("error = %v", err)
+		}
+	})
+
+	t.Run("redirect is not success", func(t *testing.T) {
+		stub := newStubProvider(t, http.StatusNotModified, "")
+		transport := NewHTTPTransport(GenericHTTPProvider{}, stub.URL, "key", time.Second)
+
+		if err := transport.Send(context.Background(), testEnvelope, testMessage); err == nil {
+			t.Fatal("Send returned no error for 304")
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+		}))
+		defer srv.Close()
+		transport := NewHTTPTransport(GenericHTTPProvider{}, srv.URL, "key", 50*time.Millisecond)
+
+		if err := transport.Send(context.Background(), testEnvelope, testMessage); err == nil {
+			t.Fatal("Send returned no error after the timeout")
+		}
+	})
+}
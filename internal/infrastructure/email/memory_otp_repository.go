@@ -1,39 +1,97 @@
 package email
 
 import (
+	"context"
 	"sync"
+	"time"
 
-	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/email"
 )
 
+// MemoryOTPRepository keeps OTPs in process memory, for development and tests.
+// Expired entries are dropped lazily on access.
 type MemoryOTPRepository struct {
-	mu   sync.Mutex
-	data map[string]valueobjects.OTP
+	mu     sync.Mutex
+	nextID uint64
+	data   map[string]domain.EmailOTP
 }
 
-func NewMemoryOTPRepository() *MemoryOTPRepository {
+func NewMemoryOTPRepository() ports.EmailOTPRepository {
 	return &MemoryOTPRepository{
-		data: make(map[string]valueobjects.OTP),
+		data: make(map[string]domain.EmailOTP),
 	}
 }
 
-func (r *MemoryOTPRepository) Save(email string, otp valueobjects.OTP) error {
+func (r *MemoryOTPRepository) Save(ctx context.Context, otp *domain.EmailOTP) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.data[email] = otp
+
+	r.nextID++
+	otp.ID = r.nextID
+	if otp.CreatedAt.IsZero() {
+		otp.CreatedAt = time.Now()
+	}
+
+	r.data[otpKey(otp.Email, otp.Purpose)] = *otp
 	return nil
 }
 
-func (r *MemoryOTPRepository) Find(email string) (valueobjects.OTP, bool) {
+func (r *MemoryOTPRepository) FindActiveByEmail(
+	ctx context.Context,
+	email, purpose string,
+) (*domain.EmailOTP, error) {
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	v, ok := r.data[email]
-	return v, ok
+
+	otp, ok := r.active(otpKey(email, purpose))
+	if !ok {
+		return nil, nil
+	}
+	return &otp, nil
 }
 
-func (r *MemoryOTPRepository) Delete(email string) error {
+func (r *MemoryOTPRepository) IncrementAttempts(
+	ctx context.Context,
+	email, purpose string,
+) (int, error) {
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	delete(r.data, email)
+
+	key := otpKey(email, purpose)
+	otp, ok := r.active(key)
+	if !ok {
+		return 0, nil
+	}
+
+	otp.Attempts++
+	r.data[key] = otp
+	return otp.Attempts, nil
+}
+
+func (r *MemoryOTPRepository) DeleteByEmail(ctx context.Context, email, purpose string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.data, otpKey(email, purpose))
 	return nil
 }
+
+// active must be called with mu held
+func (r *MemoryOTPRepository) active(key string) (domain.EmailOTP, bool) {
+	otp, ok := r.data[key]
+	if !ok {
+		return domain.EmailOTP{}, false
+	}
+	if otp.IsExpired(time.Now()) {
+		delete(r.data, key)
+		return domain.EmailOTP{}, false
+	}
+	return otp, true
+}
+
+func otpKey(email, purpose string) string {
+	return purpose + ":" + email
+}
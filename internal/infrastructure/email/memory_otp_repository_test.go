@@ -0,0 +1,14 @@
+package email
+
+import (
+	"testing"
+
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/email"
+	"github.com/dhanarrizky/Golang-template/internal/ports/email/otptest"
+)
+
+func TestMemoryOTPRepository(t *testing.T) {
+	otptest.RunConformance(t, func(t *testing.T) ports.EmailOTPRepository {
+		return NewMemoryOTPRepository()
+	})
+}
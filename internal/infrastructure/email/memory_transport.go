@@ -0,0 +1,103 @@
+package email
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/email"
+	mailer "github.com/dhanarrizky/Golang-template/pkg/email"
+)
+
+// MemoryTransport keeps the most recent messages in memory instead of sending
+// them. Useful in development and in tests that assert on outgoing email.
+// It implements ports.MailCatcher.
+type MemoryTransport struct {
+	mu       sync.RWMutex
+	messages []ports.CapturedEmail
+	capacity int
+	nextID   uint64
+}
+
+// NewMemoryTransport keeps at most capacity messages (oldest dropped first); 0 = 100
+func NewMemoryTransport(capacity int) *MemoryTransport {
+	if capacity <= 0 {
+		capacity = 100
+	}
+	return &MemoryTransport{capacity: capacity}
+}
+
+func (t *MemoryTransport) Send(_ context.Context, env Envelope, msg *mailer.Message) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.nextID++
+	t.messages = append(t.messages, ports.CapturedEmail{
+		ID:      t.nextID,
+		From:    env.From,
+		To:      append([]string(nil), env.To...),
+		Subject: msg.Subject,
+		HTML:    msg.HTML,
+		Text:    msg.Text,
+		SentAt:  time.Now(),
+	})
+
+	if over := len(t.messages) - t.capacity; over > 0 {
+		t.messages = append([]ports.CapturedEmail(nil), t.messages[over:]...)
+	}
+
+	return nil
+}
+
+// Messages returns captured messages, newest first
+func (t *MemoryTransport) Messages() []ports.CapturedEmail {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	out := make([]ports.CapturedEmail, len(t.messages))
+	for i, m := range t.messages {
+		out[len(t.messages)-1-i] = m
+	}
+	return out
+}
+
+// Get returns the message with the given ID, false if it was never captured or already dropped
+func (t *MemoryTransport) Get(id uint64) (ports.CapturedEmail, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, m := range t.messages {
+		if m.ID == id {
+			return m, true
+		}
+	}
+	return ports.CapturedEmail{}, false
+}
+
+func (t *MemoryTransport) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.messages = nil
+}
+
+// CaptureTransport records every message in catcher and then forwards it to
+// next, so development can keep a real SMTP relay while still inspecting mail
+// in the app. A nil next only captures.
+type CaptureTransport struct {
+	next    Transport
+	catcher *MemoryTransport
+}
+
+func NewCaptureTransport(next Transport, catcher *MemoryTransport) *CaptureTransport {
+	return &CaptureTransport{next: next, catcher: catcher}
+}
+
+func (t *CaptureTransport) Send(ctx context.Context, env Envelope, msg *mailer.Message) error {
+	_ = t.catcher.Send(ctx, env, msg)
+
+	if t.next == nil {
+		return nil
+	}
+	return t.next.Send(ctx, env, msg)
+}
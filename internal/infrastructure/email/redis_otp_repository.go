@@ -0,0 +1,135 @@
+package email
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/email"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisOTPKeyPrefix = "email_otp:"
+	redisOTPSeqKey    = "email_otp:seq"
+)
+
+// KEYS[1] = otp hash
+// returns the new attempt count, or 0 when the OTP is gone (expired / deleted)
+var incrementOTPAttemptsScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+  return 0
+end
+return redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+`)
+
+// RedisOTPRepository stores one hash per (purpose, email) that expires together
+// with the OTP, so there is nothing to clean up. IDs come from a shared counter
+// and are only used for outbox idempotency keys.
+type RedisOTPRepository struct {
+	client *redis.Client
+}
+
+func NewRedisOTPRepository(client *redis.Client) ports.EmailOTPRepository {
+	return &RedisOTPRepository{client: client}
+}
+
+func (r *RedisOTPRepository) Save(ctx context.Context, otp *domain.EmailOTP) error {
+	id, err := r.client.Incr(ctx, redisOTPSeqKey).Uint64()
+	if err != nil {
+		return err
+	}
+	if otp.CreatedAt.IsZero() {
+		otp.CreatedAt = time.Now()
+	}
+
+	key := redisOTPKey(otp.Email, otp.Purpose)
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key,
+			"id", id,
+			"email", otp.Email,
+			"purpose", otp.Purpose,
+			"otp_hash", otp.OTPHash,
+			"expired_at", otp.ExpiredAt.UnixNano(),
+			"attempts", otp.Attempts,
+			"ip_address", otp.IPAddress,
+			"user_agent", otp.UserAgent,
+			"created_at", otp.CreatedAt.UnixNano(),
+		)
+		pipe.PExpireAt(ctx, key, otp.ExpiredAt)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	otp.ID = id
+	return nil
+}
+
+func (r *RedisOTPRepository) FindActiveByEmail(
+	ctx context.Context,
+	email, purpose string,
+) (*domain.EmailOTP, error) {
+
+	fields, err := r.client.HGetAll(ctx, redisOTPKey(email, purpose)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	otp := &domain.EmailOTP{
+		Email:     fields["email"],
+		Purpose:   fields["purpose"],
+		OTPHash:   fields["otp_hash"],
+		IPAddress: fields["ip_address"],
+		UserAgent: fields["user_agent"],
+	}
+	otp.ID, _ = strconv.ParseUint(fields["id"], 10, 64)
+	otp.Attempts, _ = strconv.Atoi(fields["attempts"])
+	otp.ExpiredAt = unixNano(fields["expired_at"])
+	otp.CreatedAt = unixNano(fields["created_at"])
+
+	// key TTL berpresisi ms; jaga-jaga untuk sisa sub-ms
+	if otp.IsExpired(time.Now()) {
+		return nil, nil
+	}
+
+	return otp, nil
+}
+
+func (r *RedisOTPRepository) IncrementAttempts(
+	ctx context.Context,
+	email, purpose string,
+) (int, error) {
+
+	attempts, err := incrementOTPAttemptsScript.Run(
+		ctx, r.client,
+		[]string{redisOTPKey(email, purpose)},
+	).Int()
+	if err != nil {
+		return 0, err
+	}
+
+	return attempts, nil
+}
+
+func (r *RedisOTPRepository) DeleteByEmail(ctx context.Context, email, purpose string) error {
+	return r.client.Del(ctx, redisOTPKey(email, purpose)).Err()
+}
+
+func redisOTPKey(email, purpose string) string {
+	return redisOTPKeyPrefix + purpose + ":" + email
+}
+
+func unixNano(value string) time.Time {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
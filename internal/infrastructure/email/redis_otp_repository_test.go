@@ -0,0 +1,36 @@
+package email
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/email"
+	"github.com/dhanarrizky/Golang-template/internal/ports/email/otptest"
+	"github.com/redis/go-redis/v9"
+)
+
+// TestRedisOTPRepository runs against a real Redis, e.g.
+//
+//	OTP_TEST_REDIS_URL=redis://localhost:6379/15 go test ./internal/infrastructure/email/
+func TestRedisOTPRepository(t *testing.T) {
+	url := os.Getenv("OTP_TEST_REDIS_URL")
+	if url == "" {
+		t.Skip("OTP_TEST_REDIS_URL not set")
+	}
+
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		t.Fatalf("parse OTP_TEST_REDIS_URL: %v", err)
+	}
+	client := redis.NewClient(opts)
+	t.Cleanup(func() { _ = client.Close() })
+
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Fatalf("redis ping: %v", err)
+	}
+
+	otptest.RunConformance(t, func(t *testing.T) ports.EmailOTPRepository {
+		return NewRedisOTPRepository(client)
+	})
+}
@@ -0,0 +1,158 @@
+// package email
+
+// import (
+// 	"net/smtp"
+
+// 	ports "github.com/dhanarrizky/Golang-template/internal/ports/email"
+// )
+
+// type SMTPEmailService struct {
+// 	host string
+// 	port string
+// 	from string
+// 	auth smtp.Auth
+// }
+
+// func NewSMTPEmailService(host, port, from, pw string) ports.EmailSender {
+// 	return &SMTPEmailService{
+// 		host: host,
+// 		port: port,
+// 		from: from,
+// 		auth: smtp.PlainAuth("", from, pw, host),
+// 	}
+// }
+
+// func (s *SMTPEmailService) SendOTP(toEmail, otp string) error {
+// 	// render template di sini
+// 	return smtp.SendMail(
+// 		s.host+":"+s.port,
+// 		s.auth,
+// 		s.from,
+// 		[]string{toEmail},
+// 		[]byte("Subject: OTP\r\n\r\nYour OTP: "+otp),
+// 	)
+// }
+
+package email
+
+import (
+	"context"
+	"time"
+
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/email"
+	mailer "github.com/dhanarrizky/Golang-template/pkg/email"
+)
+
+// Template names, see pkg/template_html/<locale>/<name>.html
+const (
+	templateOTP                     = "otp"
+	templateResetPassword           = "reset_password"
+	templatePasswordChanged         = "password_changed"
+	templatePasswordExpiry          = "password_expiry"
+	templateEmailVerification       = "email_verification"
+	templateLoginAlert              = "login_alert"
+	templateAccountLocked           = "account_locked"
+	templateEmailChangeVerification = "email_change_verification"
+	templateEmailChangeNotice       = "email_change_notice"
+	templateInvitation              = "invitation"
+)
+
+// sendTimeout bounds a single delivery; EmailSender methods carry no context
+const sendTimeout = 30 * time.Second
+
+// Sender implements ports.EmailSender: it renders the template for each
+// message type and hands the result to a Transport (SMTP, file, memory, HTTP).
+// Templates are rendered in the recipient's locale; locale is the default for
+// recipients without one.
+type Sender struct {
+	transport Transport
+	from      string
+
+	renderer         *mailer.Renderer
+	locale           string
+	otpExpiryMinutes int
+}
+
+func NewSender(
+	transport Transport,
+	from string,
+	renderer *mailer.Renderer,
+	locale string,
+	otpExpiryMinutes int,
+) *Sender {
+	return &Sender{
+		transport:        transport,
+		from:             from,
+		renderer:         renderer,
+		locale:           locale,
+		otpExpiryMinutes: otpExpiryMinutes,
+	}
+}
+
+type otpTemplateData struct {
+	Code          string
+	ExpiryMinutes int
+}
+
+func (s *Sender) SendOTP(to ports.Recipient, otp string) error {
+	return s.send(to, templateOTP, otpTemplateData{
+		Code:          otp,
+		ExpiryMinutes: s.otpExpiryMinutes,
+	})
+}
+
+func (s *Sender) SendResetPassword(to ports.Recipient, notice ports.PasswordReset) error {
+	return s.send(to, templateResetPassword, notice)
+}
+
+func (s *Sender) SendPasswordChanged(to ports.Recipient, notice ports.PasswordChangedNotice) error {
+	return s.send(to, templatePasswordChanged, notice)
+}
+
+func (s *Sender) SendPasswordExpiry(to ports.Recipient, notice ports.PasswordExpiryNotice) error {
+	return s.send(to, templatePasswordExpiry, notice)
+}
+
+func (s *Sender) SendLoginAlert(to ports.Recipient, alert ports.LoginAlert) error {
+	return s.send(to, templateLoginAlert, alert)
+}
+
+func (s *Sender) SendAccountLocked(to ports.Recipient, notice ports.AccountLockedNotice) error {
+	return s.send(to, templateAccountLocked, notice)
+}
+
+func (s *Sender) SendEmailChangeVerification(to ports.Recipient, notice ports.EmailChangeVerification) error {
+	return s.send(to, templateEmailChangeVerification, notice)
+}
+
+func (s *Sender) SendEmailChangeNotice(to ports.Recipient, notice ports.EmailChangeNotice) error {
+	return s.send(to, templateEmailChangeNotice, notice)
+}
+
+func (s *Sender) SendEmailVerification(to ports.Recipient, notice ports.EmailVerification) error {
+	return s.send(to, templateEmailVerification, notice)
+}
+
+func (s *Sender) SendInvitation(to ports.Recipient, invitation ports.Invitation) error {
+	return s.send(to, templateInvitation, invitation)
+}
+
+// send renders the template in the recipient's locale (the renderer falls
+// back to the default when there is no such template) and delivers it
+// through the transport
+func (s *Sender) send(to ports.Recipient, template string, data any) error {
+	locale := to.Locale
+	if locale == "" {
+		locale = s.locale
+	}
+
+	msg, err := s.renderer.Render(template, locale, data)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+
+	return s.transport.Send(ctx, Envelope{From: s.from, To: []string{to.Email}}, msg)
+}
@@ -0,0 +1,210 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+
+	mailer "github.com/dhanarrizky/Golang-template/pkg/email"
+)
+
+// SMTP connection security
+const (
+	SMTPSecurityStartTLS = "starttls" // plain connect, upgrade with STARTTLS (port 587), required
+	SMTPSecurityTLS      = "tls"      // implicit TLS from the first byte (port 465)
+	SMTPSecurityNone     = "none"     // plaintext, only for local relays / mail catchers
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	Security string
+
+	// Timeout applies to dialing and to each message (commands + DATA)
+	Timeout time.Duration
+	// PoolSize is the number of idle connections kept for reuse; 0 = no reuse
+	PoolSize int
+}
+
+// SMTPTransport keeps up to PoolSize authenticated connections open and
+// reuses them between messages (RSET before each one). Broken connections
+// are dropped and redialed on the next send.
+type SMTPTransport struct {
+	cfg  SMTPConfig
+	idle chan *smtpConn
+}
+
+type smtpConn struct {
+	conn   net.Conn
+	client *smtp.Client
+}
+
+func NewSMTPTransport(cfg SMTPConfig) (*SMTPTransport, error) {
+	switch cfg.Security {
+	case SMTPSecurityStartTLS, SMTPSecurityTLS, SMTPSecurityNone:
+	default:
+		return nil, fmt.Errorf("unknown SMTP security mode %q", cfg.Security)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	return &SMTPTransport{
+		cfg:  cfg,
+		idle: make(chan *smtpConn, max(cfg.PoolSize, 0)),
+	}, nil
+}
+
+func (t *SMTPTransport) Send(ctx context.Context, env Envelope, msg *mailer.Message) error {
+	raw, err := mailer.BuildMIME(env.From, env.To, msg)
+	if err != nil {
+		return err
+	}
+
+	c, err := t.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(t.cfg.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = c.conn.SetDeadline(deadline)
+
+	if err := t.deliver(c.client, env, raw); err != nil {
+		c.close()
+		return err
+	}
+
+	t.release(c)
+	return nil
+}
+
+// Close drops all pooled connections
+func (t *SMTPTransport) Close() error {
+	for {
+		select {
+		case c := <-t.idle:
+			_ = c.client.Quit()
+			c.close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (t *SMTPTransport) deliver(client *smtp.Client, env Envelope, raw []byte) error {
+	if err := client.Mail(envelopeAddress(env.From)); err != nil {
+		return err
+	}
+	for _, to := range env.To {
+		if err := client.Rcpt(envelopeAddress(to)); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(raw); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func (t *SMTPTransport) acquire(ctx context.Context) (*smtpConn, error) {
+	for {
+		select {
+		case c := <-t.idle:
+			_ = c.conn.SetDeadline(time.Now().Add(t.cfg.Timeout))
+			// the server may have closed an idle connection
+			if err := c.client.Reset(); err != nil {
+				c.close()
+				continue
+			}
+			return c, nil
+		default:
+			return t.dial(ctx)
+		}
+	}
+}
+
+func (t *SMTPTransport) release(c *smtpConn) {
+	select {
+	case t.idle <- c:
+	default:
+		_ = c.client.Quit()
+		c.close()
+	}
+}
+
+func (t *SMTPTransport) dial(ctx context.Context) (*smtpConn, error) {
+	addr := net.JoinHostPort(t.cfg.Host, t.cfg.Port)
+	dialer := &net.Dialer{Timeout: t.cfg.Timeout}
+	tlsConfig := &tls.Config{ServerName: t.cfg.Host, MinVersion: tls.VersionTLS12}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+	if t.cfg.Security == SMTPSecurityTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(t.cfg.Timeout))
+
+	client, err := smtp.NewClient(conn, t.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	c := &smtpConn{conn: conn, client: client}
+
+	if t.cfg.Security == SMTPSecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			c.close()
+			return nil, errors.New("smtp server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			c.close()
+			return nil, err
+		}
+	}
+
+	if t.cfg.Username != "" {
+		// PlainAuth refuses to send credentials over a non-TLS connection (except localhost)
+		auth := smtp.PlainAuth("", t.cfg.Username, t.cfg.Password, t.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			c.close()
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+func (c *smtpConn) close() {
+	_ = c.client.Close()
+}
+
+// envelopeAddress strips the display name: "App <no-reply@x.io>" → "no-reply@x.io"
+func envelopeAddress(addr string) string {
+	if a, err := mail.ParseAddress(addr); err == nil {
+		return a.Address
+	}
+	return addr
+}
@@ -0,0 +1,20 @@
+package email
+
+import (
+	"context"
+
+	mailer "github.com/dhanarrizky/Golang-template/pkg/email"
+)
+
+// Envelope is the SMTP-level sender and recipients of a message
+type Envelope struct {
+	From string
+	To   []string
+}
+
+// Transport delivers a rendered message. Implementations: SMTPTransport,
+// FileTransport, MemoryTransport and HTTPTransport; DKIMTransport and
+// CaptureTransport wrap another Transport.
+type Transport interface {
+	Send(ctx context.Context, env Envelope, msg *mailer.Message) error
+}
@@ -0,0 +1,130 @@
+package geoip
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+)
+
+// ipRange is one row of the offline GeoIP database.
+type ipRange struct {
+	start    netip.Addr
+	end      netip.Addr
+	location valueobjects.GeoLocation
+}
+
+// csvResolver resolves IPs against an in-memory copy of a CSV range database.
+// Rows are: start_ip,end_ip,country_code,city,latitude,longitude
+// (the free DB-IP / IP2Location "lite" exports can be converted to this shape).
+type csvResolver struct {
+	ranges []ipRange
+}
+
+func NewCSVResolver(path string) (ports.GeoIPResolver, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return NewCSVResolverFromReader(f)
+}
+
+func NewCSVResolverFromReader(r io.Reader) (ports.GeoIPResolver, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 6
+
+	var ranges []ipRange
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		row, err := parseRow(record)
+		if err != nil {
+			return nil, fmt.Errorf("geoip: line %d: %w", line, err)
+		}
+		ranges = append(ranges, row)
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].start.Less(ranges[j].start)
+	})
+
+	return &csvResolver{ranges: ranges}, nil
+}
+
+func (r *csvResolver) Lookup(ip string) (*valueobjects.GeoLocation, error) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return nil, err
+	}
+	addr = addr.Unmap()
+
+	// first range whose start is greater than addr, the candidate is the one before it
+	i := sort.Search(len(r.ranges), func(i int) bool {
+		return addr.Less(r.ranges[i].start)
+	})
+	if i == 0 {
+		return nil, nil
+	}
+
+	candidate := r.ranges[i-1]
+	if addr.BitLen() != candidate.start.BitLen() || candidate.end.Less(addr) {
+		return nil, nil
+	}
+
+	loc := candidate.location
+	return &loc, nil
+}
+
+func parseRow(record []string) (ipRange, error) {
+	start, err := netip.ParseAddr(strings.TrimSpace(record[0]))
+	if err != nil {
+		return ipRange{}, err
+	}
+
+	end, err := netip.ParseAddr(strings.TrimSpace(record[1]))
+	if err != nil {
+		return ipRange{}, err
+	}
+
+	if start.BitLen() != end.BitLen() || end.Less(start) {
+		return ipRange{}, errors.New("invalid ip range")
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(record[4]), 64)
+	if err != nil {
+		return ipRange{}, err
+	}
+
+	lon, err := strconv.ParseFloat(strings.TrimSpace(record[5]), 64)
+	if err != nil {
+		return ipRange{}, err
+	}
+
+	return ipRange{
+		start: start.Unmap(),
+		end:   end.Unmap(),
+		location: valueobjects.GeoLocation{
+			Country:   strings.ToUpper(strings.TrimSpace(record[2])),
+			City:      strings.TrimSpace(record[3]),
+			Latitude:  lat,
+			Longitude: lon,
+		},
+	}, nil
+}
@@ -0,0 +1,79 @@
+package messaging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+)
+
+// HTTPSMSGateway posts {"to","from","message"} as JSON with a bearer token.
+// Most SMS aggregators (and small in-house relays) accept this shape directly
+// or through a thin proxy; point SMS_HTTP_ENDPOINT at a local stub in tests.
+type HTTPSMSGateway struct {
+	client   *http.Client
+	endpoint string
+	apiKey   string
+	senderID string
+}
+
+func NewHTTPSMSGateway(endpoint, apiKey, senderID string, timeout time.Duration) ports.MessageChannel {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &HTTPSMSGateway{
+		client:   &http.Client{Timeout: timeout},
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		senderID: senderID,
+	}
+}
+
+func (g *HTTPSMSGateway) Send(ctx context.Context, to, text string) error {
+	body := map[string]string{
+		"to":      to,
+		"message": text,
+	}
+	if g.senderID != "" {
+		body["from"] = g.senderID
+	}
+
+	return postJSON(ctx, g.client, g.endpoint, g.apiKey, body)
+}
+
+func postJSON(ctx context.Context, client *http.Client, endpoint, apiKey string, body any) error {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("message gateway returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,147 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+)
+
+func TestMessageChannels_Send(t *testing.T) {
+	cases := []struct {
+		name     string
+		channel  func(endpoint string) ports.MessageChannel
+		wantAuth string // empty means no Authorization header
+		wantBody map[string]any
+	}{
+		{
+			name: "sms gateway",
+			channel: func(endpoint string) ports.MessageChannel {
+				return NewHTTPSMSGateway(endpoint, "sms-key", "MyApp", time.Second)
+			},
+			wantAuth: "Bearer sms-key",
+			wantBody: map[string]any{"to": "+6281234567890", "from": "MyApp", "message": "Your code is 123456"},
+		},
+		{
+			name: "sms gateway without api key and sender id",
+			channel: func(endpoint string) ports.MessageChannel {
+				return NewHTTPSMSGateway(endpoint, "", "", time.Second)
+			},
+			wantBody: map[string]any{"to": "+6281234567890", "message": "Your code is 123456"},
+		},
+		{
+			name: "whatsapp cloud",
+			channel: func(endpoint string) ports.MessageChannel {
+				return NewWhatsAppCloudChannel(endpoint, "wa-token", time.Second)
+			},
+			wantAuth: "Bearer wa-token",
+			// the Cloud API takes digits only
+			wantBody: map[string]any{
+				"messaging_product": "whatsapp",
+				"to":                "6281234567890",
+				"type":              "text",
+				"text":              map[string]any{"body": "Your code is 123456"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				method string
+				header http.Header
+				body   map[string]any
+			)
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				method = r.Method
+				header = r.Header.Clone()
+
+				raw, _ := io.ReadAll(r.Body)
+				if err := json.Unmarshal(raw, &body); err != nil {
+					t.Errorf("body is not JSON: %v (%s)", err, raw)
+				}
+			}))
+			defer srv.Close()
+
+			if err := tc.channel(srv.URL).Send(context.Background(), "+6281234567890", "Your code is 123456"); err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+
+			if method != http.MethodPost {
+				t.Errorf("method = %s, want POST", method)
+			}
+			if got := header.Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q", got)
+			}
+			if got := header.Get("Authorization"); got != tc.wantAuth {
+				t.Errorf("Authorization = %q, want %q", got, tc.wantAuth)
+			}
+			if !reflect.DeepEqual(body, tc.wantBody) {
+				t.Errorf("body = %v, want %v", body, tc.wantBody)
+			}
+		})
+	}
+}
+
+func TestHTTPSMSGateway_Errors(t *testing.T) {
+	cases := map[string]struct {
+		handler  http.HandlerFunc
+		canceled bool
+		wantErr  string // empty means any error will do
+	}{
+		"rejected": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte("  invalid number\n"))
+			},
+			wantErr: "message gateway returned 400: invalid number",
+		},
+		"long error body is truncated": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+				_, _ = w.Write([]byte(strings.Repeat("x", 4096)))
+			},
+			wantErr: "message gateway returned 502: " + strings.Repeat("x", 1024),
+		},
+		"timeout": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(200 * time.Millisecond)
+			},
+		},
+		"canceled": {
+			handler:  func(w http.ResponseWriter, r *http.Request) {},
+			canceled: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.handler)
+			defer srv.Close()
+
+			gateway := NewHTTPSMSGateway(srv.URL, "sms-key", "", 50*time.Millisecond)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.canceled {
+				cancel()
+			}
+
+			err := gateway.Send(ctx, "+6281234567890", "hi")
+			if err == nil {
+				t.Fatal("Send returned no error")
+			}
+			if tc.wantErr != "" && err.Error() != tc.wantErr {
+				t.Errorf("error = %q, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package messaging
+
+import (
+	"context"
+	"log"
+
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+)
+
+// LogChannel writes messages to the application log instead of sending them.
+// Development only: the log then contains OTP codes.
+type LogChannel struct {
+	name string
+}
+
+func NewLogChannel(name string) ports.MessageChannel {
+	return &LogChannel{name: name}
+}
+
+func (c *LogChannel) Send(_ context.Context, to, text string) error {
+	log.Printf("[%s] to=%s: %s", c.name, to, text)
+	return nil
+}
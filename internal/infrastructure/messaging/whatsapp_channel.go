@@ -0,0 +1,43 @@
+package messaging
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+)
+
+// WhatsAppCloudChannel sends text messages through the WhatsApp Cloud API
+// (https://graph.facebook.com/<version>/<phone-number-id>/messages). BSPs that
+// mirror the Cloud API payload work with their own endpoint.
+//
+// Note: outside a 24h customer service window WhatsApp only delivers approved
+// templates; use an authentication template behind a proxy if that applies.
+type WhatsAppCloudChannel struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+func NewWhatsAppCloudChannel(endpoint, token string, timeout time.Duration) ports.MessageChannel {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &WhatsAppCloudChannel{
+		client:   &http.Client{Timeout: timeout},
+		endpoint: endpoint,
+		token:    token,
+	}
+}
+
+func (c *WhatsAppCloudChannel) Send(ctx context.Context, to, text string) error {
+	return postJSON(ctx, c.client, c.endpoint, c.token, map[string]any{
+		"messaging_product": "whatsapp",
+		"to":                strings.TrimPrefix(to, "+"), // Cloud API expects digits only
+		"type":              "text",
+		"text":              map[string]string{"body": text},
+	})
+}
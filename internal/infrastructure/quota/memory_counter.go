@@ -0,0 +1,93 @@
+package quota
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+)
+
+type memoryCounterEntry struct {
+	value     int64
+	expiresAt time.Time
+}
+
+// MemoryCounter keeps quota counters in process memory. Counts are lost on
+// restart and not shared between replicas, so it's meant for development and
+// single-instance deployments; the Postgres flush still seeds new keys.
+type MemoryCounter struct {
+	mu      sync.Mutex
+	entries map[string]*memoryCounterEntry
+	dirty   map[string]struct{}
+}
+
+func NewMemoryCounter() ports.QuotaCounter {
+	return &MemoryCounter{
+		entries: make(map[string]*memoryCounterEntry),
+		dirty:   make(map[string]struct{}),
+	}
+}
+
+func (c *MemoryCounter) Increment(
+	ctx context.Context,
+	key string,
+	delta int64,
+	ttl time.Duration,
+) (int64, bool, error) {
+
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	created := !ok || now.After(e.expiresAt)
+	if created {
+		e = &memoryCounterEntry{expiresAt: now.Add(ttl)}
+		c.entries[key] = e
+	}
+
+	e.value += delta
+	c.dirty[key] = struct{}{}
+
+	return e.value, created, nil
+}
+
+func (c *MemoryCounter) Get(ctx context.Context, key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || time.Now().After(e.expiresAt) {
+		return 0, nil
+	}
+	return e.value, nil
+}
+
+func (c *MemoryCounter) PopDirty(ctx context.Context, max int) ([]string, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]string, 0, max)
+	for k := range c.dirty {
+		if len(keys) >= max {
+			break
+		}
+		keys = append(keys, k)
+		delete(c.dirty, k)
+	}
+
+	// flush juga jadi kesempatan membuang key periode yang sudah lewat
+	for k, e := range c.entries {
+		if now.After(e.expiresAt) {
+			if _, pending := c.dirty[k]; !pending {
+				delete(c.entries, k)
+			}
+		}
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,80 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+	"github.com/redis/go-redis/v9"
+)
+
+// dirtySetKey tracks counters changed since the last Postgres flush
+const dirtySetKey = "quota:dirty"
+
+// KEYS[1] = counter, KEYS[2] = dirty set
+// ARGV[1] = delta, ARGV[2] = ttl ms
+// returns {value, created}
+var incrementScript = redis.NewScript(`
+local created = 0
+if redis.call('EXISTS', KEYS[1]) == 0 then
+  created = 1
+end
+
+local value = redis.call('INCRBY', KEYS[1], ARGV[1])
+if created == 1 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+
+redis.call('SADD', KEYS[2], KEYS[1])
+
+return {value, created}
+`)
+
+// RedisCounter shares quota counters across replicas. Counters expire a bit
+// after their period ends; the flush job persists them to Postgres before that.
+type RedisCounter struct {
+	client *redis.Client
+}
+
+func NewRedisCounter(client *redis.Client) ports.QuotaCounter {
+	return &RedisCounter{client: client}
+}
+
+func (c *RedisCounter) Increment(
+	ctx context.Context,
+	key string,
+	delta int64,
+	ttl time.Duration,
+) (int64, bool, error) {
+
+	values, err := incrementScript.Run(
+		ctx, c.client,
+		[]string{key, dirtySetKey},
+		delta, ttl.Milliseconds(),
+	).Int64Slice()
+	if err != nil {
+		return 0, false, err
+	}
+	if len(values) != 2 {
+		return 0, false, fmt.Errorf("quota: unexpected script reply %v", values)
+	}
+
+	return values[0], values[1] == 1, nil
+}
+
+func (c *RedisCounter) Get(ctx context.Context, key string) (int64, error) {
+	value, err := c.client.Get(ctx, key).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return value, err
+}
+
+func (c *RedisCounter) PopDirty(ctx context.Context, max int) ([]string, error) {
+	keys, err := c.client.SPopN(ctx, dirtySetKey, int64(max)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	return keys, err
+}
@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+)
+
+// State and math for the in-memory store. The Redis Lua scripts implement
+// exactly the same formulas so both stores behave identically.
+
+// stateTTL is how long a key's state must outlive its last request: two
+// windows for the sliding window (it reads the previous one), and the time a
+// drained bucket / GCRA burst needs to refill completely. Dropping state
+// earlier would hand the client a fresh burst.
+func stateTTL(p valueobjects.RateLimitPolicy) time.Duration {
+	refill := time.Duration(float64(p.Window) * float64(p.BurstSize()) / float64(p.Limit))
+	return max(2*p.Window, refill)
+}
+
+type tokenBucketState struct {
+	tokens float64
+	last   time.Time
+}
+
+func takeTokenBucket(s *tokenBucketState, now time.Time, p valueobjects.RateLimitPolicy) *valueobjects.RateLimitResult {
+	capacity := float64(p.BurstSize())
+	rate := float64(p.Limit) / float64(p.Window) // token per nanosecond
+
+	if s.last.IsZero() {
+		s.tokens = capacity
+	} else {
+		s.tokens = math.Min(capacity, s.tokens+float64(now.Sub(s.last))*rate)
+	}
+	s.last = now
+
+	res := &valueobjects.RateLimitResult{Limit: p.Limit}
+	if s.tokens >= 1 {
+		s.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration(math.Ceil((1 - s.tokens) / rate))
+	}
+
+	res.Remaining = int(math.Floor(s.tokens))
+	res.ResetAfter = time.Duration(math.Ceil((capacity - s.tokens) / rate))
+	return res
+}
+
+type slidingWindowState struct {
+	start time.Time
+	curr  int
+	prev  int
+}
+
+func takeSlidingWindow(s *slidingWindowState, now time.Time, p valueobjects.RateLimitPolicy) *valueobjects.RateLimitResult {
+	window := p.Window
+
+	if s.start.IsZero() {
+		s.start = now
+	}
+	elapsed := now.Sub(s.start)
+	switch {
+	case elapsed >= 2*window:
+		s.prev, s.curr = 0, 0
+		s.start = now.Add(-(elapsed % window))
+	case elapsed >= window:
+		s.prev, s.curr = s.curr, 0
+		s.start = s.start.Add(window)
+	}
+	elapsed = now.Sub(s.start)
+
+	weight := float64(window-elapsed) / float64(window)
+	count := float64(s.prev)*weight + float64(s.curr)
+
+	res := &valueobjects.RateLimitResult{
+		Limit:      p.Limit,
+		ResetAfter: window - elapsed,
+	}
+
+	if count+1 <= float64(p.Limit) {
+		s.curr++
+		res.Allowed = true
+		res.Remaining = int(math.Floor(float64(p.Limit) - count - 1))
+		return res
+	}
+
+	// kapan bobot window sebelumnya cukup turun untuk satu request lagi
+	if s.curr+1 > p.Limit || s.prev == 0 {
+		res.RetryAfter = window - elapsed
+	} else {
+		at := float64(window) * (1 - float64(p.Limit-s.curr-1)/float64(s.prev))
+		res.RetryAfter = time.Duration(math.Ceil(at)) - elapsed
+	}
+	if res.RetryAfter < 0 {
+		res.RetryAfter = 0
+	}
+	return res
+}
+
+type gcraState struct {
+	tat time.Time // theoretical arrival time
+}
+
+func takeGCRA(s *gcraState, now time.Time, p valueobjects.RateLimitPolicy) *valueobjects.RateLimitResult {
+	interval := p.Window / time.Duration(p.Limit)
+	tolerance := interval * time.Duration(p.BurstSize())
+
+	tat := s.tat
+	if tat.Before(now) {
+		tat = now
+	}
+	newTat := tat.Add(interval)
+	allowAt := newTat.Add(-tolerance)
+
+	res := &valueobjects.RateLimitResult{Limit: p.Limit}
+	if now.Before(allowAt) {
+		res.RetryAfter = allowAt.Sub(now)
+		res.ResetAfter = tat.Sub(now)
+		return res
+	}
+
+	s.tat = newTat
+	res.Allowed = true
+	res.Remaining = int((tolerance - newTat.Sub(now)) / interval)
+	res.ResetAfter = newTat.Sub(now)
+	return res
+}
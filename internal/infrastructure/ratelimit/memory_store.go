@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+)
+
+const memorySweepInterval = time.Minute
+
+type memoryEntry struct {
+	tokenBucket   tokenBucketState
+	slidingWindow slidingWindowState
+	gcra          gcraState
+	expiresAt     time.Time
+}
+
+// MemoryStore is a process-local limiter for single-instance deployments and
+// development. Idle keys are swept periodically so the map doesn't leak.
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	lastSweep time.Time
+}
+
+func NewMemoryStore() ports.RateLimiter {
+	return &MemoryStore{
+		entries: make(map[string]*memoryEntry),
+	}
+}
+
+func (s *MemoryStore) Allow(
+	ctx context.Context,
+	key string,
+	policy valueobjects.RateLimitPolicy,
+) (*valueobjects.RateLimitResult, error) {
+
+	now := time.Now()
+	k := storageKey(policy, key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	e, ok := s.entries[k]
+	if !ok {
+		e = &memoryEntry{}
+		s.entries[k] = e
+	}
+	e.expiresAt = now.Add(stateTTL(policy))
+
+	switch policy.Algorithm {
+	case valueobjects.RateLimitTokenBucket:
+		return takeTokenBucket(&e.tokenBucket, now, policy), nil
+	case valueobjects.RateLimitSlidingWindow:
+		return takeSlidingWindow(&e.slidingWindow, now, policy), nil
+	case valueobjects.RateLimitGCRA:
+		return takeGCRA(&e.gcra, now, policy), nil
+	default:
+		return nil, fmt.Errorf("ratelimit: unknown algorithm %q", policy.Algorithm)
+	}
+}
+
+// sweep removes expired keys; caller must hold mu
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for k, e := range s.entries {
+		if now.After(e.expiresAt) {
+			delete(s.entries, k)
+		}
+	}
+}
+
+func storageKey(policy valueobjects.RateLimitPolicy, key string) string {
+	return "ratelimit:" + policy.Name + ":" + string(policy.Algorithm) + ":" + key
+}
@@ -0,0 +1,156 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+	"github.com/redis/go-redis/v9"
+)
+
+// Every script reads the clock from Redis (TIME) so all replicas share one
+// clock, and returns {allowed, remaining, retry_after_ms, reset_after_ms}.
+
+// KEYS[1] = bucket, ARGV = capacity, refill rate (token/ms), ttl ms
+var tokenBucketScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+
+local s = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(s[1])
+local ts = tonumber(s[2])
+if tokens == nil then
+  tokens = capacity
+else
+  tokens = math.min(capacity, tokens + (now - ts) * rate)
+end
+
+local allowed, retry = 0, 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  retry = math.ceil((1 - tokens) / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+
+return {allowed, math.floor(tokens), retry, math.ceil((capacity - tokens) / rate)}
+`)
+
+// KEYS[1] = counter hash, ARGV = limit, window ms
+var slidingWindowScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+
+local s = redis.call('HMGET', KEYS[1], 'start', 'curr', 'prev')
+local start = tonumber(s[1]) or now
+local curr = tonumber(s[2]) or 0
+local prev = tonumber(s[3]) or 0
+
+local elapsed = now - start
+if elapsed >= 2 * window then
+  prev, curr = 0, 0
+  start = now - (elapsed % window)
+elseif elapsed >= window then
+  prev, curr = curr, 0
+  start = start + window
+end
+elapsed = now - start
+
+local count = prev * ((window - elapsed) / window) + curr
+local allowed, remaining, retry = 0, 0, 0
+if count + 1 <= limit then
+  curr = curr + 1
+  allowed = 1
+  remaining = math.floor(limit - count - 1)
+elseif curr + 1 > limit or prev == 0 then
+  retry = window - elapsed
+else
+  retry = math.max(0, math.ceil(window * (1 - (limit - curr - 1) / prev)) - elapsed)
+end
+
+redis.call('HSET', KEYS[1], 'start', start, 'curr', curr, 'prev', prev)
+redis.call('PEXPIRE', KEYS[1], 2 * window)
+
+return {allowed, remaining, retry, window - elapsed}
+`)
+
+// KEYS[1] = TAT, ARGV = emission interval ms, burst
+var gcraScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local interval = tonumber(ARGV[1])
+local tolerance = interval * tonumber(ARGV[2])
+
+local tat = tonumber(redis.call('GET', KEYS[1])) or now
+if tat < now then
+  tat = now
+end
+local newTat = tat + interval
+local allowAt = newTat - tolerance
+
+if now < allowAt then
+  return {0, 0, math.ceil(allowAt - now), math.ceil(tat - now)}
+end
+
+redis.call('SET', KEYS[1], tostring(newTat), 'PX', math.ceil(newTat - now))
+
+return {1, math.floor((tolerance - (newTat - now)) / interval), 0, math.ceil(newTat - now)}
+`)
+
+// RedisStore shares limits across replicas; each decision is a single atomic script call.
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) ports.RateLimiter {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) Allow(
+	ctx context.Context,
+	key string,
+	policy valueobjects.RateLimitPolicy,
+) (*valueobjects.RateLimitResult, error) {
+
+	keys := []string{storageKey(policy, key)}
+	windowMs := float64(policy.Window.Milliseconds())
+
+	var cmd *redis.Cmd
+	switch policy.Algorithm {
+	case valueobjects.RateLimitTokenBucket:
+		rate := float64(policy.Limit) / windowMs
+		cmd = tokenBucketScript.Run(ctx, s.client, keys, policy.BurstSize(), rate, stateTTL(policy).Milliseconds())
+	case valueobjects.RateLimitSlidingWindow:
+		cmd = slidingWindowScript.Run(ctx, s.client, keys, policy.Limit, int64(windowMs))
+	case valueobjects.RateLimitGCRA:
+		interval := windowMs / float64(policy.Limit)
+		cmd = gcraScript.Run(ctx, s.client, keys, interval, policy.BurstSize())
+	default:
+		return nil, fmt.Errorf("ratelimit: unknown algorithm %q", policy.Algorithm)
+	}
+
+	values, err := cmd.Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 4 {
+		return nil, fmt.Errorf("ratelimit: unexpected script reply %v", values)
+	}
+
+	return &valueobjects.RateLimitResult{
+		Allowed:    values[0] == 1,
+		Limit:      policy.Limit,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		ResetAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
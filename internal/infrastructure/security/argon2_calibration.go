@@ -0,0 +1,228 @@
+package security
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	utils "github.com/dhanarrizky/Golang-template/pkg/utils"
+	"golang.org/x/crypto/argon2"
+)
+
+// Argon2Params are the cost parameters of an Argon2id hash.
+type Argon2Params struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+}
+
+func (p Argon2Params) String() string {
+	return fmt.Sprintf("m=%d (%d MiB), t=%d, p=%d", p.Memory, p.Memory/1024, p.Iterations, p.Parallelism)
+}
+
+// MemoryBytes is the memory a single hash allocates.
+func (p Argon2Params) MemoryBytes() uint64 {
+	return uint64(p.Memory) * 1024
+}
+
+// OWASPArgon2Minimums are the equivalent minimum Argon2id configurations from
+// the OWASP Password Storage Cheat Sheet (p=1): more memory allows fewer
+// iterations.
+var OWASPArgon2Minimums = []Argon2Params{
+	{Memory: 46 * 1024, Iterations: 1, Parallelism: 1},
+	{Memory: 19 * 1024, Iterations: 2, Parallelism: 1},
+	{Memory: 12 * 1024, Iterations: 3, Parallelism: 1},
+	{Memory: 9 * 1024, Iterations: 4, Parallelism: 1},
+	{Memory: 7 * 1024, Iterations: 5, Parallelism: 1},
+}
+
+// MeetsOWASPMinimum reports whether p is at least as strong as one of the
+// OWASP minimum configurations.
+func MeetsOWASPMinimum(p Argon2Params) bool {
+	return p.Parallelism >= 1 && minIterationsFor(p.Memory) <= p.Iterations
+}
+
+// minIterationsFor returns the iterations OWASP requires at the given memory,
+// or 0 when the memory is below every minimum.
+func minIterationsFor(memory uint32) uint32 {
+	for _, m := range OWASPArgon2Minimums {
+		if memory >= m.Memory {
+			return m.Iterations
+		}
+	}
+	return 0
+}
+
+// CheckArgon2Params returns human readable warnings for parameters below the
+// OWASP minimums, or whose peak memory (concurrency hashes at once) exceeds
+// memoryLimit bytes. A memoryLimit of 0 skips the memory check.
+func CheckArgon2Params(p Argon2Params, memoryLimit uint64, concurrency int) []string {
+	var warnings []string
+
+	if !MeetsOWASPMinimum(p) {
+		warnings = append(warnings, fmt.Sprintf(
+			"argon2id parameters %s are below the OWASP minimum (e.g. m=19 MiB, t=2, p=1)", p,
+		))
+	}
+
+	if memoryLimit > 0 && concurrency > 0 {
+		peak := p.MemoryBytes() * uint64(concurrency)
+		if peak > memoryLimit {
+			warnings = append(warnings, fmt.Sprintf(
+				"%d concurrent argon2id hashes need %d MiB, more than the %d MiB memory limit",
+				concurrency, peak>>20, memoryLimit>>20,
+			))
+		}
+	}
+
+	return warnings
+}
+
+// unlimitedMemory: cgroup v1 reports "no limit" as a number close to MaxInt64
+const unlimitedMemory = 1 << 60
+
+// ContainerMemoryLimit returns the cgroup (v2, then v1) memory limit in bytes,
+// or 0 when there is no limit or it cannot be read.
+func ContainerMemoryLimit() uint64 {
+	for _, path := range []string{
+		"/sys/fs/cgroup/memory.max",
+		"/sys/fs/cgroup/memory/memory.limit_in_bytes",
+	} {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		value := strings.TrimSpace(string(raw))
+		if value == "max" {
+			return 0
+		}
+
+		limit, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || limit >= unlimitedMemory {
+			return 0
+		}
+		return limit
+	}
+	return 0
+}
+
+// BenchmarkArgon2 returns the median duration of samples argon2.IDKey runs.
+func BenchmarkArgon2(p Argon2Params, samples int) time.Duration {
+	if samples < 1 {
+		samples = 1
+	}
+
+	password := []byte("argon2-calibration-password")
+	salt, err := utils.RandomBytes(16)
+	if err != nil {
+		salt = make([]byte, 16)
+	}
+
+	durations := make([]time.Duration, samples)
+	for i := range durations {
+		start := time.Now()
+		key := argon2.IDKey(password, salt, p.Iterations, p.Memory, p.Parallelism, 32)
+		durations[i] = time.Since(start)
+		utils.ZeroBytes(key)
+	}
+
+	sort.Slice(durations, func(a, b int) bool { return durations[a] < durations[b] })
+	return durations[len(durations)/2]
+}
+
+// Argon2CalibrationOptions describe the machine budget to calibrate against.
+type Argon2CalibrationOptions struct {
+	// Target is the acceptable latency of a single hash (login / register).
+	Target time.Duration
+	// MemoryBudget is the memory in bytes reserved for hashing in total.
+	MemoryBudget uint64
+	// Concurrency is the number of hashes expected to run at the same time;
+	// each one may use at most MemoryBudget / Concurrency.
+	Concurrency int
+	Parallelism uint8
+	// MaxMemory caps the memory of a single hash in KiB, 0 = 1 GiB.
+	MaxMemory uint32
+	// Samples per measured configuration, 0 = 3.
+	Samples int
+}
+
+// Argon2Calibration is the recommended configuration and what it costs here.
+type Argon2Calibration struct {
+	Params     Argon2Params
+	Duration   time.Duration // median latency of one hash on this machine
+	PeakMemory uint64        // bytes, Concurrency hashes at once
+	Warnings   []string
+}
+
+var ErrArgon2BudgetTooSmall = errors.New("memory budget per hash is below the OWASP minimum of 7 MiB")
+
+// CalibrateArgon2 picks the strongest parameters that fit the budget: memory
+// first (the largest share of the budget, halved while a single iteration is
+// slower than the target), then as many iterations as the target latency
+// allows. Parameters are raised to the OWASP minimum even when that misses the
+// target, in which case a warning is returned.
+func CalibrateArgon2(opts Argon2CalibrationOptions) (Argon2Calibration, error) {
+	if opts.Target <= 0 {
+		return Argon2Calibration{}, errors.New("target latency must be positive")
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	if opts.Parallelism < 1 {
+		opts.Parallelism = 1
+	}
+	if opts.MaxMemory == 0 {
+		opts.MaxMemory = 1024 * 1024
+	}
+	if opts.Samples < 1 {
+		opts.Samples = 3
+	}
+
+	floor := OWASPArgon2Minimums[len(OWASPArgon2Minimums)-1].Memory
+
+	// memory per hash, whole MiB
+	perHash := opts.MemoryBudget / uint64(opts.Concurrency) / 1024
+	memory := uint32(min(perHash, uint64(opts.MaxMemory))) / 1024 * 1024
+	if memory < floor {
+		return Argon2Calibration{}, ErrArgon2BudgetTooSmall
+	}
+
+	p := Argon2Params{Memory: memory, Iterations: 1, Parallelism: opts.Parallelism}
+	d := BenchmarkArgon2(p, opts.Samples)
+	for d > opts.Target && p.Memory/2 >= floor {
+		p.Memory = p.Memory / 2 / 1024 * 1024
+		d = BenchmarkArgon2(p, opts.Samples)
+	}
+
+	// iterations scale linearly, estimate then correct with a measurement
+	if d < opts.Target {
+		p.Iterations = max(1, uint32(opts.Target/d))
+		d = BenchmarkArgon2(p, opts.Samples)
+		for d > opts.Target && p.Iterations > 1 {
+			p.Iterations--
+			d = BenchmarkArgon2(p, opts.Samples)
+		}
+	}
+
+	var warnings []string
+	if required := minIterationsFor(p.Memory); p.Iterations < required {
+		p.Iterations = required
+		d = BenchmarkArgon2(p, opts.Samples)
+		warnings = append(warnings, fmt.Sprintf(
+			"iterations raised to the OWASP minimum, a hash takes %s (target %s)",
+			d.Round(time.Millisecond), opts.Target,
+		))
+	}
+
+	return Argon2Calibration{
+		Params:     p,
+		Duration:   d,
+		PeakMemory: p.MemoryBytes() * uint64(opts.Concurrency),
+		Warnings:   warnings,
+	}, nil
+}
@@ -0,0 +1,103 @@
+package security
+
+import (
+	"bufio"
+	"net/netip"
+	"os"
+	"strings"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+)
+
+// ipReputationList classifies IPs using local blocklists (one IP or CIDR per line).
+// Malicious list: known attackers / botnets. Suspicious list: Tor exits, hosting ranges, VPNs.
+type ipReputationList struct {
+	malicious  []netip.Prefix
+	suspicious []netip.Prefix
+}
+
+func NewIPReputationList(maliciousPath, suspiciousPath string) (ports.IPReputationChecker, error) {
+	malicious, err := loadPrefixes(maliciousPath)
+	if err != nil {
+		return nil, err
+	}
+
+	suspicious, err := loadPrefixes(suspiciousPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ipReputationList{
+		malicious:  malicious,
+		suspicious: suspicious,
+	}, nil
+}
+
+func (l *ipReputationList) Check(ip string) valueobjects.IPReputation {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return valueobjects.IPReputationClean
+	}
+	addr = addr.Unmap()
+
+	if containsAddr(l.malicious, addr) {
+		return valueobjects.IPReputationMalicious
+	}
+	if containsAddr(l.suspicious, addr) {
+		return valueobjects.IPReputationSuspicious
+	}
+
+	return valueobjects.IPReputationClean
+}
+
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, p := range prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func loadPrefixes(path string) ([]netip.Prefix, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var prefixes []netip.Prefix
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" {
+			continue
+		}
+
+		if !strings.Contains(line, "/") {
+			addr, err := netip.ParseAddr(line)
+			if err != nil {
+				return nil, err
+			}
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(line)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, scanner.Err()
+}
@@ -106,9 +106,29 @@ func (j *JWTSigner) verify(
 		return nil, errors.New("invalid token type")
 	}
 
-	return &valueobjects.TokenPayload{
+	payload := &valueobjects.TokenPayload{
 		UserID:    claims["sub"].(string),
-		TokenID:   claims["jti"].(string),
 		ExpiresAt: time.Unix(int64(claims["exp"].(float64)), 0),
-	}, nil
+	}
+
+	// access token tidak selalu punya jti / auth_time / acr
+	if jti, ok := claims["jti"].(string); ok {
+		payload.TokenID = jti
+	}
+	if authTime, ok := claims["auth_time"].(float64); ok {
+		payload.AuthTime = time.Unix(int64(authTime), 0)
+	}
+	if acr, ok := claims["acr"].(string); ok {
+		payload.ACR = acr
+	}
+	if subType, ok := claims["sub_type"].(string); ok {
+		payload.SubjectType = subType
+	}
+	if act, ok := claims["act"].(map[string]any); ok {
+		if actorID, ok := act["sub"].(string); ok {
+			payload.ActorID = actorID
+		}
+	}
+
+	return payload, nil
 }
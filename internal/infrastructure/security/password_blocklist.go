@@ -0,0 +1,62 @@
+package security
+
+import (
+	"bufio"
+	_ "embed"
+	"os"
+	"strings"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+)
+
+//go:embed common_passwords.txt
+var commonPasswords string
+
+// LoadPasswordBlocklist merges the built-in common password list (optional),
+// a file with one password per line and extra words such as the app name.
+// Lines starting with "#" are ignored.
+func LoadPasswordBlocklist(builtin bool, path string, extra []string) (valueobjects.PasswordBlocklist, error) {
+	var words []string
+	if builtin {
+		words = append(words, parseWordList(commonPasswords)...)
+	}
+
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if w := parseWord(scanner.Text()); w != "" {
+				words = append(words, w)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	words = append(words, extra...)
+	return valueobjects.NewPasswordBlocklist(words), nil
+}
+
+func parseWordList(s string) []string {
+	var words []string
+	for _, line := range strings.Split(s, "\n") {
+		if w := parseWord(line); w != "" {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+func parseWord(line string) string {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "#") {
+		return ""
+	}
+	return line
+}
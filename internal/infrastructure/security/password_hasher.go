@@ -1,6 +1,7 @@
 package security
 
 import (
+	"context"
 	"crypto/subtle"
 	"encoding/base64"
 	"fmt"
@@ -47,7 +48,11 @@ func NewPasswordHasher(config *PasswordConfig) ports.PasswordHasher {
 	return &argon2Hasher{config: config}
 }
 
-func (a *argon2Hasher) HashPassword(password []byte) (string, error) {
+func (a *argon2Hasher) HashPassword(ctx context.Context, password []byte) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
 	cfg := a.config
 
 	salt, err := utils.RandomBytes(cfg.SaltLength)
@@ -86,7 +91,11 @@ func (a *argon2Hasher) HashPassword(password []byte) (string, error) {
 	return encoded, nil
 }
 
-func (a *argon2Hasher) VerifyPassword(password []byte, encoded string) (bool, bool, error) {
+func (a *argon2Hasher) VerifyPassword(ctx context.Context, password []byte, encoded string) (bool, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, false, err
+	}
+
 	cfg := a.config
 
 	parsed, err := utils.ParseHash(encoded)
@@ -0,0 +1,279 @@
+package security
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/users"
+	utils "github.com/dhanarrizky/Golang-template/pkg/utils"
+)
+
+// passwordHashingWaitBuckets are the upper bounds of the queue wait histogram.
+var passwordHashingWaitBuckets = []time.Duration{
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+}
+
+// PasswordHashingPool bounds the memory used by concurrent argon2id hashes.
+// Every HashPassword / VerifyPassword reserves the memory of its parameters
+// (m, in KiB) from a weighted semaphore sized to the memory budget, so a login
+// flood queues instead of allocating 64 MB per request until the pod is
+// killed. Callers that wait longer than the queue timeout get
+// ports.ErrPasswordHasherBusy, which the HTTP layer turns into a 503.
+type PasswordHashingPool struct {
+	inner        ports.PasswordHasher
+	sem          *weightedSemaphore
+	hashWeight   int64
+	queueTimeout time.Duration
+
+	running  atomic.Int64
+	waiting  atomic.Int64
+	acquired atomic.Uint64
+	rejected atomic.Uint64
+	canceled atomic.Uint64
+
+	mu          sync.Mutex
+	waitTotal   time.Duration
+	waitMax     time.Duration
+	waitBuckets []uint64
+}
+
+// NewPasswordHashingPool wraps inner. memoryBudget is in KiB, hashMemory is
+// the m parameter of new hashes (KiB). A single hash larger than the budget
+// still runs, alone.
+func NewPasswordHashingPool(
+	inner ports.PasswordHasher,
+	memoryBudget uint64,
+	hashMemory uint32,
+	queueTimeout time.Duration,
+) *PasswordHashingPool {
+	p := &PasswordHashingPool{
+		inner:        inner,
+		sem:          newWeightedSemaphore(int64(memoryBudget)),
+		queueTimeout: queueTimeout,
+		waitBuckets:  make([]uint64, len(passwordHashingWaitBuckets)),
+	}
+	p.hashWeight = p.weight(hashMemory)
+	return p
+}
+
+func (p *PasswordHashingPool) HashPassword(ctx context.Context, password []byte) (string, error) {
+	if err := p.acquire(ctx, p.hashWeight); err != nil {
+		return "", err
+	}
+	defer p.release(p.hashWeight)
+
+	return p.inner.HashPassword(ctx, password)
+}
+
+func (p *PasswordHashingPool) VerifyPassword(ctx context.Context, password []byte, hashed string) (bool, bool, error) {
+	// stored hashes keep the parameters they were created with
+	parsed, err := utils.ParseHash(hashed)
+	if err != nil {
+		return false, false, err
+	}
+
+	weight := p.weight(parsed.Memory)
+	if err := p.acquire(ctx, weight); err != nil {
+		return false, false, err
+	}
+	defer p.release(weight)
+
+	return p.inner.VerifyPassword(ctx, password, hashed)
+}
+
+func (p *PasswordHashingPool) Stats() ports.PasswordHashingStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	buckets := make([]ports.PasswordHashingWaitBucket, len(passwordHashingWaitBuckets))
+	for i, le := range passwordHashingWaitBuckets {
+		buckets[i] = ports.PasswordHashingWaitBucket{Le: le, Count: p.waitBuckets[i]}
+	}
+
+	return ports.PasswordHashingStats{
+		MemoryBudget: uint64(p.sem.size),
+		MemoryInUse:  uint64(p.sem.inUse()),
+		Running:      p.running.Load(),
+		Waiting:      p.waiting.Load(),
+		Acquired:     p.acquired.Load(),
+		Rejected:     p.rejected.Load(),
+		Canceled:     p.canceled.Load(),
+		WaitTotal:    p.waitTotal,
+		WaitMax:      p.waitMax,
+		WaitBuckets:  buckets,
+	}
+}
+
+// weight is the memory of one hash, at least 1 KiB and at most the budget.
+func (p *PasswordHashingPool) weight(memory uint32) int64 {
+	return min(max(int64(memory), 1), p.sem.size)
+}
+
+func (p *PasswordHashingPool) acquire(ctx context.Context, weight int64) error {
+	start := time.Now()
+
+	if !p.sem.tryAcquire(weight) {
+		p.waiting.Add(1)
+		queueCtx, cancel := context.WithTimeout(ctx, p.queueTimeout)
+		err := p.sem.acquire(queueCtx, weight)
+		cancel()
+		p.waiting.Add(-1)
+
+		if err != nil {
+			// the request itself is gone: not a capacity problem
+			if ctx.Err() != nil {
+				p.canceled.Add(1)
+				return ctx.Err()
+			}
+			p.rejected.Add(1)
+			return ports.ErrPasswordHasherBusy
+		}
+	}
+
+	p.running.Add(1)
+	p.acquired.Add(1)
+	p.observeWait(time.Since(start))
+	return nil
+}
+
+func (p *PasswordHashingPool) release(weight int64) {
+	p.running.Add(-1)
+	p.sem.release(weight)
+}
+
+func (p *PasswordHashingPool) observeWait(wait time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.waitTotal += wait
+	if wait > p.waitMax {
+		p.waitMax = wait
+	}
+	for i, le := range passwordHashingWaitBuckets {
+		if wait <= le {
+			p.waitBuckets[i]++
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// weighted semaphore
+
+var errSemaphoreTooLarge = errors.New("semaphore: weight larger than size")
+
+// weightedSemaphore hands out weight in FIFO order, so a large hash waiting
+// for memory is not starved by a stream of smaller ones.
+type weightedSemaphore struct {
+	size int64
+
+	mu      sync.Mutex
+	cur     int64
+	waiters list.List // of semaphoreWaiter
+}
+
+type semaphoreWaiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+func newWeightedSemaphore(size int64) *weightedSemaphore {
+	return &weightedSemaphore{size: max(size, 1)}
+}
+
+func (s *weightedSemaphore) tryAcquire(n int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		return true
+	}
+	return false
+}
+
+func (s *weightedSemaphore) acquire(ctx context.Context, n int64) error {
+	if n > s.size {
+		return errSemaphoreTooLarge
+	}
+
+	s.mu.Lock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.mu.Unlock()
+		return nil
+	}
+
+	ready := make(chan struct{})
+	elem := s.waiters.PushBack(semaphoreWaiter{n: n, ready: ready})
+	s.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+
+	case <-ctx.Done():
+		s.mu.Lock()
+		select {
+		case <-ready:
+			// acquired just as the context ended: give it back
+			s.cur -= n
+			s.notifyWaiters()
+		default:
+			isFront := s.waiters.Front() == elem
+			s.waiters.Remove(elem)
+			// the next waiters may fit now that the head of the queue left
+			if isFront && s.size > s.cur {
+				s.notifyWaiters()
+			}
+		}
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+func (s *weightedSemaphore) release(n int64) {
+	s.mu.Lock()
+	s.cur -= n
+	if s.cur < 0 {
+		s.mu.Unlock()
+		panic("semaphore: released more than held")
+	}
+	s.notifyWaiters()
+	s.mu.Unlock()
+}
+
+func (s *weightedSemaphore) inUse() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cur
+}
+
+// notifyWaiters wakes waiters in order while they fit; must hold s.mu.
+func (s *weightedSemaphore) notifyWaiters() {
+	for {
+		next := s.waiters.Front()
+		if next == nil {
+			return
+		}
+
+		w := next.Value.(semaphoreWaiter)
+		if s.size-s.cur < w.n {
+			return
+		}
+
+		s.cur += w.n
+		s.waiters.Remove(next)
+		close(w.ready)
+	}
+}
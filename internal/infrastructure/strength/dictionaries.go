@@ -0,0 +1,83 @@
+package strength
+
+import (
+	"embed"
+	"strings"
+	"unicode"
+)
+
+//go:embed dictionaries/*.txt
+var dictionaryFiles embed.FS
+
+// Dictionary names, also used to pick the feedback for a dictionary match.
+const (
+	dictPasswords  = "passwords"
+	dictEnglish    = "english"
+	dictNames      = "names"
+	dictIndonesian = "indonesian"
+	dictUserInputs = "user_inputs"
+)
+
+// rankedDictionary maps a lower-case word to its rank; rank 1 is the most
+// common word and therefore the cheapest guess.
+type rankedDictionary map[string]int
+
+func loadDictionaries() map[string]rankedDictionary {
+	dicts := make(map[string]rankedDictionary)
+	for _, name := range []string{dictPasswords, dictEnglish, dictNames, dictIndonesian} {
+		data, err := dictionaryFiles.ReadFile("dictionaries/" + name + ".txt")
+		if err != nil {
+			// embedded at build time, so a missing file is a programming error
+			panic(err)
+		}
+
+		var words []string
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			words = append(words, line)
+		}
+		dicts[name] = buildRankedDictionary(words)
+	}
+	return dicts
+}
+
+func buildRankedDictionary(words []string) rankedDictionary {
+	dict := make(rankedDictionary, len(words))
+	rank := 0
+	for _, w := range words {
+		w = strings.ToLower(w)
+		if _, ok := dict[w]; ok {
+			continue
+		}
+		rank++
+		dict[w] = rank
+	}
+	return dict
+}
+
+// userInputWords expands user inputs such as "john.doe@example.com" into the
+// full value and its alphanumeric parts ("john", "doe", "example"), since any
+// of them is an easy guess for someone targeting that account.
+func userInputWords(inputs []string) []string {
+	var words []string
+	for _, input := range inputs {
+		input = strings.ToLower(strings.TrimSpace(input))
+		if input == "" {
+			continue
+		}
+		words = append(words, input)
+
+		parts := strings.FieldsFunc(input, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for _, part := range parts {
+			if len([]rune(part)) >= 3 && part != input {
+				words = append(words, part)
+			}
+		}
+	}
+	return words
+}
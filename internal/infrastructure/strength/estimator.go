@@ -0,0 +1,135 @@
+package strength
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+)
+
+const (
+	// maxPasswordLength: only the first runes are analysed, matching is
+	// quadratic in the length and longer passwords score 4 regardless
+	maxPasswordLength = 100
+
+	// guessesPerSecond models an offline attack against a slow hash
+	// (bcrypt / argon2), the realistic case after a database leak
+	guessesPerSecond = 1e4
+)
+
+// Estimator rates passwords the way zxcvbn does: the password is split into
+// the cheapest sequence of guessable patterns (common passwords, dictionary
+// words, keyboard walks, dates, repeats, sequences, l33t) and the score
+// reflects the number of guesses an attacker would need for that sequence.
+type Estimator struct {
+	dictionaries map[string]rankedDictionary
+	extraInputs  []string
+}
+
+// NewEstimator loads the embedded dictionaries. extraInputs are words that
+// are easy to guess for every account, such as the application name.
+func NewEstimator(extraInputs ...string) ports.PasswordStrengthEstimator {
+	return &Estimator{
+		dictionaries: loadDictionaries(),
+		extraInputs:  extraInputs,
+	}
+}
+
+func (e *Estimator) Estimate(password string, userInputs []string) valueobjects.PasswordStrength {
+	runes := []rune(password)
+	if len(runes) > maxPasswordLength {
+		runes = runes[:maxPasswordLength]
+	}
+
+	dicts := e.dictionaries
+	inputs := userInputWords(append(append([]string{}, e.extraInputs...), userInputs...))
+	if len(inputs) > 0 {
+		dicts = make(map[string]rankedDictionary, len(e.dictionaries)+1)
+		for name, dict := range e.dictionaries {
+			dicts[name] = dict
+		}
+		dicts[dictUserInputs] = buildRankedDictionary(inputs)
+	}
+
+	m := newMatcher(dicts)
+	result := mostGuessableMatchSequence(runes, m.omnimatch(runes), false)
+
+	guesses := result.guesses
+	if math.IsInf(guesses, 1) || guesses > math.MaxFloat64 {
+		guesses = math.MaxFloat64
+	}
+
+	score := guessesToScore(guesses)
+	warning, suggestions := feedback(score, result.sequence)
+
+	return valueobjects.PasswordStrength{
+		Score:        score,
+		GuessesLog10: math.Round(math.Log10(guesses)*100) / 100,
+		CrackTime:    displayTime(guesses / guessesPerSecond),
+		Warning:      warning,
+		Suggestions:  suggestions,
+	}
+}
+
+// guessesToScore: the +5 margin keeps passwords right at a threshold (e.g.
+// the 1000th most common password) on the weaker side.
+func guessesToScore(guesses float64) int {
+	const delta = 5
+	switch {
+	case guesses < 1e3+delta:
+		return 0 // too guessable: risky password
+	case guesses < 1e6+delta:
+		return 1 // very guessable: protection from throttled online attacks
+	case guesses < 1e8+delta:
+		return 2 // somewhat guessable: protection from unthrottled online attacks
+	case guesses < 1e10+delta:
+		return 3 // safely unguessable: moderate protection from offline attacks
+	default:
+		return 4 // very unguessable: strong protection from offline attacks
+	}
+}
+
+func displayTime(seconds float64) string {
+	const (
+		minute  = 60
+		hour    = minute * 60
+		day     = hour * 24
+		month   = day * 31
+		year    = month * 12
+		century = year * 100
+	)
+
+	unit := func(n float64, name string) string {
+		v := int64(math.Round(n))
+		if v == 1 {
+			return fmt.Sprintf("%d %s", v, name)
+		}
+		return fmt.Sprintf("%d %ss", v, name)
+	}
+
+	switch {
+	case seconds < 1:
+		return "less than a second"
+	case seconds < minute:
+		return unit(seconds, "second")
+	case seconds < hour:
+		return unit(seconds/minute, "minute")
+	case seconds < day:
+		return unit(seconds/hour, "hour")
+	case seconds < month:
+		return unit(seconds/day, "day")
+	case seconds < year:
+		return unit(seconds/month, "month")
+	case seconds < century:
+		return unit(seconds/year, "year")
+	default:
+		return "centuries"
+	}
+}
+
+// referenceYear is the year dates and recent years are measured against.
+func referenceYear() int {
+	return time.Now().Year()
+}
@@ -0,0 +1,116 @@
+package strength
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+var defaultSuggestions = []string{
+	"Use a few words, avoid common phrases",
+	"No need for symbols, digits, or uppercase letters",
+}
+
+const extraSuggestion = "Add another word or two. Uncommon words are better."
+
+// feedback explains a weak score through its longest match; passwords scoring
+// 3 or more get no feedback.
+func feedback(score int, sequence []*match) (string, []string) {
+	if len(sequence) == 0 {
+		return "", append([]string(nil), defaultSuggestions...)
+	}
+	if score > 2 {
+		return "", nil
+	}
+
+	longest := sequence[0]
+	for _, m := range sequence[1:] {
+		if len([]rune(m.token)) > len([]rune(longest.token)) {
+			longest = m
+		}
+	}
+
+	warning, suggestions := matchFeedback(longest, len(sequence) == 1)
+	return warning, append([]string{extraSuggestion}, suggestions...)
+}
+
+func matchFeedback(m *match, soleMatch bool) (string, []string) {
+	switch m.pattern {
+	case patternDictionary:
+		return dictionaryFeedback(m, soleMatch)
+
+	case patternSpatial:
+		warning := "Short keyboard patterns are easy to guess"
+		if m.turns == 1 {
+			warning = "Straight rows of keys are easy to guess"
+		}
+		return warning, []string{"Use a longer keyboard pattern with more turns"}
+
+	case patternRepeat:
+		warning := `Repeats like "abcabcabc" are only slightly harder to guess than "abc"`
+		if len([]rune(m.baseToken)) == 1 {
+			warning = `Repeats like "aaa" are easy to guess`
+		}
+		return warning, []string{"Avoid repeated words and characters"}
+
+	case patternSequence:
+		return "Sequences like abc or 6543 are easy to guess", []string{"Avoid sequences"}
+
+	case patternRegex:
+		return "Recent years are easy to guess", []string{
+			"Avoid recent years",
+			"Avoid years that are associated with you",
+		}
+
+	case patternDate:
+		return "Dates are often easy to guess", []string{"Avoid dates and years that are associated with you"}
+	}
+	return "", nil
+}
+
+func dictionaryFeedback(m *match, soleMatch bool) (string, []string) {
+	var warning string
+	switch m.dictionaryName {
+	case dictPasswords:
+		switch {
+		case soleMatch && !m.l33t && !m.reversed:
+			switch {
+			case m.rank <= 10:
+				warning = "This is a top-10 common password"
+			case m.rank <= 100:
+				warning = "This is a top-100 common password"
+			default:
+				warning = "This is a very common password"
+			}
+		case math.Log10(m.guesses) <= 4:
+			warning = "This is similar to a commonly used password"
+		}
+	case dictEnglish, dictIndonesian:
+		if soleMatch {
+			warning = "A word by itself is easy to guess"
+		}
+	case dictNames:
+		warning = "Common names and surnames are easy to guess"
+		if soleMatch {
+			warning = "Names and surnames by themselves are easy to guess"
+		}
+	case dictUserInputs:
+		warning = "Avoid words related to you, like your username or email"
+	}
+
+	var suggestions []string
+	runes := []rune(m.token)
+	switch {
+	case startUpper(runes):
+		suggestions = append(suggestions, "Capitalization doesn't help very much")
+	case strings.IndexFunc(m.token, unicode.IsLower) < 0 && strings.ToLower(m.token) != m.token:
+		suggestions = append(suggestions, "All-uppercase is almost as easy to guess as all-lowercase")
+	}
+	if m.reversed && len(runes) >= 4 {
+		suggestions = append(suggestions, "Reversed words aren't much harder to guess")
+	}
+	if m.l33t {
+		suggestions = append(suggestions, "Predictable substitutions like '@' instead of 'a' don't help very much")
+	}
+	return warning, suggestions
+}
@@ -0,0 +1,99 @@
+package strength
+
+import "strings"
+
+// keyboardGraph maps each key character to its neighbours. Every neighbour is
+// the full key ("qQ": unshifted then shifted), or "" when there is no key in
+// that direction; the slice index is the direction, used to count turns.
+type keyboardGraph struct {
+	name      string
+	keys      map[rune]string
+	adjacency map[rune][]string
+
+	startingPositions float64
+	averageDegree     float64
+}
+
+const (
+	qwertyLayout = "" +
+		"`~ 1! 2@ 3# 4$ 5% 6^ 7& 8* 9( 0) -_ =+\n" +
+		"    qQ wW eE rR tT yY uU iI oO pP [{ ]} \\|\n" +
+		"     aA sS dD fF gG hH jJ kK lL ;: '\"\n" +
+		"      zZ xX cC vV bB nN mM ,< .> /?"
+
+	keypadLayout = "" +
+		"  / * -\n" +
+		"7 8 9 +\n" +
+		"4 5 6\n" +
+		"1 2 3\n" +
+		"  0 ."
+)
+
+var (
+	qwertyGraph = buildKeyboardGraph("qwerty", qwertyLayout, true)
+	keypadGraph = buildKeyboardGraph("keypad", keypadLayout, false)
+
+	keyboardGraphs = []*keyboardGraph{qwertyGraph, keypadGraph}
+)
+
+type keyPos struct{ x, y int }
+
+// buildKeyboardGraph derives the adjacency graph from an ASCII drawing of the
+// layout. Rows of a slanted keyboard are shifted right by one column each, so
+// a key touches six others; an aligned keypad key touches up to eight.
+func buildKeyboardGraph(name, layout string, slanted bool) *keyboardGraph {
+	keys := make(map[keyPos]string)
+	tokenSize := len(strings.Fields(layout)[0])
+	unit := tokenSize + 1
+
+	for y, line := range strings.Split(layout, "\n") {
+		slant := 0
+		if slanted {
+			slant = y
+		}
+		for x := 0; x < len(line); {
+			if line[x] == ' ' {
+				x++
+				continue
+			}
+			keys[keyPos{(x - slant) / unit, y}] = line[x : x+tokenSize]
+			x += tokenSize
+		}
+	}
+
+	g := &keyboardGraph{name: name, keys: make(map[rune]string), adjacency: make(map[rune][]string)}
+	degrees := 0
+	for pos, key := range keys {
+		var neighbours []string
+		for _, n := range adjacentPositions(pos, slanted) {
+			neighbours = append(neighbours, keys[n])
+			if keys[n] != "" {
+				degrees++
+			}
+		}
+		for _, r := range key {
+			g.keys[r] = key
+			g.adjacency[r] = neighbours
+		}
+	}
+
+	g.startingPositions = float64(len(keys))
+	g.averageDegree = float64(degrees) / float64(len(keys))
+	return g
+}
+
+func adjacentPositions(p keyPos, slanted bool) []keyPos {
+	x, y := p.x, p.y
+	if slanted {
+		return []keyPos{{x - 1, y}, {x, y - 1}, {x + 1, y - 1}, {x + 1, y}, {x, y + 1}, {x - 1, y + 1}}
+	}
+	return []keyPos{
+		{x - 1, y}, {x - 1, y - 1}, {x, y - 1}, {x + 1, y - 1},
+		{x + 1, y}, {x + 1, y + 1}, {x, y + 1}, {x - 1, y + 1},
+	}
+}
+
+// shifted reports whether r is typed with shift on this keyboard.
+func (g *keyboardGraph) shifted(r rune) bool {
+	return strings.IndexRune(g.keys[r], r) > 0
+}
@@ -0,0 +1,646 @@
+package strength
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Match patterns
+const (
+	patternDictionary = "dictionary"
+	patternSpatial    = "spatial"
+	patternRepeat     = "repeat"
+	patternSequence   = "sequence"
+	patternRegex      = "regex"
+	patternDate       = "date"
+	patternBruteforce = "bruteforce"
+)
+
+// match is a guessable part of the password, covering runes i..j inclusive.
+type match struct {
+	pattern string
+	i, j    int
+	token   string
+
+	// dictionary
+	dictionaryName string
+	matchedWord    string
+	rank           int
+	reversed       bool
+	l33t           bool
+	sub            map[rune]rune // substituted character -> original letter
+
+	// spatial
+	graph        *keyboardGraph
+	turns        int
+	shiftedCount int
+
+	// repeat
+	baseToken   string
+	baseGuesses float64
+	repeatCount int
+
+	// sequence
+	ascending bool
+
+	// regex (recent year) and date
+	year      int
+	month     int
+	day       int
+	separator string
+
+	guesses float64 // 0 until estimated
+}
+
+// matcher finds every pattern match in a password against a fixed set of
+// ranked dictionaries.
+type matcher struct {
+	dictionaries map[string]rankedDictionary
+	names        []string // dictionary names, sorted so results are deterministic
+}
+
+func newMatcher(dictionaries map[string]rankedDictionary) *matcher {
+	names := make([]string, 0, len(dictionaries))
+	for name := range dictionaries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return &matcher{dictionaries: dictionaries, names: names}
+}
+
+// omnimatch returns all matches of every pattern, ordered by position.
+func (m *matcher) omnimatch(password []rune) []*match {
+	var matches []*match
+	matches = append(matches, m.dictionaryMatch(password)...)
+	matches = append(matches, m.reverseDictionaryMatch(password)...)
+	matches = append(matches, m.l33tMatch(password)...)
+	matches = append(matches, spatialMatch(password)...)
+	matches = append(matches, m.repeatMatch(password)...)
+	matches = append(matches, sequenceMatch(password)...)
+	matches = append(matches, m.recentYearMatch(password)...)
+	matches = append(matches, m.dateMatch(password)...)
+
+	sortMatches(matches)
+	return matches
+}
+
+func sortMatches(matches []*match) {
+	sort.SliceStable(matches, func(a, b int) bool {
+		if matches[a].i != matches[b].i {
+			return matches[a].i < matches[b].i
+		}
+		return matches[a].j < matches[b].j
+	})
+}
+
+// ---------------------------------------------------------------------------
+// dictionary, reversed dictionary and l33t
+
+func (m *matcher) dictionaryMatch(password []rune) []*match {
+	lower := []rune(strings.ToLower(string(password)))
+	if len(lower) != len(password) {
+		// case mapping changed the rune count; positions would not line up
+		return nil
+	}
+
+	var matches []*match
+	for _, name := range m.names {
+		dict := m.dictionaries[name]
+		for i := range lower {
+			for j := i; j < len(lower); j++ {
+				word := string(lower[i : j+1])
+				rank, ok := dict[word]
+				if !ok {
+					continue
+				}
+				matches = append(matches, &match{
+					pattern:        patternDictionary,
+					i:              i,
+					j:              j,
+					token:          string(password[i : j+1]),
+					dictionaryName: name,
+					matchedWord:    word,
+					rank:           rank,
+				})
+			}
+		}
+	}
+	return matches
+}
+
+func (m *matcher) reverseDictionaryMatch(password []rune) []*match {
+	n := len(password)
+	matches := m.dictionaryMatch(reverseRunes(password))
+	for _, mt := range matches {
+		mt.token = reverseString(mt.token)
+		mt.reversed = true
+		mt.i, mt.j = n-1-mt.j, n-1-mt.i
+	}
+	return matches
+}
+
+// l33tTable lists the letters each substitution character can stand for.
+var l33tTable = map[rune][]rune{
+	'4': {'a'}, '@': {'a'},
+	'8': {'b'},
+	'(': {'c'}, '{': {'c'}, '[': {'c'}, '<': {'c'},
+	'3': {'e'},
+	'6': {'g'}, '9': {'g'},
+	'1': {'i', 'l'}, '!': {'i'}, '|': {'i', 'l'},
+	'7': {'l', 't'},
+	'0': {'o'},
+	'$': {'s'}, '5': {'s'},
+	'+': {'t'},
+	'%': {'x'},
+	'2': {'z'},
+}
+
+// maxL33tSubs bounds the substitution combinations tried per password.
+const maxL33tSubs = 100
+
+func (m *matcher) l33tMatch(password []rune) []*match {
+	var matches []*match
+	seen := make(map[string]bool)
+
+	for _, sub := range l33tSubs(password) {
+		translated := make([]rune, len(password))
+		for k, r := range password {
+			if letter, ok := sub[r]; ok {
+				translated[k] = letter
+			} else {
+				translated[k] = r
+			}
+		}
+
+		for _, mt := range m.dictionaryMatch(translated) {
+			token := password[mt.i : mt.j+1]
+			if len(token) <= 1 {
+				// a lone substituted character is not a word
+				continue
+			}
+
+			used := make(map[rune]rune)
+			for _, r := range token {
+				if letter, ok := sub[r]; ok {
+					used[r] = letter
+				}
+			}
+			if len(used) == 0 {
+				continue
+			}
+
+			key := mt.dictionaryName + ":" + strconv.Itoa(mt.i) + ":" + strconv.Itoa(mt.j) + ":" + mt.matchedWord
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			mt.token = string(token)
+			mt.l33t = true
+			mt.sub = used
+			matches = append(matches, mt)
+		}
+	}
+	return matches
+}
+
+// l33tSubs enumerates the ways the substitution characters present in the
+// password can be read back as letters; each map picks one letter per
+// character.
+func l33tSubs(password []rune) []map[rune]rune {
+	var chars []rune
+	present := make(map[rune]bool)
+	for _, r := range password {
+		if _, ok := l33tTable[r]; ok && !present[r] {
+			present[r] = true
+			chars = append(chars, r)
+		}
+	}
+	if len(chars) == 0 {
+		return nil
+	}
+	sort.Slice(chars, func(a, b int) bool { return chars[a] < chars[b] })
+
+	subs := []map[rune]rune{{}}
+	for _, c := range chars {
+		var next []map[rune]rune
+		for _, sub := range subs {
+			for _, letter := range l33tTable[c] {
+				if len(next) >= maxL33tSubs {
+					break
+				}
+				extended := make(map[rune]rune, len(sub)+1)
+				for k, v := range sub {
+					extended[k] = v
+				}
+				extended[c] = letter
+				next = append(next, extended)
+			}
+		}
+		subs = next
+	}
+	return subs
+}
+
+// ---------------------------------------------------------------------------
+// spatial (keyboard walks)
+
+func spatialMatch(password []rune) []*match {
+	var matches []*match
+	for _, g := range keyboardGraphs {
+		matches = append(matches, spatialMatchGraph(password, g)...)
+	}
+	return matches
+}
+
+func spatialMatchGraph(password []rune, g *keyboardGraph) []*match {
+	var matches []*match
+
+	for i := 0; i < len(password)-1; {
+		j := i + 1
+		lastDirection := -1
+		turns := 0
+		shiftedCount := 0
+		if g.shifted(password[i]) {
+			shiftedCount = 1
+		}
+
+		for {
+			found := false
+			if j < len(password) {
+				cur := password[j]
+				for direction, key := range g.adjacency[password[j-1]] {
+					idx := strings.IndexRune(key, cur)
+					if key == "" || idx < 0 {
+						continue
+					}
+					found = true
+					if idx > 0 {
+						shiftedCount++
+					}
+					if lastDirection != direction {
+						turns++
+						lastDirection = direction
+					}
+					break
+				}
+			}
+
+			if found {
+				j++
+				continue
+			}
+
+			// walks of three or more keys count as a pattern
+			if j-i > 2 {
+				matches = append(matches, &match{
+					pattern:      patternSpatial,
+					i:            i,
+					j:            j - 1,
+					token:        string(password[i:j]),
+					graph:        g,
+					turns:        turns,
+					shiftedCount: shiftedCount,
+				})
+			}
+			i = j
+			break
+		}
+	}
+	return matches
+}
+
+// ---------------------------------------------------------------------------
+// repeat
+
+// repeatMatch finds runs of a repeated base ("aaa", "abcabc"). At each
+// position the longest run wins, and for equal runs the shortest base.
+func (m *matcher) repeatMatch(password []rune) []*match {
+	var matches []*match
+
+	for i := 0; i < len(password); {
+		bestLen, bestBase := 0, 0
+		for base := 1; i+2*base <= len(password); base++ {
+			count := 1
+			for i+(count+1)*base <= len(password) &&
+				string(password[i+count*base:i+(count+1)*base]) == string(password[i:i+base]) {
+				count++
+			}
+			if count >= 2 && count*base > bestLen {
+				bestLen, bestBase = count*base, base
+			}
+		}
+
+		if bestLen == 0 {
+			i++
+			continue
+		}
+
+		base := password[i : i+bestBase]
+		baseResult := mostGuessableMatchSequence(base, m.omnimatch(base), false)
+		matches = append(matches, &match{
+			pattern:     patternRepeat,
+			i:           i,
+			j:           i + bestLen - 1,
+			token:       string(password[i : i+bestLen]),
+			baseToken:   string(base),
+			baseGuesses: baseResult.guesses,
+			repeatCount: bestLen / bestBase,
+		})
+		i += bestLen
+	}
+	return matches
+}
+
+// ---------------------------------------------------------------------------
+// sequence
+
+// maxSequenceDelta is the largest step between characters still treated as a
+// sequence ("aceg", "9753").
+const maxSequenceDelta = 5
+
+func sequenceMatch(password []rune) []*match {
+	if len(password) <= 1 {
+		return nil
+	}
+
+	var matches []*match
+	update := func(i, j, delta int) {
+		if j-i > 1 || abs(delta) == 1 {
+			if d := abs(delta); d > 0 && d <= maxSequenceDelta {
+				matches = append(matches, &match{
+					pattern:   patternSequence,
+					i:         i,
+					j:         j,
+					token:     string(password[i : j+1]),
+					ascending: delta > 0,
+				})
+			}
+		}
+	}
+
+	i := 0
+	lastDelta := 0
+	haveLast := false
+	for k := 1; k < len(password); k++ {
+		delta := int(password[k]) - int(password[k-1])
+		if !haveLast {
+			lastDelta, haveLast = delta, true
+		}
+		if delta == lastDelta {
+			continue
+		}
+		j := k - 1
+		update(i, j, lastDelta)
+		i = j
+		lastDelta = delta
+	}
+	update(i, len(password)-1, lastDelta)
+
+	return matches
+}
+
+// ---------------------------------------------------------------------------
+// regex (recent years) and dates
+
+func (m *matcher) recentYearMatch(password []rune) []*match {
+	var matches []*match
+	for i := 0; i+4 <= len(password); i++ {
+		token := string(password[i : i+4])
+		if !allDigits(token) {
+			continue
+		}
+		year, _ := strconv.Atoi(token)
+		if year < 1900 || year > referenceYear()+minYearSpace {
+			continue
+		}
+		matches = append(matches, &match{
+			pattern: patternRegex,
+			i:       i,
+			j:       i + 3,
+			token:   token,
+			year:    year,
+		})
+	}
+	return matches
+}
+
+const (
+	dateMinYear = 1000
+	dateMaxYear = 2050
+)
+
+// dateSplits lists, per token length, where a run of digits may be split into
+// day, month and year ("1391" -> 1 3 91, 13 9 1).
+var dateSplits = map[int][][2]int{
+	4: {{1, 2}, {2, 3}},
+	5: {{1, 3}, {2, 3}},
+	6: {{1, 2}, {2, 4}, {4, 5}},
+	7: {{1, 3}, {2, 3}, {4, 5}, {4, 6}},
+	8: {{2, 4}, {4, 6}},
+}
+
+var dateWithSeparator = regexp.MustCompile(`^(\d{1,4})([\s/\\_.-])(\d{1,2})([\s/\\_.-])(\d{1,4})$`)
+
+type dmy struct{ year, month, day int }
+
+func (m *matcher) dateMatch(password []rune) []*match {
+	var matches []*match
+
+	// dates without separators: 4 to 8 digits
+	for i := 0; i+4 <= len(password); i++ {
+		for j := i + 3; j <= i+7 && j < len(password); j++ {
+			token := string(password[i : j+1])
+			if !allDigits(token) {
+				continue
+			}
+
+			var best *dmy
+			bestDistance := 0
+			for _, split := range dateSplits[len(token)] {
+				ints := [3]int{atoi(token[:split[0]]), atoi(token[split[0]:split[1]]), atoi(token[split[1]:])}
+				d, ok := mapIntsToDMY(ints)
+				if !ok {
+					continue
+				}
+				distance := abs(d.year - referenceYear())
+				if best == nil || distance < bestDistance {
+					best, bestDistance = &d, distance
+				}
+			}
+			if best == nil {
+				continue
+			}
+			matches = append(matches, &match{
+				pattern: patternDate,
+				i:       i,
+				j:       j,
+				token:   token,
+				year:    best.year,
+				month:   best.month,
+				day:     best.day,
+			})
+		}
+	}
+
+	// dates with a separator: 6 to 10 characters, e.g. "1/1/91", "2019-05-17"
+	for i := 0; i+6 <= len(password); i++ {
+		for j := i + 5; j <= i+9 && j < len(password); j++ {
+			token := string(password[i : j+1])
+			groups := dateWithSeparator.FindStringSubmatch(token)
+			if groups == nil || groups[2] != groups[4] {
+				continue
+			}
+			d, ok := mapIntsToDMY([3]int{atoi(groups[1]), atoi(groups[3]), atoi(groups[5])})
+			if !ok {
+				continue
+			}
+			matches = append(matches, &match{
+				pattern:   patternDate,
+				i:         i,
+				j:         j,
+				token:     token,
+				separator: groups[2],
+				year:      d.year,
+				month:     d.month,
+				day:       d.day,
+			})
+		}
+	}
+
+	// drop dates inside a longer date ("1/1/1991" also matches "1/1/91")
+	var filtered []*match
+	for _, mt := range matches {
+		contained := false
+		for _, other := range matches {
+			if mt == other {
+				continue
+			}
+			if other.i <= mt.i && other.j >= mt.j {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			filtered = append(filtered, mt)
+		}
+	}
+	return filtered
+}
+
+// mapIntsToDMY reads three integers as a day, month and year in any common
+// order, rejecting combinations that cannot be a date.
+func mapIntsToDMY(ints [3]int) (dmy, bool) {
+	if ints[1] > 31 || ints[1] <= 0 {
+		return dmy{}, false
+	}
+
+	over12, over31, under1 := 0, 0, 0
+	for _, n := range ints {
+		if (n > 99 && n < dateMinYear) || n > dateMaxYear {
+			return dmy{}, false
+		}
+		if n > 31 {
+			over31++
+		}
+		if n > 12 {
+			over12++
+		}
+		if n <= 0 {
+			under1++
+		}
+	}
+	if over31 >= 2 || over12 == 3 || under1 >= 2 {
+		return dmy{}, false
+	}
+
+	type split struct {
+		year int
+		rest [2]int
+	}
+	splits := []split{
+		{ints[2], [2]int{ints[0], ints[1]}}, // year last
+		{ints[0], [2]int{ints[1], ints[2]}}, // year first
+	}
+
+	for _, s := range splits {
+		if s.year >= dateMinYear && s.year <= dateMaxYear {
+			day, month, ok := mapIntsToDM(s.rest)
+			if !ok {
+				// a four-digit year with no valid day/month is not a date
+				return dmy{}, false
+			}
+			return dmy{year: s.year, month: month, day: day}, true
+		}
+	}
+
+	for _, s := range splits {
+		if day, month, ok := mapIntsToDM(s.rest); ok {
+			return dmy{year: twoToFourDigitYear(s.year), month: month, day: day}, true
+		}
+	}
+	return dmy{}, false
+}
+
+func mapIntsToDM(ints [2]int) (day, month int, ok bool) {
+	for _, dm := range [][2]int{{ints[0], ints[1]}, {ints[1], ints[0]}} {
+		d, mo := dm[0], dm[1]
+		if d >= 1 && d <= 31 && mo >= 1 && mo <= 12 {
+			return d, mo, true
+		}
+	}
+	return 0, 0, false
+}
+
+func twoToFourDigitYear(year int) int {
+	switch {
+	case year > 99:
+		return year
+	case year > 50:
+		return year + 1900
+	default:
+		return year + 2000
+	}
+}
+
+// ---------------------------------------------------------------------------
+// helpers
+
+func reverseRunes(r []rune) []rune {
+	out := make([]rune, len(r))
+	for i, c := range r {
+		out[len(r)-1-i] = c
+	}
+	return out
+}
+
+func reverseString(s string) string {
+	return string(reverseRunes([]rune(s)))
+}
+
+func allDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
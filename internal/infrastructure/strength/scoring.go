@@ -0,0 +1,370 @@
+package strength
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	bruteforceCardinality = 10
+
+	minGuessesBeforeGrowingSequence = 10000
+	minSubmatchGuessesSingleChar    = 10
+	minSubmatchGuessesMultiChar     = 50
+
+	minYearSpace = 20
+)
+
+type guessResult struct {
+	guesses  float64
+	sequence []*match
+}
+
+// sequenceStep is the last match of a candidate sequence.
+type sequenceStep struct {
+	m  *match
+	pi float64 // product of the guesses of all matches in the sequence
+	g  float64 // total guesses including the length penalty
+}
+
+// mostGuessableMatchSequence picks the non-overlapping sequence of matches
+// (gaps filled by bruteforce) that needs the fewest guesses in total. A
+// sequence of l matches costs l! * product(guesses), since the attacker also
+// has to guess the order, plus 10000^(l-1) unless excludeAdditive is set.
+func mostGuessableMatchSequence(password []rune, matches []*match, excludeAdditive bool) guessResult {
+	n := len(password)
+	if n == 0 {
+		return guessResult{guesses: 1}
+	}
+
+	matchesByJ := make([][]*match, n)
+	for _, m := range matches {
+		matchesByJ[m.j] = append(matchesByJ[m.j], m)
+	}
+	for _, ms := range matchesByJ {
+		sort.SliceStable(ms, func(a, b int) bool { return ms[a].i < ms[b].i })
+	}
+
+	// optimal[k][l]: cheapest sequence of l matches covering password[0..k]
+	optimal := make([]map[int]sequenceStep, n)
+	for k := range optimal {
+		optimal[k] = make(map[int]sequenceStep)
+	}
+
+	update := func(m *match, l int) {
+		k := m.j
+		pi := estimateGuesses(m, n)
+		if l > 1 {
+			pi *= optimal[m.i-1][l-1].pi
+		}
+		g := factorial(l) * pi
+		if !excludeAdditive {
+			g += math.Pow(minGuessesBeforeGrowingSequence, float64(l-1))
+		}
+
+		// keep this sequence only if no shorter-or-equal one is as cheap
+		for competingL, competing := range optimal[k] {
+			if competingL > l {
+				continue
+			}
+			if competing.g <= g {
+				return
+			}
+		}
+		optimal[k][l] = sequenceStep{m: m, pi: pi, g: g}
+	}
+
+	bruteforceUpdate := func(k int) {
+		update(bruteforceMatch(password, 0, k), 1)
+		for i := 1; i <= k; i++ {
+			m := bruteforceMatch(password, i, k)
+			for _, l := range sortedKeys(optimal[i-1]) {
+				// two adjacent bruteforce matches are never better than one
+				if optimal[i-1][l].m.pattern == patternBruteforce {
+					continue
+				}
+				update(m, l+1)
+			}
+		}
+	}
+
+	for k := 0; k < n; k++ {
+		for _, m := range matchesByJ[k] {
+			if m.i > 0 {
+				for _, l := range sortedKeys(optimal[m.i-1]) {
+					update(m, l+1)
+				}
+			} else {
+				update(m, 1)
+			}
+		}
+		bruteforceUpdate(k)
+	}
+
+	// unwind from the cheapest sequence ending at the last character
+	bestL, bestG := 0, math.Inf(1)
+	for _, l := range sortedKeys(optimal[n-1]) {
+		if g := optimal[n-1][l].g; g < bestG {
+			bestL, bestG = l, g
+		}
+	}
+
+	sequence := make([]*match, bestL)
+	for k, l := n-1, bestL; k >= 0 && l > 0; l-- {
+		m := optimal[k][l].m
+		sequence[l-1] = m
+		k = m.i - 1
+	}
+
+	return guessResult{guesses: bestG, sequence: sequence}
+}
+
+func bruteforceMatch(password []rune, i, j int) *match {
+	return &match{
+		pattern: patternBruteforce,
+		i:       i,
+		j:       j,
+		token:   string(password[i : j+1]),
+	}
+}
+
+// estimateGuesses returns the guesses needed for a single match. Matches
+// shorter than the password get a floor, so that sequences made of many tiny
+// matches are not rated cheaper than they are.
+func estimateGuesses(m *match, passwordLen int) float64 {
+	if m.guesses != 0 {
+		return m.guesses
+	}
+
+	tokenLen := len([]rune(m.token))
+	minGuesses := 1.0
+	if tokenLen < passwordLen {
+		if tokenLen == 1 {
+			minGuesses = minSubmatchGuessesSingleChar
+		} else {
+			minGuesses = minSubmatchGuessesMultiChar
+		}
+	}
+
+	var guesses float64
+	switch m.pattern {
+	case patternBruteforce:
+		guesses = bruteforceGuesses(m)
+	case patternDictionary:
+		guesses = dictionaryGuesses(m)
+	case patternSpatial:
+		guesses = spatialGuesses(m)
+	case patternRepeat:
+		guesses = m.baseGuesses * float64(m.repeatCount)
+	case patternSequence:
+		guesses = sequenceGuesses(m)
+	case patternRegex:
+		guesses = float64(yearSpace(m.year))
+	case patternDate:
+		guesses = dateGuesses(m)
+	}
+
+	m.guesses = math.Max(guesses, minGuesses)
+	return m.guesses
+}
+
+func bruteforceGuesses(m *match) float64 {
+	tokenLen := len([]rune(m.token))
+	guesses := math.Pow(bruteforceCardinality, float64(tokenLen))
+	if math.IsInf(guesses, 1) {
+		guesses = math.MaxFloat64
+	}
+
+	// one more than the submatch floor, so a real match of the same span wins
+	minGuesses := float64(minSubmatchGuessesMultiChar + 1)
+	if tokenLen == 1 {
+		minGuesses = minSubmatchGuessesSingleChar + 1
+	}
+	return math.Max(guesses, minGuesses)
+}
+
+func dictionaryGuesses(m *match) float64 {
+	guesses := float64(m.rank) * uppercaseVariations(m.token) * l33tVariations(m)
+	if m.reversed {
+		guesses *= 2
+	}
+	return guesses
+}
+
+// uppercaseVariations: all lower-case costs nothing extra, the common
+// capitalisations ("Word", "worD", "WORD") double the guesses, anything else
+// counts every way of placing that many upper-case letters.
+func uppercaseVariations(word string) float64 {
+	runes := []rune(word)
+	upper, lower := 0, 0
+	for _, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+
+	if upper == 0 {
+		return 1
+	}
+	if startUpper(runes) || endUpper(runes) || lower == 0 {
+		return 2
+	}
+
+	var variations float64
+	for i := 1; i <= min(upper, lower); i++ {
+		variations += nCk(upper+lower, i)
+	}
+	return variations
+}
+
+func l33tVariations(m *match) float64 {
+	if !m.l33t {
+		return 1
+	}
+
+	variations := 1.0
+	lower := strings.ToLower(m.token)
+	for subbed, unsubbed := range m.sub {
+		s := strings.Count(lower, string(subbed))
+		u := strings.Count(lower, string(unsubbed))
+		if s == 0 || u == 0 {
+			// fully substituted or only one character: attacker tries both
+			variations *= 2
+			continue
+		}
+
+		var possibilities float64
+		for i := 1; i <= min(u, s); i++ {
+			possibilities += nCk(u+s, i)
+		}
+		variations *= possibilities
+	}
+	return variations
+}
+
+func spatialGuesses(m *match) float64 {
+	s := m.graph.startingPositions
+	d := m.graph.averageDegree
+	tokenLen := len([]rune(m.token))
+
+	var guesses float64
+	for i := 2; i <= tokenLen; i++ {
+		possibleTurns := min(m.turns, i-1)
+		for j := 1; j <= possibleTurns; j++ {
+			guesses += nCk(i-1, j-1) * s * math.Pow(d, float64(j))
+		}
+	}
+
+	if m.shiftedCount > 0 {
+		shifted := m.shiftedCount
+		unshifted := tokenLen - shifted
+		if unshifted == 0 {
+			guesses *= 2
+		} else {
+			var variations float64
+			for i := 1; i <= min(shifted, unshifted); i++ {
+				variations += nCk(shifted+unshifted, i)
+			}
+			guesses *= variations
+		}
+	}
+	return guesses
+}
+
+// sequenceGuesses: sequences starting at an obvious character ("abc", "123",
+// "zyx") are tried first.
+func sequenceGuesses(m *match) float64 {
+	first := []rune(m.token)[0]
+
+	var base float64
+	switch {
+	case strings.ContainsRune("aAzZ019", first):
+		base = 4
+	case first >= '0' && first <= '9':
+		base = 10
+	default:
+		// letters, and unicode which is unusual enough to be treated alike
+		base = 26
+	}
+	if !m.ascending {
+		base *= 2
+	}
+	return base * float64(len([]rune(m.token)))
+}
+
+func dateGuesses(m *match) float64 {
+	guesses := float64(yearSpace(m.year) * 365)
+	if m.separator != "" {
+		guesses *= 4
+	}
+	return guesses
+}
+
+func yearSpace(year int) int {
+	return max(abs(year-referenceYear()), minYearSpace)
+}
+
+// ---------------------------------------------------------------------------
+// helpers
+
+func startUpper(r []rune) bool {
+	if len(r) < 2 || !unicode.IsUpper(r[0]) {
+		return false
+	}
+	for _, c := range r[1:] {
+		if unicode.IsUpper(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func endUpper(r []rune) bool {
+	if len(r) < 2 || !unicode.IsUpper(r[len(r)-1]) {
+		return false
+	}
+	for _, c := range r[:len(r)-1] {
+		if unicode.IsUpper(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func nCk(n, k int) float64 {
+	if k > n {
+		return 0
+	}
+	if k == 0 {
+		return 1
+	}
+	r := 1.0
+	for d := 1; d <= k; d++ {
+		r *= float64(n)
+		r /= float64(d)
+		n--
+	}
+	return r
+}
+
+func factorial(n int) float64 {
+	f := 1.0
+	for i := 2; i <= n; i++ {
+		f *= float64(i)
+	}
+	return f
+}
+
+func sortedKeys(m map[int]sequenceStep) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -0,0 +1,12 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+)
+
+type AccountLockEventRepository interface {
+	Create(ctx context.Context, event *auth.AccountLockEvent) error
+	GetRecentByUser(ctx context.Context, userID uint64, limit int) ([]*auth.AccountLockEvent, error)
+}
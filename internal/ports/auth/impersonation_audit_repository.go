@@ -0,0 +1,15 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+)
+
+type ImpersonationAuditRepository interface {
+	Create(ctx context.Context, event *auth.ImpersonationAuditEvent) error
+	GetRecentBySubject(ctx context.Context, subjectID uint64, limit int) ([]*auth.ImpersonationAuditEvent, error)
+
+	// IsTokenStopped: sudah ada event stop untuk token (jti) ini
+	IsTokenStopped(ctx context.Context, tokenID string) (bool, error)
+}
@@ -2,6 +2,9 @@ package auth
 
 import (
 	"context"
+	"time"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
 )
 
 // type LoginAttemptRepository interface {
@@ -10,8 +13,29 @@ import (
 // 	CountFailedByEmail(ctx context.Context, email string, sinceMinutes int) (int, error)
 // }
 
+// LoginAttemptFilter: field kosong/nil = tidak difilter
+type LoginAttemptFilter struct {
+	UserID    *uint64
+	IPAddress string
+	Success   *bool
+	Reason    string
+	From      *time.Time
+	To        *time.Time
+
+	Limit  int
+	Offset int
+}
+
 type LoginAttemptRepository interface {
-	IsRateLimited(ctx context.Context, identifier string) bool
-	RecordFailedAttempt(ctx context.Context, identifier string) error
-	ResetAttempts(ctx context.Context, identifier string) error
+	// Record menyimpan setiap attempt (sukses maupun gagal); history tidak pernah dihapus
+	Record(ctx context.Context, attempt *auth.LoginAttempt) error
+
+	// CountFailures* mengembalikan jumlah kegagalan yang dihitung untuk lockout
+	// sejak `since` beserta waktu gagal terakhir (zero jika tidak ada).
+	// Per identifier hitungan dimulai ulang setelah login sukses terakhir.
+	CountFailuresByIdentifier(ctx context.Context, identifier string, since time.Time) (int, time.Time, error)
+	CountFailuresByIP(ctx context.Context, ipAddress string, since time.Time) (int, time.Time, error)
+
+	// List mengembalikan attempt terbaru lebih dulu beserta total tanpa paging
+	List(ctx context.Context, filter LoginAttemptFilter) ([]*auth.LoginAttempt, int64, error)
 }
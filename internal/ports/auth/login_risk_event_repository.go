@@ -0,0 +1,13 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+)
+
+type LoginRiskEventRepository interface {
+	Create(ctx context.Context, event *auth.LoginRiskEvent) error
+	MarkCompleted(ctx context.Context, id uint64) error
+	GetRecentByUser(ctx context.Context, userID uint64, limit int) ([]*auth.LoginRiskEvent, error)
+}
@@ -8,6 +8,19 @@ import (
 
 type PasswordResetTokenRepository interface {
 	Create(ctx context.Context, token *auth.PasswordResetToken) error
+
+	// GetByTokenHash return nil, nil jika tidak ada
 	GetByTokenHash(ctx context.Context, hash string) (*auth.PasswordResetToken, error)
-	MarkUsed(ctx context.Context, id uint64) error
+
+	// GetLatestActiveByUser token terbaru yang belum dipakai & belum expired (metode OTP)
+	GetLatestActiveByUser(ctx context.Context, userID uint64) (*auth.PasswordResetToken, error)
+
+	// IncrementAttempts return jumlah percobaan terbaru
+	IncrementAttempts(ctx context.Context, id uint64) (int, error)
+
+	// MarkUsed atomik: false jika token sudah dipakai sebelumnya (request paralel)
+	MarkUsed(ctx context.Context, id uint64) (bool, error)
+
+	// InvalidateByUser tandai semua token aktif user sebagai terpakai
+	InvalidateByUser(ctx context.Context, userID uint64) error
 }
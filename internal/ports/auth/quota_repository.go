@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+)
+
+type QuotaPlanRepository interface {
+	// GetByRoleID mengembalikan nil, nil jika role belum punya plan
+	GetByRoleID(ctx context.Context, roleID uint64) (*auth.QuotaPlan, error)
+	List(ctx context.Context) ([]*auth.QuotaPlan, error)
+
+	// Upsert per role (satu role satu plan)
+	Upsert(ctx context.Context, plan *auth.QuotaPlan) error
+	DeleteByRoleID(ctx context.Context, roleID uint64) error
+}
+
+type QuotaOverrideRepository interface {
+	// GetBySubject mengembalikan nil, nil jika tidak ada override
+	GetBySubject(ctx context.Context, subjectType, subjectID string) (*auth.QuotaOverride, error)
+
+	Upsert(ctx context.Context, override *auth.QuotaOverride) error
+	Delete(ctx context.Context, subjectType, subjectID string) error
+}
+
+type QuotaUsageRepository interface {
+	// GetCount: 0 jika belum ada usage untuk periode tersebut
+	GetCount(ctx context.Context, subjectType, subjectID, period string, periodStart time.Time) (int64, error)
+
+	// Upsert menyimpan count terbesar (flush tidak boleh menurunkan usage)
+	Upsert(ctx context.Context, usages []*auth.QuotaUsage) error
+}
@@ -0,0 +1,9 @@
+package auth
+
+import "context"
+
+// SecondFactorVerifier memverifikasi kode authenticator app (TOTP) milik user
+type SecondFactorVerifier interface {
+	IsEnrolled(ctx context.Context, userID uint64) (bool, error)
+	Verify(ctx context.Context, userID uint64, code string) (bool, error)
+}
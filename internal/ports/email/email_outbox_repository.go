@@ -0,0 +1,32 @@
+package email
+
+import (
+	"context"
+	"time"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+)
+
+type EmailOutboxRepository interface {
+	// Create ikut transaksi di ctx (TransactionManager); pesan dengan
+	// idempotency key yang sudah ada diabaikan → created=false
+	Create(ctx context.Context, msg *auth.EmailOutboxMessage) (created bool, err error)
+
+	// ClaimDue ambil pesan yang jatuh tempo (termasuk lease yang kedaluwarsa)
+	// dan tandai processing sampai leaseUntil; aman dipakai banyak worker.
+	// Lease kedaluwarsa yang diambil ulang menambah Attempts.
+	ClaimDue(ctx context.Context, now time.Time, limit int, leaseUntil time.Time) ([]*auth.EmailOutboxMessage, error)
+
+	// MarkSent / MarkFailed hanya berlaku selama lease dari ClaimDue (msg.NextAttemptAt)
+	// masih dipegang; updated=false jika pesan sudah diambil ulang worker lain
+	MarkSent(ctx context.Context, id uint64, leaseUntil, sentAt time.Time) (updated bool, err error)
+	MarkFailed(ctx context.Context, id uint64, leaseUntil time.Time, attempts int, nextAttemptAt time.Time, lastError string, dead bool) (updated bool, err error)
+
+	// nil jika tidak ada
+	GetByID(ctx context.Context, id uint64) (*auth.EmailOutboxMessage, error)
+	// status kosong = semua, terbaru dulu
+	List(ctx context.Context, status string, limit, offset int) ([]*auth.EmailOutboxMessage, error)
+
+	// Replay kembalikan pesan ke antrian dengan attempts direset
+	Replay(ctx context.Context, id uint64, now time.Time) error
+}
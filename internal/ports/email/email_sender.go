@@ -1,5 +1,7 @@
 package email
 
+import "time"
+
 // type EmailSender interface {
 // 	SendOTP(to string, otp string) error
 // }
@@ -7,6 +9,83 @@ package email
 // internal/ports/email/sender.go
 
 type EmailSender interface {
-	SendOTP(to string, otp string) error
-	SendResetPassword(to string, otp string) error
+	SendOTP(to Recipient, otp string) error
+	SendResetPassword(to Recipient, notice PasswordReset) error
+	SendPasswordChanged(to Recipient, notice PasswordChangedNotice) error
+	SendPasswordExpiry(to Recipient, notice PasswordExpiryNotice) error
+	SendLoginAlert(to Recipient, alert LoginAlert) error
+	SendAccountLocked(to Recipient, notice AccountLockedNotice) error
+	SendEmailChangeVerification(to Recipient, notice EmailChangeVerification) error
+	SendEmailChangeNotice(to Recipient, notice EmailChangeNotice) error
+	SendEmailVerification(to Recipient, notice EmailVerification) error
+	SendInvitation(to Recipient, invitation Invitation) error
+}
+
+// Recipient: alamat tujuan + bahasa template (User.Locale);
+// Locale kosong = EMAIL_LOCALE
+type Recipient struct {
+	Email  string
+	Locale string
+}
+
+// LoginAlert berisi detail sign-in dari device/lokasi yang belum dikenal
+type LoginAlert struct {
+	IPAddress string
+	UserAgent string
+	Location  string
+	Time      time.Time
+}
+
+// AccountLockedNotice dikirim saat akun terkunci otomatis karena terlalu banyak login gagal
+type AccountLockedNotice struct {
+	LockedUntil *time.Time // nil = sampai di-unlock
+	UnlockURL   string     // link self-service unlock (sekali pakai)
+}
+
+// EmailChangeVerification dikirim ke alamat baru; email baru dipakai setelah link dibuka
+type EmailChangeVerification struct {
+	VerifyURL string
+	ExpiresAt time.Time
+}
+
+// EmailChangeNotice dikirim ke alamat lama saat ada permintaan ganti email
+type EmailChangeNotice struct {
+	NewEmail  string // sudah di-mask
+	RevertURL string // batalkan / kembalikan ke alamat lama
+	ExpiresAt time.Time
+}
+
+// EmailVerification dikirim setelah registrasi; isinya link atau kode OTP
+// tergantung metode verifikasi yang dipakai
+type EmailVerification struct {
+	VerifyURL string // kosong jika metode OTP
+	Code      string // kosong jika metode link
+	ExpiresAt time.Time
+}
+
+// PasswordReset: link atau kode OTP tergantung metode reset (PASSWORD_RESET_METHOD)
+type PasswordReset struct {
+	ResetURL  string // kosong jika metode OTP
+	Code      string // kosong jika metode link
+	ExpiresAt time.Time
+}
+
+// PasswordChangedNotice dikirim setelah password diganti / di-reset
+type PasswordChangedNotice struct {
+	IPAddress string
+	UserAgent string
+	Time      time.Time
+}
+
+// PasswordExpiryNotice dikirim sebelum password mencapai umur maksimal
+type PasswordExpiryNotice struct {
+	ExpiresAt time.Time
+	DaysLeft  int
+}
+
+// Invitation: undangan membuat akun dari user lain / admin
+type Invitation struct {
+	InviterName string
+	AcceptURL   string
+	ExpiresAt   time.Time
 }
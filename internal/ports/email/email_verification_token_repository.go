@@ -9,5 +9,15 @@ import (
 type EmailVerificationTokenRepository interface {
 	Create(ctx context.Context, token *auth.EmailVerificationToken) error
 	GetByTokenHash(ctx context.Context, hash string) (*auth.EmailVerificationToken, error)
+
+	// token terbaru per purpose, nil jika belum ada (dipakai untuk cooldown resend)
+	GetLatestByUserAndPurpose(ctx context.Context, userID uint64, purpose string) (*auth.EmailVerificationToken, error)
 	DeleteByUser(ctx context.Context, userID uint64) error
+
+	// IncrementAttempts catat satu kode salah, return jumlah percobaan terbaru
+	IncrementAttempts(ctx context.Context, id uint64) (int, error)
+
+	// token sekali pakai
+	Delete(ctx context.Context, id uint64) error
+	DeleteByUserAndPurpose(ctx context.Context, userID uint64, purpose string) error
 }
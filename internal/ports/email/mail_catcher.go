@@ -0,0 +1,22 @@
+package email
+
+import "time"
+
+// CapturedEmail adalah email keluar yang ditahan mail catcher (development)
+type CapturedEmail struct {
+	ID      uint64
+	From    string
+	To      []string
+	Subject string
+	HTML    string
+	Text    string
+	SentAt  time.Time
+}
+
+// MailCatcher menyimpan email keluar terbaru di memory, hanya untuk development / e2e test
+type MailCatcher interface {
+	// Messages urut terbaru dulu
+	Messages() []CapturedEmail
+	Get(id uint64) (CapturedEmail, bool)
+	Reset()
+}
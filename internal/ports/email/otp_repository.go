@@ -16,11 +16,22 @@ import (
 	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
 )
 
+// EmailOTPRepository: maksimal satu OTP aktif per (email, purpose).
+// Implementasi: Postgres, Redis dan memory; perilakunya dicek otptest.RunConformance
 type EmailOTPRepository interface {
+	// Save menggantikan OTP sebelumnya untuk email + purpose yang sama, mengisi otp.ID
 	Save(ctx context.Context, otp *domain.EmailOTP) error
+
+	// FindActiveByEmail return nil, nil jika tidak ada / sudah expired
 	FindActiveByEmail(
 		ctx context.Context,
-		email string,
+		email, purpose string,
 	) (*domain.EmailOTP, error)
-	DeleteByEmail(ctx context.Context, email string) error
+
+	// IncrementAttempts catat satu percobaan gagal, return jumlah percobaan terbaru
+	// (0 jika tidak ada OTP aktif)
+	IncrementAttempts(ctx context.Context, email, purpose string) (int, error)
+
+	// DeleteByEmail invalidasi OTP (setelah berhasil dipakai / percobaan habis)
+	DeleteByEmail(ctx context.Context, email, purpose string) error
 }
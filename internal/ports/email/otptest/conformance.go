@@ -0,0 +1,234 @@
+// Package otptest checks that an EmailOTPRepository implementation behaves the
+// way the OTP usecase expects. Every implementation (Postgres, Redis, memory)
+// should pass the same suite:
+//
+//	func TestRedisOTPRepository(t *testing.T) {
+//		otptest.RunConformance(t, func(t *testing.T) ports.EmailOTPRepository {
+//			return email.NewRedisOTPRepository(client)
+//		})
+//	}
+//
+// Each subtest uses its own email address, so a shared database or Redis
+// instance does not need to be cleaned between runs.
+package otptest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/email"
+)
+
+func RunConformance(t *testing.T, newRepo func(t *testing.T) ports.EmailOTPRepository) {
+	t.Helper()
+
+	cases := []struct {
+		name string
+		run  func(t *testing.T, repo ports.EmailOTPRepository, email string)
+	}{
+		{"SaveAndFind", testSaveAndFind},
+		{"FindMissing", testFindMissing},
+		{"SaveReplacesPrevious", testSaveReplacesPrevious},
+		{"PurposeIsolation", testPurposeIsolation},
+		{"ExpiredIsNotActive", testExpiredIsNotActive},
+		{"TTL", testTTL},
+		{"IncrementAttempts", testIncrementAttempts},
+		{"IncrementMissing", testIncrementMissing},
+		{"Delete", testDelete},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			email := fmt.Sprintf("%s-%d@otptest.local", strings.ToLower(tc.name), time.Now().UnixNano())
+			tc.run(t, newRepo(t), email)
+		})
+	}
+}
+
+func testSaveAndFind(t *testing.T, repo ports.EmailOTPRepository, email string) {
+	otp := newOTP(email, domain.EmailOTPPurposeSignup, "hash-1", time.Minute)
+	otp.IPAddress = "203.0.113.7"
+	otp.UserAgent = "otptest"
+	mustSave(t, repo, otp)
+
+	if otp.ID == 0 {
+		t.Fatal("Save did not assign an ID")
+	}
+
+	got := mustFind(t, repo, email, domain.EmailOTPPurposeSignup)
+	if got == nil {
+		t.Fatal("saved OTP not found")
+	}
+	if got.ID != otp.ID || got.OTPHash != "hash-1" || got.Purpose != domain.EmailOTPPurposeSignup {
+		t.Fatalf("got %+v, want ID %d, hash-1, purpose signup", got, otp.ID)
+	}
+	if got.Attempts != 0 {
+		t.Fatalf("new OTP has %d attempts, want 0", got.Attempts)
+	}
+	if got.IPAddress != otp.IPAddress || got.UserAgent != otp.UserAgent {
+		t.Fatalf("client info not stored: %+v", got)
+	}
+	if d := got.ExpiredAt.Sub(otp.ExpiredAt); d > time.Millisecond || d < -time.Millisecond {
+		t.Fatalf("ExpiredAt = %v, want %v", got.ExpiredAt, otp.ExpiredAt)
+	}
+}
+
+func testFindMissing(t *testing.T, repo ports.EmailOTPRepository, email string) {
+	if got := mustFind(t, repo, email, domain.EmailOTPPurposeLogin); got != nil {
+		t.Fatalf("expected nil for unknown email, got %+v", got)
+	}
+}
+
+func testSaveReplacesPrevious(t *testing.T, repo ports.EmailOTPRepository, email string) {
+	first := newOTP(email, domain.EmailOTPPurposeLogin, "old", time.Minute)
+	mustSave(t, repo, first)
+	mustIncrement(t, repo, email, domain.EmailOTPPurposeLogin, 1)
+
+	second := newOTP(email, domain.EmailOTPPurposeLogin, "new", time.Minute)
+	mustSave(t, repo, second)
+
+	if second.ID == first.ID {
+		t.Fatal("replacement OTP reused the previous ID")
+	}
+
+	got := mustFind(t, repo, email, domain.EmailOTPPurposeLogin)
+	if got == nil || got.OTPHash != "new" {
+		t.Fatalf("expected the replacement OTP, got %+v", got)
+	}
+	if got.Attempts != 0 {
+		t.Fatalf("replacement OTP inherited %d attempts", got.Attempts)
+	}
+}
+
+func testPurposeIsolation(t *testing.T, repo ports.EmailOTPRepository, email string) {
+	mustSave(t, repo, newOTP(email, domain.EmailOTPPurposeSignup, "signup", time.Minute))
+	mustSave(t, repo, newOTP(email, domain.EmailOTPPurposePasswordReset, "reset", time.Minute))
+
+	mustIncrement(t, repo, email, domain.EmailOTPPurposePasswordReset, 1)
+
+	signup := mustFind(t, repo, email, domain.EmailOTPPurposeSignup)
+	reset := mustFind(t, repo, email, domain.EmailOTPPurposePasswordReset)
+	if signup == nil || signup.OTPHash != "signup" || signup.Attempts != 0 {
+		t.Fatalf("signup OTP affected by another purpose: %+v", signup)
+	}
+	if reset == nil || reset.OTPHash != "reset" || reset.Attempts != 1 {
+		t.Fatalf("password_reset OTP = %+v, want hash reset with 1 attempt", reset)
+	}
+
+	if got := mustFind(t, repo, email, domain.EmailOTPPurposeEmailChange); got != nil {
+		t.Fatalf("found OTP for a purpose that was never saved: %+v", got)
+	}
+}
+
+func testExpiredIsNotActive(t *testing.T, repo ports.EmailOTPRepository, email string) {
+	mustSave(t, repo, newOTP(email, domain.EmailOTPPurposeSignup, "expired", -time.Second))
+
+	if got := mustFind(t, repo, email, domain.EmailOTPPurposeSignup); got != nil {
+		t.Fatalf("expired OTP returned as active: %+v", got)
+	}
+	mustIncrement(t, repo, email, domain.EmailOTPPurposeSignup, 0)
+}
+
+func testTTL(t *testing.T, repo ports.EmailOTPRepository, email string) {
+	mustSave(t, repo, newOTP(email, domain.EmailOTPPurposeLogin, "short", 200*time.Millisecond))
+
+	if got := mustFind(t, repo, email, domain.EmailOTPPurposeLogin); got == nil {
+		t.Fatal("OTP missing before its TTL")
+	}
+
+	time.Sleep(300 * time.Millisecond)
+
+	if got := mustFind(t, repo, email, domain.EmailOTPPurposeLogin); got != nil {
+		t.Fatalf("OTP still active after its TTL: %+v", got)
+	}
+	mustIncrement(t, repo, email, domain.EmailOTPPurposeLogin, 0)
+}
+
+func testIncrementAttempts(t *testing.T, repo ports.EmailOTPRepository, email string) {
+	mustSave(t, repo, newOTP(email, domain.EmailOTPPurposeEmailChange, "hash", time.Minute))
+
+	for want := 1; want <= 3; want++ {
+		mustIncrement(t, repo, email, domain.EmailOTPPurposeEmailChange, want)
+	}
+
+	got := mustFind(t, repo, email, domain.EmailOTPPurposeEmailChange)
+	if got == nil || got.Attempts != 3 {
+		t.Fatalf("attempts not persisted: %+v", got)
+	}
+}
+
+func testIncrementMissing(t *testing.T, repo ports.EmailOTPRepository, email string) {
+	mustIncrement(t, repo, email, domain.EmailOTPPurposeSignup, 0)
+
+	if got := mustFind(t, repo, email, domain.EmailOTPPurposeSignup); got != nil {
+		t.Fatalf("IncrementAttempts created an OTP: %+v", got)
+	}
+}
+
+func testDelete(t *testing.T, repo ports.EmailOTPRepository, email string) {
+	ctx := context.Background()
+
+	mustSave(t, repo, newOTP(email, domain.EmailOTPPurposeSignup, "signup", time.Minute))
+	mustSave(t, repo, newOTP(email, domain.EmailOTPPurposeLogin, "login", time.Minute))
+
+	if err := repo.DeleteByEmail(ctx, email, domain.EmailOTPPurposeSignup); err != nil {
+		t.Fatalf("DeleteByEmail: %v", err)
+	}
+
+	if got := mustFind(t, repo, email, domain.EmailOTPPurposeSignup); got != nil {
+		t.Fatalf("deleted OTP still active: %+v", got)
+	}
+	mustIncrement(t, repo, email, domain.EmailOTPPurposeSignup, 0)
+
+	if got := mustFind(t, repo, email, domain.EmailOTPPurposeLogin); got == nil {
+		t.Fatal("DeleteByEmail removed an OTP with another purpose")
+	}
+
+	// idempotent
+	if err := repo.DeleteByEmail(ctx, email, domain.EmailOTPPurposeSignup); err != nil {
+		t.Fatalf("second DeleteByEmail: %v", err)
+	}
+}
+
+// ================= HELPERS =================
+
+func newOTP(email, purpose, hash string, ttl time.Duration) *domain.EmailOTP {
+	return &domain.EmailOTP{
+		Email:     email,
+		Purpose:   purpose,
+		OTPHash:   hash,
+		ExpiredAt: time.Now().Add(ttl),
+	}
+}
+
+func mustSave(t *testing.T, repo ports.EmailOTPRepository, otp *domain.EmailOTP) {
+	t.Helper()
+	if err := repo.Save(context.Background(), otp); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+}
+
+func mustFind(t *testing.T, repo ports.EmailOTPRepository, email, purpose string) *domain.EmailOTP {
+	t.Helper()
+	otp, err := repo.FindActiveByEmail(context.Background(), email, purpose)
+	if err != nil {
+		t.Fatalf("FindActiveByEmail: %v", err)
+	}
+	return otp
+}
+
+func mustIncrement(t *testing.T, repo ports.EmailOTPRepository, email, purpose string, want int) {
+	t.Helper()
+	got, err := repo.IncrementAttempts(context.Background(), email, purpose)
+	if err != nil {
+		t.Fatalf("IncrementAttempts: %v", err)
+	}
+	if got != want {
+		t.Fatalf("IncrementAttempts = %d, want %d", got, want)
+	}
+}
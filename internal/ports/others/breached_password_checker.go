@@ -0,0 +1,10 @@
+package others
+
+import "context"
+
+// BreachedPasswordChecker mencari password di data breach (Pwned Passwords / HIBP).
+// Implementasi hanya memakai 5 karakter awal SHA-1 untuk lookup (k-anonymity)
+type BreachedPasswordChecker interface {
+	// Count berapa kali password muncul di data breach, 0 = tidak ditemukan
+	Count(ctx context.Context, password string) (int, error)
+}
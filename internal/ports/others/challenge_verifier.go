@@ -0,0 +1,15 @@
+package others
+
+import (
+	"context"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+)
+
+type ChallengeVerifier interface {
+	// Issue membuat challenge baru untuk client
+	Issue(ctx context.Context) (*valueobjects.Challenge, error)
+
+	// Verify memeriksa jawaban challenge dari client (single-use)
+	Verify(ctx context.Context, solution, remoteIP string) (bool, error)
+}
@@ -0,0 +1,9 @@
+package others
+
+import "github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+
+// GeoIPResolver mencari lokasi dari alamat IP (offline database)
+type GeoIPResolver interface {
+	// Lookup mengembalikan nil jika IP tidak ditemukan di database
+	Lookup(ip string) (*valueobjects.GeoLocation, error)
+}
@@ -0,0 +1,7 @@
+package others
+
+import "github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+
+type IPReputationChecker interface {
+	Check(ip string) valueobjects.IPReputation
+}
@@ -0,0 +1,8 @@
+package others
+
+import "context"
+
+// MessageChannel mengirim pesan teks pendek (SMS, WhatsApp) ke nomor E.164
+type MessageChannel interface {
+	Send(ctx context.Context, to, text string) error
+}
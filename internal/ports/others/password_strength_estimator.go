@@ -0,0 +1,10 @@
+package others
+
+import "github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+
+// PasswordStrengthEstimator menilai password dari pola (kamus, keyboard walk,
+// tanggal, pengulangan, l33t), bukan dari aturan komposisi
+type PasswordStrengthEstimator interface {
+	// Estimate: userInputs = data terkait user (username, email) yang mudah ditebak
+	Estimate(password string, userInputs []string) valueobjects.PasswordStrength
+}
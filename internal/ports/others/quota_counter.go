@@ -0,0 +1,18 @@
+package others
+
+import (
+	"context"
+	"time"
+)
+
+// QuotaCounter adalah counter usage cepat (Redis / memory) yang
+// di-flush berkala ke Postgres
+type QuotaCounter interface {
+	// Increment menambah counter dan mengembalikan nilai baru.
+	// created = true jika key baru dibuat (perlu seed dari Postgres).
+	Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (value int64, created bool, err error)
+	Get(ctx context.Context, key string) (int64, error)
+
+	// PopDirty mengambil key yang berubah sejak flush terakhir
+	PopDirty(ctx context.Context, max int) ([]string, error)
+}
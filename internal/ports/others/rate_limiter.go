@@ -0,0 +1,12 @@
+package others
+
+import (
+	"context"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+)
+
+type RateLimiter interface {
+	// Allow mengonsumsi satu request untuk key di bawah policy secara atomik
+	Allow(ctx context.Context, key string, policy valueobjects.RateLimitPolicy) (*valueobjects.RateLimitResult, error)
+}
@@ -1,12 +1,47 @@
 package users
 
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrPasswordHasherBusy: antrian hashing penuh sampai timeout, request boleh diulang (503)
+var ErrPasswordHasherBusy = errors.New("password hashing is busy, please retry")
+
 // PasswordHasher mendefinisikan kontrak untuk hashing dan verifikasi password
 type PasswordHasher interface {
 	// HashPassword menghasilkan hash password dengan pepper versi saat ini
 	// HashPassword(password []byte) (hashed string, pepperVersion int, err error)
-	HashPassword(password []byte) (string, error)
+	HashPassword(ctx context.Context, password []byte) (string, error)
 
 	// VerifyPassword memverifikasi password terhadap hash yang sudah ada
 	// Mengembalikan (match bool, shouldRehash bool, err error)
-	VerifyPassword(password []byte, hashed string) (bool, bool, error)
+	VerifyPassword(ctx context.Context, password []byte, hashed string) (bool, bool, error)
+}
+
+// PasswordHashingMonitor: metrik pool hashing (memory terpakai, antrian, waktu tunggu, penolakan)
+type PasswordHashingMonitor interface {
+	Stats() PasswordHashingStats
+}
+
+type PasswordHashingStats struct {
+	MemoryBudget uint64 // KiB
+	MemoryInUse  uint64 // KiB
+	Running      int64
+	Waiting      int64
+
+	Acquired uint64
+	Rejected uint64 // timeout antrian → ErrPasswordHasherBusy
+	Canceled uint64 // request selesai / dibatalkan saat masih antri
+
+	WaitTotal time.Duration
+	WaitMax   time.Duration
+	// WaitBuckets kumulatif (seperti histogram Prometheus): Count = acquire dengan tunggu <= Le
+	WaitBuckets []PasswordHashingWaitBucket
+}
+
+type PasswordHashingWaitBucket struct {
+	Le    time.Duration
+	Count uint64
 }
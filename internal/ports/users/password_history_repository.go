@@ -0,0 +1,17 @@
+package users
+
+import (
+	"context"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+)
+
+type PasswordHistoryRepository interface {
+	Create(ctx context.Context, entry *auth.PasswordHistory) error
+
+	// GetRecent: terbaru dulu, maksimal limit entry
+	GetRecent(ctx context.Context, userID uint64, limit int) ([]*auth.PasswordHistory, error)
+
+	// Prune hapus entry di luar keep entry terbaru
+	Prune(ctx context.Context, userID uint64, keep int) error
+}
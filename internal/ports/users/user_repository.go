@@ -2,6 +2,7 @@ package users
 
 import (
 	"context"
+	"time"
 
 	"github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
 )
@@ -30,11 +31,34 @@ type UserRepository interface {
 
 	Create(ctx context.Context, user *auth.User) error
 	Update(ctx context.Context, user *auth.User) error
+	// UpdatePassword: password baru, umur password dihitung ulang dari sekarang
 	UpdatePassword(ctx context.Context, id uint64, hashedPassword string) error
+	// RehashPassword: password sama dengan parameter hash / pepper baru, umur password tetap
+	RehashPassword(ctx context.Context, id uint64, hashedPassword string) error
+	SetMustChangePassword(ctx context.Context, id uint64, must bool) error
 	UpdateUsername(ctx context.Context, id uint64, hashedPassword string) error
+	UpdateEmail(ctx context.Context, id uint64, email string, verified bool) error
 
 	ExistsByUsernameExceptID(ctx context.Context, username string, exceptID uint64) (bool, error)
 	ExistsByEmailExceptID(ctx context.Context, email string, exceptID uint64) (bool, error)
 
 	SoftDelete(ctx context.Context, id uint64) error
+
+	// Lockout
+	Lock(ctx context.Context, id uint64, reason string, until *time.Time, unlockTokenHash *string) error
+	Unlock(ctx context.Context, id uint64) error
+	GetByUnlockTokenHash(ctx context.Context, hash string) (*auth.User, error)
+
+	// Paksa ganti password (admin, bulk) & expiry password
+	SetMustChangePasswordByIDs(ctx context.Context, ids []uint64) (int64, error)
+	SetMustChangePasswordAll(ctx context.Context, exceptID uint64) (int64, error)
+	// FlagExpiredPasswords: must_change_password = true untuk password yang diganti sebelum changedBefore
+	FlagExpiredPasswords(ctx context.Context, changedBefore time.Time) (int64, error)
+	// GetPasswordExpiring: password diganti sebelum changedBefore, belum diberi peringatan & belum dipaksa ganti
+	GetPasswordExpiring(ctx context.Context, changedBefore time.Time, limit int) ([]*auth.User, error)
+	MarkPasswordExpiryWarned(ctx context.Context, id uint64, at time.Time) error
+
+	// Phone / channel notifikasi
+	UpdatePhone(ctx context.Context, id uint64, phone *string, verified bool, preferredChannel string) error
+	UpdatePreferredChannel(ctx context.Context, id uint64, channel string) error
 }
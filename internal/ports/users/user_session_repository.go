@@ -13,4 +13,6 @@ type UserSessionRepository interface {
 	Logout(ctx context.Context, id uint64) error
 
 	GetActiveSessions(ctx context.Context, userID uint64) ([]*auth.UserSession, error)
+	// GetRecentByUser termasuk session yang sudah logout (riwayat device/IP)
+	GetRecentByUser(ctx context.Context, userID uint64, limit int) ([]*auth.UserSession, error)
 }
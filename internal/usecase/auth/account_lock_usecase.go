@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	authPorts "github.com/dhanarrizky/Golang-template/internal/ports/auth"
+	otherPorts "github.com/dhanarrizky/Golang-template/internal/ports/others"
+	userPorts "github.com/dhanarrizky/Golang-template/internal/ports/users"
+)
+
+var (
+	ErrLockUserNotFound     = errors.New("user not found")
+	ErrInvalidUnlockToken   = errors.New("invalid or expired unlock link")
+	ErrCannotLockSelf       = errors.New("cannot lock your own account")
+	ErrAccountNotLocked     = errors.New("account is not locked")
+	ErrLockUserIDDecode     = errors.New("internal server decode")
+	ErrLockReasonIsRequired = errors.New("reason is required")
+)
+
+type AccountLockUsecase interface {
+	// Lock oleh admin; duration 0 = terkunci sampai di-unlock manual.
+	// Lock admin tidak bisa dibuka lewat link email.
+	Lock(ctx context.Context, actorID, userID, reason string, duration time.Duration) error
+	Unlock(ctx context.Context, actorID, userID, reason string) error
+
+	// UnlockWithToken: self-service dari link di email lockout
+	UnlockWithToken(ctx context.Context, token string) error
+}
+
+type accountLockUsecase struct {
+	userRepo      userPorts.UserRepository
+	lockEventRepo authPorts.AccountLockEventRepository
+	tokenVerifier otherPorts.TokenVerifier
+	idCodec       otherPorts.PublicIDCodec
+}
+
+func NewAccountLockUsecase(
+	userRepo userPorts.UserRepository,
+	lockEventRepo authPorts.AccountLockEventRepository,
+	tokenVerifier otherPorts.TokenVerifier,
+	idCodec otherPorts.PublicIDCodec,
+) AccountLockUsecase {
+	return &accountLockUsecase{
+		userRepo:      userRepo,
+		lockEventRepo: lockEventRepo,
+		tokenVerifier: tokenVerifier,
+		idCodec:       idCodec,
+	}
+}
+
+// ================= ADMIN LOCK =================
+
+func (u *accountLockUsecase) Lock(
+	ctx context.Context,
+	actorID, userID, reason string,
+	duration time.Duration,
+) error {
+
+	if reason == "" {
+		return ErrLockReasonIsRequired
+	}
+
+	actor, user, err := u.resolve(ctx, actorID, userID)
+	if err != nil {
+		return err
+	}
+
+	if actor == user.ID {
+		return ErrCannotLockSelf
+	}
+
+	var until *time.Time
+	if duration > 0 {
+		t := time.Now().Add(duration)
+		until = &t
+	}
+
+	if err := u.userRepo.Lock(ctx, user.ID, reason, until, nil); err != nil {
+		return err
+	}
+
+	return u.lockEventRepo.Create(ctx, &domain.AccountLockEvent{
+		UserID:      user.ID,
+		ActorID:     &actor,
+		Action:      domain.AccountLockActionLock,
+		Reason:      reason,
+		LockedUntil: until,
+	})
+}
+
+// ================= ADMIN UNLOCK =================
+
+func (u *accountLockUsecase) Unlock(
+	ctx context.Context,
+	actorID, userID, reason string,
+) error {
+
+	if reason == "" {
+		return ErrLockReasonIsRequired
+	}
+
+	actor, user, err := u.resolve(ctx, actorID, userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.Locked {
+		return ErrAccountNotLocked
+	}
+
+	return u.unlock(ctx, user, &actor, reason)
+}
+
+// ================= SELF-SERVICE UNLOCK =================
+
+func (u *accountLockUsecase) UnlockWithToken(ctx context.Context, token string) error {
+	user, err := u.userRepo.GetByUnlockTokenHash(ctx, u.tokenVerifier.Hash(token))
+	if err != nil || user == nil {
+		return ErrInvalidUnlockToken
+	}
+
+	return u.unlock(ctx, user, nil, UnlockReasonEmailLink)
+}
+
+func (u *accountLockUsecase) unlock(
+	ctx context.Context,
+	user *domain.User,
+	actor *uint64,
+	reason string,
+) error {
+
+	if err := u.userRepo.Unlock(ctx, user.ID); err != nil {
+		return err
+	}
+
+	// event unlock juga jadi titik awal hitungan lockout berikutnya
+	return u.lockEventRepo.Create(ctx, &domain.AccountLockEvent{
+		UserID:  user.ID,
+		ActorID: actor,
+		Action:  domain.AccountLockActionUnlock,
+		Reason:  reason,
+	})
+}
+
+func (u *accountLockUsecase) resolve(
+	ctx context.Context,
+	actorID, userID string,
+) (uint64, *domain.User, error) {
+
+	actor, err := u.idCodec.Decode(actorID)
+	if err != nil {
+		return 0, nil, ErrLockUserIDDecode
+	}
+
+	id, err := u.idCodec.Decode(userID)
+	if err != nil {
+		return 0, nil, ErrLockUserNotFound
+	}
+
+	user, err := u.userRepo.GetByID(ctx, id)
+	if err != nil || user == nil {
+		return 0, nil, ErrLockUserNotFound
+	}
+
+	return actor, user, nil
+}
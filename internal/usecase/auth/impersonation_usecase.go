@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	authPorts "github.com/dhanarrizky/Golang-template/internal/ports/auth"
+	otherPorts "github.com/dhanarrizky/Golang-template/internal/ports/others"
+	rolePorts "github.com/dhanarrizky/Golang-template/internal/ports/roles"
+	userPorts "github.com/dhanarrizky/Golang-template/internal/ports/users"
+	"github.com/dhanarrizky/Golang-template/pkg/utils"
+)
+
+// AdminRoleName adalah role yang tidak boleh di-impersonate
+const AdminRoleName = "admin"
+
+var (
+	ErrImpersonationTargetNotFound = errors.New("user not found")
+	ErrCannotImpersonateAdmin      = errors.New("cannot impersonate an admin")
+	ErrCannotImpersonateSelf       = errors.New("cannot impersonate yourself")
+	ErrNestedImpersonation         = errors.New("already impersonating")
+	ErrNotImpersonating            = errors.New("token is not an impersonation token")
+	ErrImpersonationIDDecode       = errors.New("internal server decode")
+)
+
+type ImpersonationResult struct {
+	AccessToken string
+	ExpiresAt   time.Time
+	ActorID     string
+	SubjectID   string
+}
+
+type ImpersonationUsecase interface {
+	// Start menerbitkan access token berumur pendek atas nama subject,
+	// dengan klaim "act" berisi admin yang bertindak (RFC 8693)
+	Start(ctx context.Context, actorID, subjectID, reason string, client LoginClient) (*ImpersonationResult, error)
+
+	// Stop mengakhiri sesi impersonation: event stop dengan jti token dicatat dan
+	// sejak itu token ditolak (lihat IsActive) walaupun belum exp
+	Stop(ctx context.Context, actorID, subjectID, tokenID string, client LoginClient) error
+
+	// IsActive false jika token sudah di-stop, atau tidak punya jti sehingga
+	// tidak bisa di-stop
+	IsActive(ctx context.Context, tokenID string) (bool, error)
+
+	// RecordRequest mencatat satu request yang dilakukan dengan token impersonation
+	RecordRequest(ctx context.Context, actorID, subjectID, method, path string, statusCode int, ipAddress, userAgent string) error
+}
+
+type impersonationUsecase struct {
+	userRepo    userPorts.UserRepository
+	roleRepo    rolePorts.RoleRepository
+	auditRepo   authPorts.ImpersonationAuditRepository
+	tokenSigner authPorts.TokenSigner
+	idCodec     otherPorts.PublicIDCodec
+	tokenTTL    time.Duration
+}
+
+func NewImpersonationUsecase(
+	userRepo userPorts.UserRepository,
+	roleRepo rolePorts.RoleRepository,
+	auditRepo authPorts.ImpersonationAuditRepository,
+	tokenSigner authPorts.TokenSigner,
+	idCodec otherPorts.PublicIDCodec,
+	tokenTTL time.Duration,
+) ImpersonationUsecase {
+	return &impersonationUsecase{
+		userRepo:    userRepo,
+		roleRepo:    roleRepo,
+		auditRepo:   auditRepo,
+		tokenSigner: tokenSigner,
+		idCodec:     idCodec,
+		tokenTTL:    tokenTTL,
+	}
+}
+
+// ================= START =================
+
+func (u *impersonationUsecase) Start(
+	ctx context.Context,
+	actorID, subjectID, reason string,
+	client LoginClient,
+) (*ImpersonationResult, error) {
+
+	actor, subject, err := u.decodePair(actorID, subjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if actor == subject {
+		return nil, ErrCannotImpersonateSelf
+	}
+
+	user, err := u.userRepo.GetByID(ctx, subject)
+	if err != nil || user == nil {
+		return nil, ErrImpersonationTargetNotFound
+	}
+
+	role, err := u.roleRepo.GetByID(ctx, user.RoleID)
+	if err != nil {
+		return nil, ErrRoleNotFound
+	}
+	if role.Name == AdminRoleName {
+		return nil, ErrCannotImpersonateAdmin
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(u.tokenTTL)
+
+	// jti dipakai untuk mencabut token saat Stop
+	tokenID := utils.GenerateUUID()
+
+	// tanpa auth_time → RequireFreshAuth selalu menolak operasi sensitif
+	claims := map[string]any{
+		"jti":   tokenID,
+		"email": user.Email,
+		"roles": user.RoleID,
+		"act":   map[string]any{"sub": actorID},
+		"exp":   expiresAt.Unix(),
+	}
+
+	accessToken, err := u.tokenSigner.GenerateAccessToken(subjectID, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.auditRepo.Create(ctx, &domain.ImpersonationAuditEvent{
+		ActorID:   actor,
+		SubjectID: subject,
+		Action:    domain.ImpersonationActionStart,
+		Reason:    reason,
+		TokenID:   tokenID,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+	})
+	if err != nil {
+		// tanpa jejak audit, token tidak boleh dikeluarkan
+		return nil, err
+	}
+
+	return &ImpersonationResult{
+		AccessToken: accessToken,
+		ExpiresAt:   expiresAt,
+		ActorID:     actorID,
+		SubjectID:   subjectID,
+	}, nil
+}
+
+// ================= STOP =================
+
+func (u *impersonationUsecase) Stop(
+	ctx context.Context,
+	actorID, subjectID, tokenID string,
+	client LoginClient,
+) error {
+
+	if actorID == "" || tokenID == "" {
+		return ErrNotImpersonating
+	}
+
+	actor, subject, err := u.decodePair(actorID, subjectID)
+	if err != nil {
+		return err
+	}
+
+	return u.auditRepo.Create(ctx, &domain.ImpersonationAuditEvent{
+		ActorID:   actor,
+		SubjectID: subject,
+		Action:    domain.ImpersonationActionStop,
+		TokenID:   tokenID,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+	})
+}
+
+func (u *impersonationUsecase) IsActive(ctx context.Context, tokenID string) (bool, error) {
+	if tokenID == "" {
+		return false, nil
+	}
+
+	stopped, err := u.auditRepo.IsTokenStopped(ctx, tokenID)
+	if err != nil {
+		return false, err
+	}
+
+	return !stopped, nil
+}
+
+// ================= RECORD REQUEST =================
+
+func (u *impersonationUsecase) RecordRequest(
+	ctx context.Context,
+	actorID, subjectID, method, path string,
+	statusCode int,
+	ipAddress, userAgent string,
+) error {
+
+	actor, subject, err := u.decodePair(actorID, subjectID)
+	if err != nil {
+		return err
+	}
+
+	return u.auditRepo.Create(ctx, &domain.ImpersonationAuditEvent{
+		ActorID:    actor,
+		SubjectID:  subject,
+		Action:     domain.ImpersonationActionRequest,
+		Method:     method,
+		Path:       path,
+		StatusCode: statusCode,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+	})
+}
+
+func (u *impersonationUsecase) decodePair(actorID, subjectID string) (uint64, uint64, error) {
+	actor, err := u.idCodec.Decode(actorID)
+	if err != nil {
+		return 0, 0, ErrImpersonationIDDecode
+	}
+
+	subject, err := u.idCodec.Decode(subjectID)
+	if err != nil {
+		return 0, 0, ErrImpersonationIDDecode
+	}
+
+	return actor, subject, nil
+}
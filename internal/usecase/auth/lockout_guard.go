@@ -0,0 +1,318 @@
+package auth
+
+import (
+	"context"
+	"log"
+	"time"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	authPorts "github.com/dhanarrizky/Golang-template/internal/ports/auth"
+	emailPorts "github.com/dhanarrizky/Golang-template/internal/ports/email"
+	otherPorts "github.com/dhanarrizky/Golang-template/internal/ports/others"
+	userPorts "github.com/dhanarrizky/Golang-template/internal/ports/users"
+	emailUC "github.com/dhanarrizky/Golang-template/internal/usecase/email"
+)
+
+const (
+	LockReasonTooManyFailures = "too many failed login attempts"
+	UnlockReasonCooldown      = "lock cooldown expired"
+	UnlockReasonEmailLink     = "unlocked via email link"
+
+	// batas shift supaya backoff tidak overflow
+	maxBackoffExponent = 20
+)
+
+// LoginThrottledError: login ditahan sementara (backoff), bukan akun terkunci
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string { return ErrTooManyAttempts.Error() }
+func (e *LoginThrottledError) Unwrap() error { return ErrTooManyAttempts }
+
+// AccountLockedError: akun terkunci (hard lock), LockedUntil nil = sampai di-unlock
+type AccountLockedError struct {
+	LockedUntil *time.Time
+}
+
+func (e *AccountLockedError) Error() string { return ErrAccountLocked.Error() }
+func (e *AccountLockedError) Unwrap() error { return ErrAccountLocked }
+
+type LockoutPolicy struct {
+	Window            time.Duration // jendela hitung login gagal
+	FreeAttempts      int           // gagal per identifier sebelum backoff dimulai
+	IPFreeAttempts    int           // gagal per IP (lintas akun) sebelum backoff dimulai
+	BaseDelay         time.Duration
+	MaxDelay          time.Duration
+	HardLockThreshold int           // gagal per identifier → akun dikunci
+	LockCooldown      time.Duration // 0 = terkunci sampai di-unlock manual
+	UnlockURL         string        // base URL link self-service unlock, token ditambahkan sebagai ?token=
+}
+
+func DefaultLockoutPolicy() LockoutPolicy {
+	return LockoutPolicy{
+		Window:            15 * time.Minute,
+		FreeAttempts:      3,
+		IPFreeAttempts:    20,
+		BaseDelay:         time.Second,
+		MaxDelay:          15 * time.Minute,
+		HardLockThreshold: 10,
+		LockCooldown:      30 * time.Minute,
+	}
+}
+
+// backoff: 0 selama masih dalam jatah gratis, lalu BaseDelay * 2^(n-free-1) sampai MaxDelay
+func (p LockoutPolicy) backoff(failures, free int) time.Duration {
+	if failures <= free {
+		return 0
+	}
+
+	exp := failures - free - 1
+	if exp > maxBackoffExponent {
+		exp = maxBackoffExponent
+	}
+
+	delay := p.BaseDelay << exp
+	if delay <= 0 || delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+type LockoutGuard interface {
+	// Check dipanggil sebelum verifikasi password (backoff per identifier dan per IP)
+	Check(ctx context.Context, identifier, ipAddress string) error
+
+	// CheckUser menolak akun yang terkunci; lock yang cooldown-nya habis di-unlock otomatis
+	CheckUser(ctx context.Context, user *domain.User) error
+
+	// RecordFailure mencatat login gagal dengan outcome-nya dan mengunci akun jika
+	// kegagalan yang dihitung melewati HardLockThreshold. user boleh nil (identifier tidak dikenal).
+	RecordFailure(ctx context.Context, identifier string, client LoginClient, user *domain.User, outcome string)
+
+	// RecordSuccess mencatat login sukses; hitungan gagal per identifier mulai dari nol lagi
+	RecordSuccess(ctx context.Context, identifier string, client LoginClient, user *domain.User)
+}
+
+type lockoutGuard struct {
+	loginAttemptRepo authPorts.LoginAttemptRepository
+	lockEventRepo    authPorts.AccountLockEventRepository
+	userRepo         userPorts.UserRepository
+	tokenGenerator   otherPorts.TokenGenerator
+	notifier         emailUC.NotificationUsecase
+	policy           LockoutPolicy
+}
+
+func NewLockoutGuard(
+	loginAttemptRepo authPorts.LoginAttemptRepository,
+	lockEventRepo authPorts.AccountLockEventRepository,
+	userRepo userPorts.UserRepository,
+	tokenGenerator otherPorts.TokenGenerator,
+	notifier emailUC.NotificationUsecase,
+	policy LockoutPolicy,
+) LockoutGuard {
+	return &lockoutGuard{
+		loginAttemptRepo: loginAttemptRepo,
+		lockEventRepo:    lockEventRepo,
+		userRepo:         userRepo,
+		tokenGenerator:   tokenGenerator,
+		notifier:         notifier,
+		policy:           policy,
+	}
+}
+
+// ================= CHECK =================
+
+func (g *lockoutGuard) Check(ctx context.Context, identifier, ipAddress string) error {
+	now := time.Now()
+	since := now.Add(-g.policy.Window)
+
+	count, last, err := g.loginAttemptRepo.CountFailuresByIdentifier(ctx, identifier, since)
+	if err != nil {
+		// Fail-safe: kalau DB error, anggap limited
+		return ErrTooManyAttempts
+	}
+	if retry := g.retryAfter(now, last, g.policy.backoff(count, g.policy.FreeAttempts)); retry > 0 {
+		return &LoginThrottledError{RetryAfter: retry}
+	}
+
+	if ipAddress == "" {
+		return nil
+	}
+
+	count, last, err = g.loginAttemptRepo.CountFailuresByIP(ctx, ipAddress, since)
+	if err != nil {
+		return ErrTooManyAttempts
+	}
+	if retry := g.retryAfter(now, last, g.policy.backoff(count, g.policy.IPFreeAttempts)); retry > 0 {
+		return &LoginThrottledError{RetryAfter: retry}
+	}
+
+	return nil
+}
+
+func (g *lockoutGuard) retryAfter(now, last time.Time, delay time.Duration) time.Duration {
+	if delay == 0 || last.IsZero() {
+		return 0
+	}
+
+	if wait := last.Add(delay).Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+func (g *lockoutGuard) CheckUser(ctx context.Context, user *domain.User) error {
+	if !user.Locked {
+		return nil
+	}
+
+	now := time.Now()
+	if user.IsLocked(now) {
+		return &AccountLockedError{LockedUntil: user.LockedUntil}
+	}
+
+	// cooldown habis → auto-unlock
+	if err := g.userRepo.Unlock(ctx, user.ID); err != nil {
+		return err
+	}
+	user.Unlock()
+
+	g.logEvent(ctx, &domain.AccountLockEvent{
+		UserID: user.ID,
+		Action: domain.AccountLockActionUnlock,
+		Reason: UnlockReasonCooldown,
+	})
+
+	return nil
+}
+
+// ================= RECORD =================
+
+func (g *lockoutGuard) RecordFailure(
+	ctx context.Context,
+	identifier string,
+	client LoginClient,
+	user *domain.User,
+	outcome string,
+) {
+
+	g.record(ctx, identifier, client, user, false, outcome)
+
+	if user == nil || user.Locked || g.policy.HardLockThreshold <= 0 || !isLockoutCounted(outcome) {
+		return
+	}
+
+	count, _, err := g.loginAttemptRepo.CountFailuresByIdentifier(ctx, identifier, g.hardLockSince(ctx, user))
+	if err != nil || count < g.policy.HardLockThreshold {
+		return
+	}
+
+	if err := g.lock(ctx, user); err != nil {
+		log.Printf("[LOGIN] failed to lock account: %v", err)
+	}
+}
+
+func (g *lockoutGuard) lock(ctx context.Context, user *domain.User) error {
+	var until *time.Time
+	if g.policy.LockCooldown > 0 {
+		t := time.Now().Add(g.policy.LockCooldown)
+		until = &t
+	}
+
+	plain, hash, err := g.tokenGenerator.Generate()
+	if err != nil {
+		return err
+	}
+
+	if err := g.userRepo.Lock(ctx, user.ID, LockReasonTooManyFailures, until, &hash); err != nil {
+		return err
+	}
+	user.Lock(LockReasonTooManyFailures, until)
+
+	g.logEvent(ctx, &domain.AccountLockEvent{
+		UserID:      user.ID,
+		Action:      domain.AccountLockActionLock,
+		Reason:      LockReasonTooManyFailures,
+		LockedUntil: until,
+	})
+
+	// lewat channel pilihan user, async supaya tidak menahan response login
+	notice := emailPorts.AccountLockedNotice{
+		LockedUntil: until,
+		UnlockURL:   g.policy.UnlockURL + "?token=" + plain,
+	}
+	go func() {
+		if err := g.notifier.SendAccountLocked(context.Background(), user, notice); err != nil {
+			log.Printf("[LOGIN] failed to send account locked notice: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+func (g *lockoutGuard) RecordSuccess(
+	ctx context.Context,
+	identifier string,
+	client LoginClient,
+	user *domain.User,
+) {
+
+	g.record(ctx, identifier, client, user, true, domain.LoginOutcomeSuccess)
+}
+
+func (g *lockoutGuard) record(
+	ctx context.Context,
+	identifier string,
+	client LoginClient,
+	user *domain.User,
+	success bool,
+	outcome string,
+) {
+
+	attempt := &domain.LoginAttempt{
+		Identity:  identifier,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+		Success:   success,
+		Reason:    outcome,
+	}
+	if user != nil {
+		attempt.UserID = &user.ID
+	}
+
+	if err := g.loginAttemptRepo.Record(ctx, attempt); err != nil {
+		log.Printf("[LOGIN] failed to record login attempt: %v", err)
+	}
+}
+
+// hardLockSince: kegagalan sebelum unlock terakhir tidak boleh langsung mengunci ulang
+func (g *lockoutGuard) hardLockSince(ctx context.Context, user *domain.User) time.Time {
+	since := time.Now().Add(-g.policy.Window)
+
+	events, err := g.lockEventRepo.GetRecentByUser(ctx, user.ID, 1)
+	if err != nil || len(events) == 0 {
+		return since
+	}
+
+	last := events[0]
+	if last.Action == domain.AccountLockActionUnlock && last.CreatedAt.After(since) {
+		return last.CreatedAt
+	}
+	return since
+}
+
+func isLockoutCounted(outcome string) bool {
+	for _, o := range domain.LockoutCountedOutcomes() {
+		if o == outcome {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *lockoutGuard) logEvent(ctx context.Context, event *domain.AccountLockEvent) {
+	if err := g.lockEventRepo.Create(ctx, event); err != nil {
+		log.Printf("[LOGIN] failed to write account lock event: %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	authPorts "github.com/dhanarrizky/Golang-template/internal/ports/auth"
+	otherPorts "github.com/dhanarrizky/Golang-template/internal/ports/others"
+)
+
+const (
+	defaultLoginHistoryLimit = 20
+	maxLoginHistoryLimit     = 100
+
+	// filter outcome agregat selain outcome spesifik (domain.LoginOutcome*)
+	LoginHistoryOutcomeFailure = "failure"
+)
+
+var (
+	ErrLoginHistoryUserIDDecode = errors.New("invalid user id")
+	ErrInvalidLoginHistoryRange = errors.New("from must be before to")
+)
+
+type LoginHistoryEntry struct {
+	UserID     string // public id, kosong jika identifier tidak dikenal
+	Identifier string
+	IPAddress  string
+	UserAgent  string
+	Success    bool
+	Outcome    string
+	CreatedAt  time.Time
+}
+
+// LoginHistoryQuery: field kosong = tidak difilter
+type LoginHistoryQuery struct {
+	UserID    string // public id
+	IPAddress string
+	Outcome   string // "success", "failure", atau outcome spesifik (mis. "invalid_credentials")
+	From      *time.Time
+	To        *time.Time
+
+	Limit  int
+	Offset int
+}
+
+type LoginHistoryUsecase interface {
+	// ListMine: history login milik user yang sedang login
+	ListMine(ctx context.Context, userID string, limit, offset int) ([]*LoginHistoryEntry, int64, error)
+
+	// Search: query admin lintas user
+	Search(ctx context.Context, query LoginHistoryQuery) ([]*LoginHistoryEntry, int64, error)
+}
+
+type loginHistoryUsecase struct {
+	loginAttemptRepo authPorts.LoginAttemptRepository
+	idCodec          otherPorts.PublicIDCodec
+}
+
+func NewLoginHistoryUsecase(
+	loginAttemptRepo authPorts.LoginAttemptRepository,
+	idCodec otherPorts.PublicIDCodec,
+) LoginHistoryUsecase {
+	return &loginHistoryUsecase{
+		loginAttemptRepo: loginAttemptRepo,
+		idCodec:          idCodec,
+	}
+}
+
+// ================= LIST MINE =================
+
+func (u *loginHistoryUsecase) ListMine(
+	ctx context.Context,
+	userID string,
+	limit, offset int,
+) ([]*LoginHistoryEntry, int64, error) {
+
+	if userID == "" {
+		return nil, 0, ErrLoginHistoryUserIDDecode
+	}
+
+	return u.Search(ctx, LoginHistoryQuery{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// ================= SEARCH =================
+
+func (u *loginHistoryUsecase) Search(
+	ctx context.Context,
+	query LoginHistoryQuery,
+) ([]*LoginHistoryEntry, int64, error) {
+
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return nil, 0, ErrInvalidLoginHistoryRange
+	}
+
+	filter := authPorts.LoginAttemptFilter{
+		IPAddress: query.IPAddress,
+		From:      query.From,
+		To:        query.To,
+		Limit:     normalizeLoginHistoryLimit(query.Limit),
+		Offset:    query.Offset,
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	if query.UserID != "" {
+		id, err := u.idCodec.Decode(query.UserID)
+		if err != nil {
+			return nil, 0, ErrLoginHistoryUserIDDecode
+		}
+		filter.UserID = &id
+	}
+
+	switch query.Outcome {
+	case "":
+	case domain.LoginOutcomeSuccess:
+		success := true
+		filter.Success = &success
+	case LoginHistoryOutcomeFailure:
+		success := false
+		filter.Success = &success
+	default:
+		filter.Reason = query.Outcome
+	}
+
+	attempts, total, err := u.loginAttemptRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries := make([]*LoginHistoryEntry, 0, len(attempts))
+	for _, a := range attempts {
+		entry := &LoginHistoryEntry{
+			Identifier: a.Identity,
+			IPAddress:  a.IPAddress,
+			UserAgent:  a.UserAgent,
+			Success:    a.Success,
+			Outcome:    a.Reason,
+			CreatedAt:  a.CreatedAt,
+		}
+		if a.UserID != nil {
+			if publicID, err := u.idCodec.Encode(*a.UserID); err == nil {
+				entry.UserID = publicID
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, total, nil
+}
+
+func normalizeLoginHistoryLimit(limit int) int {
+	if limit <= 0 {
+		return defaultLoginHistoryLimit
+	}
+	if limit > maxLoginHistoryLimit {
+		return maxLoginHistoryLimit
+	}
+	return limit
+}
@@ -3,11 +3,19 @@ package auth
 import (
 	"context"
 	"errors"
+	"log"
+	"time"
 
 	// "github.com/dhanarrizky/Golang-template/internal/ports"
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
 	authPorts "github.com/dhanarrizky/Golang-template/internal/ports/auth"
+	emailPorts "github.com/dhanarrizky/Golang-template/internal/ports/email"
 	rolePorts "github.com/dhanarrizky/Golang-template/internal/ports/roles"
 	userPorts "github.com/dhanarrizky/Golang-template/internal/ports/users"
+	emailUC "github.com/dhanarrizky/Golang-template/internal/usecase/email"
+	passwordUC "github.com/dhanarrizky/Golang-template/internal/usecase/password"
+	"github.com/dhanarrizky/Golang-template/pkg/email"
 )
 
 var (
@@ -15,6 +23,23 @@ var (
 	ErrTooManyAttempts    = errors.New("too many login attempts")
 	ErrRoleNotFound       = errors.New("invalid role access")
 	ErrAccountLocked      = errors.New("account locked")
+	ErrLoginBlocked       = errors.New("login blocked due to suspicious activity")
+	ErrInvalidStepUpCode  = errors.New("invalid or expired verification code")
+	ErrEmailNotVerified   = errors.New("email address has not been verified")
+)
+
+const (
+	StepUpMethodEmailOTP    = "email_otp"
+	StepUpMethodSMSOTP      = "sms_otp"
+	StepUpMethodWhatsAppOTP = "whatsapp_otp"
+	StepUpMethodTOTP        = "totp"
+)
+
+// Kebijakan login untuk akun yang emailnya belum diverifikasi
+const (
+	UnverifiedLoginAllow = "allow" // akses penuh
+	UnverifiedLoginLimit = "limit" // boleh login, endpoint lain dibatasi RequireVerifiedEmail
+	UnverifiedLoginBlock = "block" // login ditolak sampai email diverifikasi
 )
 
 type LoginResult struct {
@@ -24,33 +49,71 @@ type LoginResult struct {
 	Roles         string
 	RolesID       uint64
 	EmailVerified bool
+
+	RiskScore    int
+	RiskDecision domain.RiskDecision
+
+	// StepUpRequired: password valid tapi login harus diulang dengan kode verifikasi
+	StepUpRequired bool
+	StepUpMethod   string
+
+	// ACR metode yang benar-benar diselesaikan login ini, untuk klaim "acr"
+	// (TokenUsecase.IssueForLogin): mfa jika lolos step-up, selain itu pwd
+	ACR string
+
+	// PasswordChangeRequired: login sukses tapi akses dibatasi RequirePasswordChange
+	// sampai password diganti (ditemukan di data breach, expired, atau diminta admin)
+	PasswordChangeRequired bool
+	// PasswordExpiresAt nil jika password tidak punya umur maksimal
+	PasswordExpiresAt *time.Time
 }
 
 type LoginUsecase interface {
-	Login(ctx context.Context, identifier, password string) (*LoginResult, error)
+	Login(ctx context.Context, identifier, password, stepUpCode string, client LoginClient) (*LoginResult, error)
 	Logout(ctx context.Context, refreshToken string) error
 }
 
 type loginUsecase struct {
-	userRepo         userPorts.UserRepository
-	loginAttemptRepo authPorts.LoginAttemptRepository
-	passwordHasher   userPorts.PasswordHasher
-	roleRepo         rolePorts.RoleRepository
-	tokenUsecase     TokenUsecase
+	userRepo       userPorts.UserRepository
+	lockout        LockoutGuard
+	passwordHasher userPorts.PasswordHasher
+	roleRepo       rolePorts.RoleRepository
+	tokenUsecase   TokenUsecase
+	riskEvaluator  RiskEvaluator
+	otpUsecase     *emailUC.OTPUsecase
+	secondFactor   authPorts.SecondFactorVerifier // optional (TOTP)
+	notifier       emailUC.NotificationUsecase
+	passwordPolicy passwordUC.PasswordPolicyUsecase
+
+	unverifiedPolicy string
 }
 
 func NewLoginUsecase(
 	userRepo userPorts.UserRepository,
-	loginAttemptRepo authPorts.LoginAttemptRepository,
+	lockout LockoutGuard,
 	passwordHasher userPorts.PasswordHasher,
 	roleRepo rolePorts.RoleRepository,
 	tokenUsecase TokenUsecase,
+	riskEvaluator RiskEvaluator,
+	otpUsecase *emailUC.OTPUsecase,
+	secondFactor authPorts.SecondFactorVerifier,
+	notifier emailUC.NotificationUsecase,
+	passwordPolicy passwordUC.PasswordPolicyUsecase,
+	unverifiedPolicy string,
 ) LoginUsecase {
 	return &loginUsecase{
-		userRepo:         userRepo,
-		loginAttemptRepo: loginAttemptRepo,
-		passwordHasher:   passwordHasher,
-		tokenUsecase:     tokenUsecase,
+		userRepo:       userRepo,
+		lockout:        lockout,
+		passwordHasher: passwordHasher,
+		roleRepo:       roleRepo,
+		tokenUsecase:   tokenUsecase,
+		riskEvaluator:  riskEvaluator,
+		otpUsecase:     otpUsecase,
+		secondFactor:   secondFactor,
+		notifier:       notifier,
+		passwordPolicy: passwordPolicy,
+
+		unverifiedPolicy: unverifiedPolicy,
 	}
 }
 
@@ -58,39 +121,121 @@ func NewLoginUsecase(
 
 func (u *loginUsecase) Login(
 	ctx context.Context,
-	identifier, password string,
+	identifier, password, stepUpCode string,
+	client LoginClient,
 ) (*LoginResult, error) {
 
-	if u.loginAttemptRepo.IsRateLimited(ctx, identifier) {
-		return nil, ErrTooManyAttempts
+	// backoff per identifier dan per IP → *LoginThrottledError
+	if err := u.lockout.Check(ctx, identifier, client.IPAddress); err != nil {
+		u.lockout.RecordFailure(ctx, identifier, client, nil, domain.LoginOutcomeThrottled)
+		return nil, err
 	}
 
 	user, err := u.userRepo.GetByEmailOrUsername(ctx, identifier)
 	if err != nil || user == nil {
-		u.loginAttemptRepo.RecordFailedAttempt(ctx, identifier)
+		u.lockout.RecordFailure(ctx, identifier, client, nil, domain.LoginOutcomeUnknownIdentifier)
 		return nil, ErrInvalidCredentials
 	}
 
-	if user.Locked {
-		return nil, ErrAccountLocked
+	// hard lock → *AccountLockedError
+	if err := u.lockout.CheckUser(ctx, user); err != nil {
+		u.lockout.RecordFailure(ctx, identifier, client, user, domain.LoginOutcomeAccountLocked)
+		return nil, err
 	}
 
 	matched, shouldRehash, err :=
-		u.passwordHasher.VerifyPassword([]byte(password), user.PasswordHash)
+		u.passwordHasher.VerifyPassword(ctx, []byte(password), user.PasswordHash)
 
+	// antrian hashing penuh / request dibatalkan: bukan password salah, tidak dihitung gagal
+	if err != nil && (errors.Is(err, userPorts.ErrPasswordHasherBusy) || ctx.Err() != nil) {
+		return nil, err
+	}
 	if err != nil || !matched {
-		u.loginAttemptRepo.RecordFailedAttempt(ctx, identifier)
+		u.lockout.RecordFailure(ctx, identifier, client, user, domain.LoginOutcomeInvalidCredentials)
 		return nil, ErrInvalidCredentials
 	}
 
+	// dicek setelah password supaya status verifikasi tidak bocor ke penebak password
+	if u.unverifiedPolicy == UnverifiedLoginBlock && !user.EmailVerified {
+		u.lockout.RecordFailure(ctx, identifier, client, user, domain.LoginOutcomeEmailUnverified)
+		return nil, ErrEmailNotVerified
+	}
+
+	// Risk-based check: password benar belum tentu pemilik akun
+	assessment, err := u.riskEvaluator.Evaluate(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	acr := valueobjects.ACRPassword
+
+	switch assessment.Decision {
+	case domain.RiskDecisionBlock:
+		u.lockout.RecordFailure(ctx, identifier, client, user, domain.LoginOutcomeRiskBlocked)
+		return nil, ErrLoginBlocked
+
+	case domain.RiskDecisionStepUp:
+		method, err := u.stepUpMethod(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+
+		if stepUpCode == "" {
+			if method != StepUpMethodTOTP {
+				if err := u.sendStepUpOTP(ctx, user, client); err != nil {
+					return nil, err
+				}
+			}
+
+			u.lockout.RecordFailure(ctx, identifier, client, user, domain.LoginOutcomeStepUpRequired)
+
+			return &LoginResult{
+				UserID:         user.ID,
+				RiskScore:      assessment.Score,
+				RiskDecision:   assessment.Decision,
+				StepUpRequired: true,
+				StepUpMethod:   method,
+			}, nil
+		}
+
+		ok, err := u.verifyStepUp(ctx, user, method, stepUpCode)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			u.lockout.RecordFailure(ctx, identifier, client, user, domain.LoginOutcomeInvalidStepUpCode)
+			return nil, ErrInvalidStepUpCode
+		}
+
+		// password + kode OTP / TOTP: route yang minta acr=mfa tidak perlu reauth lagi
+		acr = valueobjects.ACRMultiFactor
+	}
+
+	// baru sekarang lokasi ini dipercaya untuk evaluasi login berikutnya
+	if err := u.riskEvaluator.Complete(ctx, assessment); err != nil {
+		log.Printf("[LOGIN] failed to complete risk event: %v", err)
+	}
+
 	if shouldRehash {
-		newHash, err := u.passwordHasher.HashPassword([]byte(password))
+		newHash, err := u.passwordHasher.HashPassword(ctx, []byte(password))
 		if err == nil {
-			_ = u.userRepo.UpdatePassword(ctx, user.ID, newHash)
+			_ = u.userRepo.RehashPassword(ctx, user.ID, newHash)
 		}
 	}
 
-	u.loginAttemptRepo.ResetAttempts(ctx, identifier)
+	u.lockout.RecordSuccess(ctx, identifier, client, user)
+
+	// satu-satunya saat password plain tersedia untuk dicek ke data breach
+	if !user.MustChangePassword {
+		u.flagBreachedPassword(ctx, user, password)
+	}
+	if !user.MustChangePassword {
+		u.flagExpiredPassword(ctx, user)
+	}
+
+	if assessment.NewDevice || assessment.NewLocation {
+		u.sendLoginAlert(user, client, assessment)
+	}
 
 	role, err := u.roleRepo.GetByID(ctx, user.RoleID)
 	if err != nil || role == nil {
@@ -104,9 +249,123 @@ func (u *loginUsecase) Login(
 		Roles:         role.Name,
 		RolesID:       role.ID,
 		EmailVerified: user.EmailVerified,
+		RiskScore:     assessment.Score,
+		RiskDecision:  assessment.Decision,
+		ACR:           acr,
+
+		PasswordChangeRequired: user.MustChangePassword,
+		PasswordExpiresAt:      user.PasswordExpiresAt(u.passwordPolicy.Policy().MaxAge),
 	}, nil
 }
 
+// ================= STEP-UP =================
+
+// stepUpMethod: TOTP jika user sudah enroll authenticator, selain itu OTP
+// lewat channel pilihan user (email / SMS / WhatsApp)
+func (u *loginUsecase) stepUpMethod(ctx context.Context, user *domain.User) (string, error) {
+	if u.secondFactor != nil {
+		enrolled, err := u.secondFactor.IsEnrolled(ctx, user.ID)
+		if err != nil {
+			return "", err
+		}
+		if enrolled {
+			return StepUpMethodTOTP, nil
+		}
+	}
+
+	switch user.NotificationChannel() {
+	case valueobjects.ChannelSMS:
+		return StepUpMethodSMSOTP, nil
+	case valueobjects.ChannelWhatsApp:
+		return StepUpMethodWhatsAppOTP, nil
+	}
+	return StepUpMethodEmailOTP, nil
+}
+
+func (u *loginUsecase) sendStepUpOTP(ctx context.Context, user *domain.User, client LoginClient) error {
+	otp, err := email.GenerateOTP()
+	if err != nil {
+		return err
+	}
+
+	_, err = u.otpUsecase.RequestOTP(
+		ctx,
+		user,
+		domain.EmailOTPPurposeLogin,
+		otp,
+		email.HashOTP(otp),
+		client.IPAddress,
+		client.UserAgent,
+	)
+	return err
+}
+
+func (u *loginUsecase) verifyStepUp(
+	ctx context.Context,
+	user *domain.User,
+	method, code string,
+) (bool, error) {
+
+	if method == StepUpMethodTOTP {
+		return u.secondFactor.Verify(ctx, user.ID, code)
+	}
+
+	return u.otpUsecase.VerifyOTP(ctx, user.Email, domain.EmailOTPPurposeLogin, email.HashOTP(code)), nil
+}
+
+// sendLoginAlert lewat channel pilihan user, async supaya tidak menahan login
+func (u *loginUsecase) sendLoginAlert(user *domain.User, client LoginClient, assessment *RiskAssessment) {
+	alert := emailPorts.LoginAlert{
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+		Time:      time.Now(),
+	}
+	if loc := assessment.Location; loc != nil {
+		alert.Location = loc.Country
+		if loc.City != "" {
+			alert.Location = loc.City + ", " + loc.Country
+		}
+	}
+
+	go func() {
+		if err := u.notifier.SendLoginAlert(context.Background(), user, alert); err != nil {
+			log.Printf("[LOGIN] failed to send new sign-in alert: %v", err)
+		}
+	}()
+}
+
+// flagBreachedPassword: gagal cek breach tidak menggagalkan login
+func (u *loginUsecase) flagBreachedPassword(ctx context.Context, user *domain.User, password string) {
+	breached, err := u.passwordPolicy.Breached(ctx, password)
+	if err != nil {
+		log.Printf("[LOGIN] breached password check failed: %v", err)
+		return
+	}
+	if !breached {
+		return
+	}
+
+	if err := u.userRepo.SetMustChangePassword(ctx, user.ID, true); err != nil {
+		log.Printf("[LOGIN] failed to flag breached password: %v", err)
+		return
+	}
+	user.RequirePasswordChange()
+}
+
+// flagExpiredPassword: umur password lewat PASSWORD_MAX_AGE_DAYS, biasanya sudah
+// ditandai job expiry lebih dulu; ini untuk user yang login sebelum job jalan
+func (u *loginUsecase) flagExpiredPassword(ctx context.Context, user *domain.User) {
+	if !user.PasswordExpired(u.passwordPolicy.Policy().MaxAge, time.Now()) {
+		return
+	}
+
+	if err := u.userRepo.SetMustChangePassword(ctx, user.ID, true); err != nil {
+		log.Printf("[LOGIN] failed to flag expired password: %v", err)
+		return
+	}
+	user.RequirePasswordChange()
+}
+
 // ================= LOGOUT =================
 
 func (u *loginUsecase) Logout(ctx context.Context, refreshToken string) error {
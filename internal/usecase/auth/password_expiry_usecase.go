@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	emailPorts "github.com/dhanarrizky/Golang-template/internal/ports/email"
+	userPorts "github.com/dhanarrizky/Golang-template/internal/ports/users"
+	emailUC "github.com/dhanarrizky/Golang-template/internal/usecase/email"
+	passwordUC "github.com/dhanarrizky/Golang-template/internal/usecase/password"
+)
+
+const passwordExpiryBatchSize = 100
+
+// PasswordExpiryResult: hasil satu kali jalan job expiry
+type PasswordExpiryResult struct {
+	Flagged int64 // password expired → must_change_password
+	Warned  int   // peringatan yang di-enqueue
+	Failed  int   // gagal di-enqueue, dicoba lagi di jalan berikutnya
+}
+
+type PasswordExpiryUsecase interface {
+	// ProcessDue menandai password expired dan mengirim peringatan yang sudah jatuh tempo
+	ProcessDue(ctx context.Context) (PasswordExpiryResult, error)
+}
+
+type passwordExpiryUsecase struct {
+	userRepo       userPorts.UserRepository
+	passwordPolicy passwordUC.PasswordPolicyUsecase
+	notifier       emailUC.NotificationUsecase
+}
+
+func NewPasswordExpiryUsecase(
+	userRepo userPorts.UserRepository,
+	passwordPolicy passwordUC.PasswordPolicyUsecase,
+	notifier emailUC.NotificationUsecase,
+) PasswordExpiryUsecase {
+	return &passwordExpiryUsecase{
+		userRepo:       userRepo,
+		passwordPolicy: passwordPolicy,
+		notifier:       notifier,
+	}
+}
+
+func (u *passwordExpiryUsecase) ProcessDue(ctx context.Context) (PasswordExpiryResult, error) {
+	var result PasswordExpiryResult
+
+	policy := u.passwordPolicy.Policy()
+	if policy.MaxAge <= 0 {
+		return result, nil
+	}
+
+	now := time.Now()
+
+	// 1. password yang sudah lewat umur maksimal: akses dibatasi sampai diganti
+	flagged, err := u.userRepo.FlagExpiredPasswords(ctx, now.Add(-policy.MaxAge))
+	if err != nil {
+		return result, err
+	}
+	result.Flagged = flagged
+
+	if policy.ExpiryWarning <= 0 {
+		return result, nil
+	}
+
+	// 2. password yang masuk masa peringatan, satu email per password
+	warnBefore := now.Add(-(policy.MaxAge - policy.ExpiryWarning))
+	for {
+		users, err := u.userRepo.GetPasswordExpiring(ctx, warnBefore, passwordExpiryBatchSize)
+		if err != nil {
+			return result, err
+		}
+
+		failed := 0
+		for _, user := range users {
+			expiresAt := user.PasswordChangedAt.Add(policy.MaxAge)
+
+			// enqueue dulu baru tandai: jika tandai gagal, key outbox mencegah email ganda.
+			// enqueue gagal → jangan ditandai, peringatan belum terkirim
+			if err := u.notifier.SendPasswordExpiry(ctx, user, emailPorts.PasswordExpiryNotice{
+				ExpiresAt: expiresAt,
+				DaysLeft:  int(math.Ceil(expiresAt.Sub(now).Hours() / 24)),
+			}); err != nil {
+				log.Printf("[PASSWORD_EXPIRY] failed to enqueue warning for user %d: %v", user.ID, err)
+				failed++
+				continue
+			}
+
+			if err := u.userRepo.MarkPasswordExpiryWarned(ctx, user.ID, now); err != nil {
+				return result, err
+			}
+			result.Warned++
+		}
+		result.Failed += failed
+
+		// user yang gagal masih belum ditandai dan akan terambil lagi:
+		// berhenti di sini, dicoba ulang di jalan berikutnya
+		if failed > 0 || len(users) < passwordExpiryBatchSize {
+			return result, nil
+		}
+	}
+}
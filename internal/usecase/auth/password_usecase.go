@@ -2,14 +2,20 @@ package auth
 
 import (
 	"context"
-	"crypto/rand"
 	"errors"
+	"strconv"
+	"strings"
 	"time"
 
 	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
 	authPorts "github.com/dhanarrizky/Golang-template/internal/ports/auth"
+	emailPorts "github.com/dhanarrizky/Golang-template/internal/ports/email"
 	otherPorts "github.com/dhanarrizky/Golang-template/internal/ports/others"
 	userPorts "github.com/dhanarrizky/Golang-template/internal/ports/users"
+	emailUC "github.com/dhanarrizky/Golang-template/internal/usecase/email"
+	passwordUC "github.com/dhanarrizky/Golang-template/internal/usecase/password"
+	"github.com/dhanarrizky/Golang-template/pkg/email"
 )
 
 var (
@@ -20,12 +26,57 @@ var (
 	ErrResetTokenUsed       = errors.New("reset token already used")
 	ErrCurrentPasswordWrong = errors.New("current password is incorrect")
 	ErrPasswordSameAsOld    = errors.New("new password cannot be the same as old password")
+	ErrPasswordUserNotFound = errors.New("user not found")
 )
 
+const (
+	PasswordResetMethodLink = "link"
+	PasswordResetMethodOTP  = "otp"
+)
+
+// PasswordResetPolicy: Method "link" (ResetURL?token=) atau "otp" (kode 6 digit + email)
+type PasswordResetPolicy struct {
+	Method   string
+	ResetURL string
+	TokenTTL time.Duration
+	// MaxAttempts kode OTP salah sebelum token hangus (metode otp)
+	MaxAttempts int
+	// ResendCooldown: Forgot berulang dalam jeda ini diabaikan diam-diam
+	ResendCooldown time.Duration
+}
+
+func DefaultPasswordResetPolicy() PasswordResetPolicy {
+	return PasswordResetPolicy{
+		Method:         PasswordResetMethodLink,
+		TokenTTL:       time.Hour,
+		MaxAttempts:    5,
+		ResendCooldown: time.Minute,
+	}
+}
+
 type PasswordUsecase interface {
-	Forgot(ctx context.Context, email string) error
-	Reset(ctx context.Context, token, newPassword string) error
-	Change(ctx context.Context, userID, currentPassword, newPassword string) error
+	// Forgot selalu sukses untuk email tidak terdaftar (anti-enumeration)
+	Forgot(ctx context.Context, email string, client LoginClient) error
+
+	// Reset dari link (metode link)
+	Reset(ctx context.Context, token, newPassword string, client LoginClient) error
+
+	// ResetWithCode dari email + kode OTP (metode otp)
+	ResetWithCode(ctx context.Context, email, code, newPassword string, client LoginClient) error
+
+	Change(ctx context.Context, userID, currentPassword, newPassword string, client LoginClient) error
+
+	// AdminReset: admin set password user; requireChange = user wajib ganti saat login berikutnya
+	AdminReset(ctx context.Context, userID, newPassword string, requireChange bool, client LoginClient) error
+
+	// RequireChange: admin paksa user ganti password saat login berikutnya (password tetap)
+	RequireChange(ctx context.Context, userID string) error
+
+	// RequireChangeBulk: userIDs, atau semua user kecuali actor jika all = true
+	RequireChangeBulk(ctx context.Context, actorID string, userIDs []string, all bool) (int64, error)
+
+	// MustChangePassword untuk middleware RequirePasswordChange
+	MustChangePassword(ctx context.Context, userID string) (bool, error)
 }
 
 type passwordUsecase struct {
@@ -35,11 +86,14 @@ type passwordUsecase struct {
 	refreshFamilyRepo authPorts.RefreshTokenFamilyRepository
 	sessionRepo       userPorts.UserSessionRepository
 	passwordHasher    userPorts.PasswordHasher
-	codecHasher       otherPorts.PublicIDCodec
+	passwordPolicy    passwordUC.PasswordPolicyUsecase
 	tokenGenerator    otherPorts.TokenGenerator
-	hmacTokenVerifier otherPorts.TokenVerifier
-	resetTokenExp     time.Duration
+	tokenVerifier     otherPorts.TokenVerifier
+	outbox            emailUC.EmailOutboxUsecase
+	notifier          emailUC.NotificationUsecase
+	txManager         otherPorts.TransactionManager
 	idCodec           otherPorts.PublicIDCodec
+	policy            PasswordResetPolicy
 }
 
 func NewPasswordUsecase(
@@ -49,94 +103,220 @@ func NewPasswordUsecase(
 	refreshFamilyRepo authPorts.RefreshTokenFamilyRepository,
 	sessionRepo userPorts.UserSessionRepository,
 	passwordHasher userPorts.PasswordHasher,
-	resetTokenExp time.Duration,
+	passwordPolicy passwordUC.PasswordPolicyUsecase,
+	tokenGenerator otherPorts.TokenGenerator,
+	tokenVerifier otherPorts.TokenVerifier,
+	outbox emailUC.EmailOutboxUsecase,
+	notifier emailUC.NotificationUsecase,
+	txManager otherPorts.TransactionManager,
 	idCodec otherPorts.PublicIDCodec,
+	policy PasswordResetPolicy,
 ) PasswordUsecase {
 	return &passwordUsecase{
-		userRepo:       userRepo,
-		resetTokenRepo: resetTokenRepo,
-		refreshRepo:    refreshRepo,
-		sessionRepo:    sessionRepo,
-		passwordHasher: passwordHasher,
-		resetTokenExp:  resetTokenExp,
+		userRepo:          userRepo,
+		resetTokenRepo:    resetTokenRepo,
+		refreshRepo:       refreshRepo,
+		refreshFamilyRepo: refreshFamilyRepo,
+		sessionRepo:       sessionRepo,
+		passwordHasher:    passwordHasher,
+		passwordPolicy:    passwordPolicy,
+		tokenGenerator:    tokenGenerator,
+		tokenVerifier:     tokenVerifier,
+		outbox:            outbox,
+		notifier:          notifier,
+		txManager:         txManager,
+		idCodec:           idCodec,
+		policy:            policy,
 	}
 }
 
 // ================= FORGOT PASSWORD =================
-func (u *passwordUsecase) Forgot(ctx context.Context, email string) error {
-	user, err := u.userRepo.GetByEmail(ctx, email)
+
+func (u *passwordUsecase) Forgot(ctx context.Context, emailAddr string, client LoginClient) error {
+	user, err := u.userRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(emailAddr)))
 	if err != nil || user == nil {
 		return nil // silent untuk security
 	}
 
-	// Generate secure random token
-	raw := make([]byte, 48)
-	if _, err := rand.Read(raw); err != nil {
-		return err
-	}
-
-	_, hashedToken, err := u.tokenGenerator.Generate()
+	last, err := u.resetTokenRepo.GetLatestActiveByUser(ctx, user.ID)
 	if err != nil {
 		return err
 	}
-
-	// ID:        uuid.NewString(),
-	// UserID:    user.ID,
-	// Token:     hashedToken,
-	// ExpiresAt: time.Now().Add(u.resetTokenExp),
-	// CreatedAt: time.Now(),
-	// Used:      false,
-	resetToken := domain.PasswordResetToken{
-		UserID:    user.ID,
-		TokenHash: hashedToken,
-		ExpiresAt: time.Now().Add(u.resetTokenExp),
-		Used:      false,
-		CreatedAt: time.Now(),
+	if last != nil && time.Since(last.CreatedAt) < u.policy.ResendCooldown {
+		return nil // baru saja dikirim, tidak membocorkan status lewat error
 	}
 
-	if err := u.resetTokenRepo.Create(ctx, &resetToken); err != nil {
-		return err
+	expiresAt := time.Now().Add(u.policy.TokenTTL)
+	notice := emailPorts.PasswordReset{ExpiresAt: expiresAt}
+
+	var hash string
+	if u.policy.Method == PasswordResetMethodOTP {
+		code, err := email.GenerateOTP()
+		if err != nil {
+			return err
+		}
+		hash = u.codeHash(user.ID, code)
+		notice.Code = code
+	} else {
+		plain, h, err := u.tokenGenerator.Generate()
+		if err != nil {
+			return err
+		}
+		hash = h
+		notice.ResetURL = u.policy.ResetURL + "?token=" + plain
 	}
 
-	return nil
+	// token lama hangus, token baru + email ditulis satu transaksi (dikirim worker outbox)
+	return u.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := u.resetTokenRepo.InvalidateByUser(txCtx, user.ID); err != nil {
+			return err
+		}
+
+		token := &domain.PasswordResetToken{
+			UserID:    user.ID,
+			TokenHash: hash,
+			ExpiresAt: expiresAt,
+			IPAddress: client.IPAddress,
+		}
+		if err := u.resetTokenRepo.Create(txCtx, token); err != nil {
+			return err
+		}
+
+		return u.outbox.Enqueue(
+			txCtx,
+			domain.EmailOutboxKindResetPassword,
+			user.Email,
+			user.Locale,
+			"reset_password:"+strconv.FormatUint(token.ID, 10),
+			notice,
+		)
+	})
 }
 
 // ================= RESET PASSWORD =================
-func (u *passwordUsecase) Reset(ctx context.Context, username, newPassword string) error {
-	user, err := u.userRepo.GetByEmailOrUsername(ctx, username)
+
+func (u *passwordUsecase) Reset(ctx context.Context, token, newPassword string, client LoginClient) error {
+	if token == "" {
+		return ErrResetTokenInvalid
+	}
+
+	t, err := u.resetTokenRepo.GetByTokenHash(ctx, u.tokenVerifier.Hash(token))
+	if err != nil || t == nil {
+		return ErrResetTokenInvalid
+	}
+	if t.Used {
+		return ErrResetTokenUsed
+	}
+	if t.IsExpired(time.Now()) {
+		return ErrResetTokenInvalid
+	}
+
+	user, err := u.userRepo.GetByID(ctx, t.UserID)
 	if err != nil || user == nil {
-		return ErrUsernameNotFound
+		return ErrResetTokenInvalid
 	}
 
-	// Hash new password
-	hashedNew, err := u.passwordHasher.HashPassword([]byte(newPassword))
-	if err != nil {
-		return ErrHashingPass
+	return u.complete(ctx, user, t, newPassword, client)
+}
+
+func (u *passwordUsecase) ResetWithCode(
+	ctx context.Context,
+	emailAddr, code, newPassword string,
+	client LoginClient,
+) error {
+
+	user, err := u.userRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(emailAddr)))
+	if err != nil || user == nil || code == "" {
+		return ErrResetTokenInvalid
+	}
+
+	t, err := u.resetTokenRepo.GetLatestActiveByUser(ctx, user.ID)
+	if err != nil || t == nil {
+		return ErrResetTokenInvalid
 	}
 
-	if err := u.userRepo.UpdatePassword(ctx, user.ID, hashedNew); err != nil {
+	if !u.tokenVerifier.Compare(t.TokenHash, u.codePlain(user.ID, code)) {
+		// kode 6 digit: batasi tebakan per token, setelah itu harus minta kode baru
+		attempts, err := u.resetTokenRepo.IncrementAttempts(ctx, t.ID)
+		if err == nil && attempts >= u.policy.MaxAttempts {
+			_, _ = u.resetTokenRepo.MarkUsed(ctx, t.ID)
+		}
+		return ErrResetTokenInvalid
+	}
+
+	return u.complete(ctx, user, t, newPassword, client)
+}
+
+// complete: token sudah cocok; cek policy, ganti password, hanguskan token,
+// cabut semua session & refresh token lalu kirim notifikasi
+func (u *passwordUsecase) complete(
+	ctx context.Context,
+	user *domain.User,
+	t *domain.PasswordResetToken,
+	newPassword string,
+	client LoginClient,
+) error {
+
+	if err := u.passwordPolicy.Validate(ctx, newPassword, userInfo(user)); err != nil {
 		return err
 	}
 
-	// Revoke all sessions & refresh tokens (force logout everywhere)
-	families, err := u.refreshFamilyRepo.GetByUserID(ctx, user.ID)
+	if err := u.ensureNotReused(ctx, user, newPassword); err != nil {
+		return err
+	}
+
+	hashedNew, err := u.hashPassword(ctx, newPassword)
 	if err != nil {
 		return err
 	}
 
-	for _, family := range families {
-		// revoke semua refresh token dalam family
-		_ = u.refreshRepo.RevokeByFamily(ctx, family.ID)
+	err = u.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		// sekali pakai: request paralel dengan token yang sama hanya satu yang lolos
+		ok, err := u.resetTokenRepo.MarkUsed(txCtx, t.ID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrResetTokenUsed
+		}
 
-		// revoke family itu sendiri
-		_ = u.refreshFamilyRepo.Revoke(ctx, family.ID)
-	}
+		if err := u.resetTokenRepo.InvalidateByUser(txCtx, user.ID); err != nil {
+			return err
+		}
 
-	return nil
+		if err := u.userRepo.UpdatePassword(txCtx, user.ID, hashedNew); err != nil {
+			return err
+		}
+
+		if err := u.passwordPolicy.Remember(txCtx, user.ID, user.PasswordHash); err != nil {
+			return err
+		}
+
+		if err := u.clearMustChange(txCtx, user); err != nil {
+			return err
+		}
+
+		if err := u.revokeSessions(txCtx, user.ID); err != nil {
+			return err
+		}
+
+		return u.notifier.SendPasswordChanged(txCtx, user, emailPorts.PasswordChangedNotice{
+			IPAddress: client.IPAddress,
+			UserAgent: client.UserAgent,
+			Time:      time.Now(),
+		})
+	})
+
+	return err
 }
 
 // ================= CHANGE PASSWORD (logged in user) =================
-func (u *passwordUsecase) Change(ctx context.Context, userID, currentPassword, newPassword string) error {
+func (u *passwordUsecase) Change(
+	ctx context.Context,
+	userID, currentPassword, newPassword string,
+	client LoginClient,
+) error {
+
 	id, err := u.idCodec.Decode(userID)
 	if err != nil {
 		return ErrDecode
@@ -144,11 +324,12 @@ func (u *passwordUsecase) Change(ctx context.Context, userID, currentPassword, n
 
 	user, err := u.userRepo.GetByID(ctx, id)
 	if err != nil || user == nil {
-		return errors.New("user not found")
+		return ErrPasswordUserNotFound
 	}
 
 	// Verify current password
 	match, _, err := u.passwordHasher.VerifyPassword(
+		ctx,
 		[]byte(currentPassword),
 		user.PasswordHash,
 	)
@@ -160,22 +341,256 @@ func (u *passwordUsecase) Change(ctx context.Context, userID, currentPassword, n
 		return ErrCurrentPasswordWrong
 	}
 
-	// Prevent same password
-	same, _, err := u.passwordHasher.VerifyPassword(
-		[]byte(newPassword),
-		user.PasswordHash,
-	)
+	if err := u.passwordPolicy.Validate(ctx, newPassword, userInfo(user)); err != nil {
+		return err
+	}
+
+	// Prevent same password (saat ini + history)
+	if err := u.ensureNotReused(ctx, user, newPassword); err != nil {
+		return err
+	}
+
+	hashedNew, err := u.hashPassword(ctx, newPassword)
 	if err != nil {
 		return err
 	}
 
+	return u.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := u.userRepo.UpdatePassword(txCtx, id, hashedNew); err != nil {
+			return err
+		}
+
+		if err := u.passwordPolicy.Remember(txCtx, id, user.PasswordHash); err != nil {
+			return err
+		}
+
+		if err := u.clearMustChange(txCtx, user); err != nil {
+			return err
+		}
+
+		// link / kode reset yang masih aktif tidak berlaku lagi
+		if err := u.resetTokenRepo.InvalidateByUser(txCtx, id); err != nil {
+			return err
+		}
+
+		return u.notifier.SendPasswordChanged(txCtx, user, emailPorts.PasswordChangedNotice{
+			IPAddress: client.IPAddress,
+			UserAgent: client.UserAgent,
+			Time:      time.Now(),
+		})
+	})
+}
+
+// ================= ADMIN RESET =================
+
+// AdminReset: aturan policy & history sama dengan reset mandiri; semua session user dicabut
+func (u *passwordUsecase) AdminReset(
+	ctx context.Context,
+	userID, newPassword string,
+	requireChange bool,
+	client LoginClient,
+) error {
+
+	id, err := u.idCodec.Decode(userID)
+	if err != nil {
+		return ErrDecode
+	}
+
+	user, err := u.userRepo.GetByID(ctx, id)
+	if err != nil || user == nil {
+		return ErrPasswordUserNotFound
+	}
+
+	if err := u.passwordPolicy.Validate(ctx, newPassword, userInfo(user)); err != nil {
+		return err
+	}
+
+	if err := u.ensureNotReused(ctx, user, newPassword); err != nil {
+		return err
+	}
+
+	hashedNew, err := u.hashPassword(ctx, newPassword)
+	if err != nil {
+		return err
+	}
+
+	return u.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := u.userRepo.UpdatePassword(txCtx, id, hashedNew); err != nil {
+			return err
+		}
+
+		if err := u.passwordPolicy.Remember(txCtx, id, user.PasswordHash); err != nil {
+			return err
+		}
+
+		// password dari admin diketahui orang lain → user ganti sendiri saat login
+		if requireChange != user.MustChangePassword {
+			if err := u.userRepo.SetMustChangePassword(txCtx, id, requireChange); err != nil {
+				return err
+			}
+		}
+
+		if err := u.resetTokenRepo.InvalidateByUser(txCtx, id); err != nil {
+			return err
+		}
+
+		if err := u.revokeSessions(txCtx, id); err != nil {
+			return err
+		}
+
+		return u.notifier.SendPasswordChanged(txCtx, user, emailPorts.PasswordChangedNotice{
+			IPAddress: client.IPAddress,
+			UserAgent: client.UserAgent,
+			Time:      time.Now(),
+		})
+	})
+}
+
+// ================= REQUIRE CHANGE (admin) =================
+
+func (u *passwordUsecase) RequireChange(ctx context.Context, userID string) error {
+	id, err := u.idCodec.Decode(userID)
+	if err != nil {
+		return ErrDecode
+	}
+
+	user, err := u.userRepo.GetByID(ctx, id)
+	if err != nil || user == nil {
+		return ErrPasswordUserNotFound
+	}
+
+	if user.MustChangePassword {
+		return nil
+	}
+
+	// sesi aktif tetap jalan, tapi middleware RequirePasswordChange langsung membatasi akses
+	return u.userRepo.SetMustChangePassword(ctx, id, true)
+}
+
+func (u *passwordUsecase) RequireChangeBulk(
+	ctx context.Context,
+	actorID string,
+	userIDs []string,
+	all bool,
+) (int64, error) {
+
+	if all {
+		// admin yang menjalankan tidak ikut terkunci
+		actor, err := u.idCodec.Decode(actorID)
+		if err != nil {
+			return 0, ErrDecode
+		}
+		return u.userRepo.SetMustChangePasswordAll(ctx, actor)
+	}
+
+	ids := make([]uint64, 0, len(userIDs))
+	for _, userID := range userIDs {
+		id, err := u.idCodec.Decode(userID)
+		if err != nil {
+			return 0, ErrDecode
+		}
+		ids = append(ids, id)
+	}
+
+	return u.userRepo.SetMustChangePasswordByIDs(ctx, ids)
+}
+
+func (u *passwordUsecase) MustChangePassword(ctx context.Context, userID string) (bool, error) {
+	id, err := u.idCodec.Decode(userID)
+	if err != nil {
+		return false, ErrDecode
+	}
+
+	user, err := u.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if user == nil {
+		return false, ErrPasswordUserNotFound
+	}
+
+	return user.MustChangePassword, nil
+}
+
+// ================= HELPERS =================
+
+// ensureNotReused: password baru tidak boleh sama dengan password saat ini
+// maupun password lama di history
+func (u *passwordUsecase) ensureNotReused(ctx context.Context, user *domain.User, newPassword string) error {
+	same, _, err := u.passwordHasher.VerifyPassword(ctx, []byte(newPassword), user.PasswordHash)
+	if err != nil {
+		return err
+	}
 	if same {
 		return ErrPasswordSameAsOld
 	}
 
-	hashedNew, err := u.passwordHasher.HashPassword([]byte(newPassword))
+	return u.passwordPolicy.CheckHistory(ctx, user.ID, newPassword)
+}
+
+// hashPassword: pool hashing penuh diteruskan apa adanya (503 + retry), error lain ErrHashingPass
+func (u *passwordUsecase) hashPassword(ctx context.Context, password string) (string, error) {
+	hashed, err := u.passwordHasher.HashPassword(ctx, []byte(password))
+	if errors.Is(err, userPorts.ErrPasswordHasherBusy) {
+		return "", err
+	}
+	if err != nil {
+		return "", ErrHashingPass
+	}
+	return hashed, nil
+}
+
+// clearMustChange: password baru sudah lolos policy (termasuk breach check)
+func (u *passwordUsecase) clearMustChange(ctx context.Context, user *domain.User) error {
+	if !user.MustChangePassword {
+		return nil
+	}
+	return u.userRepo.SetMustChangePassword(ctx, user.ID, false)
+}
+
+// revokeSessions: force logout di semua device (refresh token family + session)
+func (u *passwordUsecase) revokeSessions(ctx context.Context, userID uint64) error {
+	families, err := u.refreshFamilyRepo.GetByUserID(ctx, userID)
 	if err != nil {
-		return ErrHashingPass
+		return err
 	}
-	return u.userRepo.UpdatePassword(ctx, id, hashedNew)
+
+	for _, family := range families {
+		// revoke semua refresh token dalam family
+		if err := u.refreshRepo.RevokeByFamily(ctx, family.ID); err != nil {
+			return err
+		}
+
+		// revoke family itu sendiri
+		if err := u.refreshFamilyRepo.Revoke(ctx, family.ID); err != nil {
+			return err
+		}
+	}
+
+	sessions, err := u.sessionRepo.GetActiveSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range sessions {
+		if err := u.sessionRepo.Logout(ctx, s.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func userInfo(user *domain.User) valueobjects.PasswordUserInfo {
+	return valueobjects.PasswordUserInfo{Username: user.Username, Email: user.Email}
+}
+
+// codePlain mengikat kode OTP ke user; prefix "reset" supaya tidak bentrok
+// dengan kode verifikasi email milik user yang sama
+func (u *passwordUsecase) codePlain(userID uint64, code string) string {
+	return "reset:" + strconv.FormatUint(userID, 10) + ":" + code
+}
+
+func (u *passwordUsecase) codeHash(userID uint64, code string) string {
+	return u.tokenVerifier.Hash(u.codePlain(userID, code))
 }
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	authPorts "github.com/dhanarrizky/Golang-template/internal/ports/auth"
+	otherPorts "github.com/dhanarrizky/Golang-template/internal/ports/others"
+	userPorts "github.com/dhanarrizky/Golang-template/internal/ports/users"
+)
+
+var (
+	ErrReauthCredentialRequired   = errors.New("password or verification code is required")
+	ErrSecondFactorNotEnrolled    = errors.New("second factor is not enrolled")
+	ErrInvalidReauthCredentials   = errors.New("invalid credentials")
+	ErrReauthUserNotFound         = errors.New("user not found")
+	ErrReauthUserIdentifierDecode = errors.New("internal server decode")
+)
+
+type ReauthResult struct {
+	AccessToken string
+	ExpiresAt   time.Time
+	AuthTime    time.Time
+	ACR         string
+}
+
+type ReauthUsecase interface {
+	// Reauthenticate membuktikan ulang identitas user yang sudah login (password atau second factor)
+	// dan mengembalikan access token elevated yang berumur pendek
+	Reauthenticate(ctx context.Context, userID, password, code string) (*ReauthResult, error)
+}
+
+type reauthUsecase struct {
+	userRepo       userPorts.UserRepository
+	passwordHasher userPorts.PasswordHasher
+	secondFactor   authPorts.SecondFactorVerifier // optional (TOTP)
+	tokenSigner    authPorts.TokenSigner
+	idCodec        otherPorts.PublicIDCodec
+	elevatedTTL    time.Duration
+}
+
+func NewReauthUsecase(
+	userRepo userPorts.UserRepository,
+	passwordHasher userPorts.PasswordHasher,
+	secondFactor authPorts.SecondFactorVerifier,
+	tokenSigner authPorts.TokenSigner,
+	idCodec otherPorts.PublicIDCodec,
+	elevatedTTL time.Duration,
+) ReauthUsecase {
+	return &reauthUsecase{
+		userRepo:       userRepo,
+		passwordHasher: passwordHasher,
+		secondFactor:   secondFactor,
+		tokenSigner:    tokenSigner,
+		idCodec:        idCodec,
+		elevatedTTL:    elevatedTTL,
+	}
+}
+
+// ================= REAUTHENTICATE =================
+
+func (u *reauthUsecase) Reauthenticate(
+	ctx context.Context,
+	userID, password, code string,
+) (*ReauthResult, error) {
+
+	if password == "" && code == "" {
+		return nil, ErrReauthCredentialRequired
+	}
+
+	id, err := u.idCodec.Decode(userID)
+	if err != nil {
+		return nil, ErrReauthUserIdentifierDecode
+	}
+
+	user, err := u.userRepo.GetByID(ctx, id)
+	if err != nil || user == nil {
+		return nil, ErrReauthUserNotFound
+	}
+
+	acr := valueobjects.ACRPassword
+
+	if code != "" {
+		if u.secondFactor == nil {
+			return nil, ErrSecondFactorNotEnrolled
+		}
+
+		enrolled, err := u.secondFactor.IsEnrolled(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !enrolled {
+			return nil, ErrSecondFactorNotEnrolled
+		}
+
+		ok, err := u.secondFactor.Verify(ctx, user.ID, code)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrInvalidReauthCredentials
+		}
+
+		acr = valueobjects.ACRMultiFactor
+	}
+
+	if password != "" {
+		matched, _, err := u.passwordHasher.VerifyPassword(ctx, []byte(password), user.PasswordHash)
+		if errors.Is(err, userPorts.ErrPasswordHasherBusy) {
+			return nil, err
+		}
+		if err != nil || !matched {
+			return nil, ErrInvalidReauthCredentials
+		}
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(u.elevatedTTL)
+
+	// "exp" meng-override TTL default signer → token elevated berumur pendek
+	claims := map[string]any{
+		"email":     user.Email,
+		"roles":     user.RoleID,
+		"auth_time": now.Unix(),
+		"acr":       acr,
+		"exp":       expiresAt.Unix(),
+	}
+
+	accessToken, err := u.tokenSigner.GenerateAccessToken(userID, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReauthResult{
+		AccessToken: accessToken,
+		ExpiresAt:   expiresAt,
+		AuthTime:    now,
+		ACR:         acr,
+	}, nil
+}
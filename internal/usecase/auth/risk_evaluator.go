@@ -0,0 +1,284 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	authPorts "github.com/dhanarrizky/Golang-template/internal/ports/auth"
+	otherPorts "github.com/dhanarrizky/Golang-template/internal/ports/others"
+	userPorts "github.com/dhanarrizky/Golang-template/internal/ports/users"
+)
+
+// Bobot skor per sinyal risiko
+const (
+	riskScoreNewDevice        = 25
+	riskScoreNewIP            = 10
+	riskScoreNewCountry       = 25
+	riskScoreSuspiciousIP     = 30
+	riskScoreMaliciousIP      = 100
+	riskScoreImpossibleTravel = 50
+
+	// selisih lokasi di bawah ini dianggap noise GeoIP
+	minTravelDistanceKm = 100
+)
+
+const (
+	RiskReasonNewDevice        = "new_device"
+	RiskReasonNewIP            = "new_ip"
+	RiskReasonNewCountry       = "new_country"
+	RiskReasonSuspiciousIP     = "suspicious_ip"
+	RiskReasonMaliciousIP      = "malicious_ip"
+	RiskReasonImpossibleTravel = "impossible_travel"
+)
+
+// LoginClient berisi metadata request yang dipakai untuk evaluasi risiko
+type LoginClient struct {
+	IPAddress string
+	UserAgent string
+}
+
+type RiskPolicy struct {
+	StepUpThreshold     int
+	BlockThreshold      int
+	ImpossibleTravelKmh float64
+	HistorySize         int
+}
+
+func DefaultRiskPolicy() RiskPolicy {
+	return RiskPolicy{
+		StepUpThreshold:     40,
+		BlockThreshold:      80,
+		ImpossibleTravelKmh: 900, // kira-kira kecepatan pesawat komersial
+		HistorySize:         20,
+	}
+}
+
+type RiskAssessment struct {
+	Score    int
+	Decision domain.RiskDecision
+	Reasons  []string
+
+	NewDevice   bool
+	NewLocation bool
+	Location    *valueobjects.GeoLocation
+
+	// EventID: LoginRiskEvent yang dicatat Evaluate, ditandai selesai lewat Complete
+	EventID uint64
+}
+
+func (a *RiskAssessment) add(score int, reason string) {
+	a.Score += score
+	a.Reasons = append(a.Reasons, reason)
+}
+
+type RiskEvaluator interface {
+	// Evaluate menilai login yang password-nya sudah valid dan mencatat hasilnya untuk audit
+	Evaluate(ctx context.Context, user *domain.User, client LoginClient) (*RiskAssessment, error)
+
+	// Complete dipanggil setelah login benar-benar berhasil (allow / step-up lolos);
+	// baru setelah itu lokasinya ikut jadi riwayat terpercaya
+	Complete(ctx context.Context, assessment *RiskAssessment) error
+}
+
+type riskEvaluator struct {
+	sessionRepo userPorts.UserSessionRepository
+	riskRepo    authPorts.LoginRiskEventRepository
+	geoResolver otherPorts.GeoIPResolver       // optional
+	reputation  otherPorts.IPReputationChecker // optional
+	policy      RiskPolicy
+}
+
+func NewRiskEvaluator(
+	sessionRepo userPorts.UserSessionRepository,
+	riskRepo authPorts.LoginRiskEventRepository,
+	geoResolver otherPorts.GeoIPResolver,
+	reputation otherPorts.IPReputationChecker,
+	policy RiskPolicy,
+) RiskEvaluator {
+	return &riskEvaluator{
+		sessionRepo: sessionRepo,
+		riskRepo:    riskRepo,
+		geoResolver: geoResolver,
+		reputation:  reputation,
+		policy:      policy,
+	}
+}
+
+// ================= EVALUATE =================
+
+func (e *riskEvaluator) Evaluate(
+	ctx context.Context,
+	user *domain.User,
+	client LoginClient,
+) (*RiskAssessment, error) {
+
+	now := time.Now()
+	assessment := &RiskAssessment{}
+
+	// 1. IP reputation
+	if e.reputation != nil {
+		switch e.reputation.Check(client.IPAddress) {
+		case valueobjects.IPReputationMalicious:
+			assessment.add(riskScoreMaliciousIP, RiskReasonMaliciousIP)
+		case valueobjects.IPReputationSuspicious:
+			assessment.add(riskScoreSuspiciousIP, RiskReasonSuspiciousIP)
+		}
+	}
+
+	// 2. Known device & IP dari riwayat session
+	// Login pertama tidak punya pembanding, jadi tidak dianggap "baru"
+	sessions, err := e.sessionRepo.GetRecentByUser(ctx, user.ID, e.policy.HistorySize)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sessions) > 0 {
+		if !isKnownUserAgent(sessions, client.UserAgent) {
+			assessment.NewDevice = true
+			assessment.add(riskScoreNewDevice, RiskReasonNewDevice)
+		}
+		if !isKnownIP(sessions, client.IPAddress) {
+			assessment.add(riskScoreNewIP, RiskReasonNewIP)
+		}
+	}
+
+	// 3. Lokasi (GeoIP offline) & impossible travel
+	history, err := e.riskRepo.GetRecentByUser(ctx, user.ID, e.policy.HistorySize)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.geoResolver != nil {
+		location, err := e.geoResolver.Lookup(client.IPAddress)
+		if err == nil && location != nil {
+			assessment.Location = location
+
+			if !isKnownCountry(history, location.Country) {
+				assessment.NewLocation = true
+				assessment.add(riskScoreNewCountry, RiskReasonNewCountry)
+			}
+
+			if last := lastTrustedLocation(history); last != nil &&
+				e.isImpossibleTravel(last, *location, now) {
+				assessment.add(riskScoreImpossibleTravel, RiskReasonImpossibleTravel)
+			}
+		}
+	}
+
+	assessment.Decision = e.decide(assessment.Score)
+
+	// 4. Audit trail
+	event := &domain.LoginRiskEvent{
+		UserID:    user.ID,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+		Score:     assessment.Score,
+		Decision:  assessment.Decision,
+		Reasons:   assessment.Reasons,
+		CreatedAt: now,
+	}
+	if loc := assessment.Location; loc != nil {
+		event.Country = loc.Country
+		event.City = loc.City
+		event.Latitude = &loc.Latitude
+		event.Longitude = &loc.Longitude
+	}
+
+	if err := e.riskRepo.Create(ctx, event); err != nil {
+		return nil, err
+	}
+	assessment.EventID = event.ID
+
+	return assessment, nil
+}
+
+func (e *riskEvaluator) Complete(ctx context.Context, assessment *RiskAssessment) error {
+	if assessment == nil || assessment.EventID == 0 {
+		return nil
+	}
+	return e.riskRepo.MarkCompleted(ctx, assessment.EventID)
+}
+
+func (e *riskEvaluator) decide(score int) domain.RiskDecision {
+	switch {
+	case score >= e.policy.BlockThreshold:
+		return domain.RiskDecisionBlock
+	case score >= e.policy.StepUpThreshold:
+		return domain.RiskDecisionStepUp
+	default:
+		return domain.RiskDecisionAllow
+	}
+}
+
+func (e *riskEvaluator) isImpossibleTravel(
+	last *domain.LoginRiskEvent,
+	current valueobjects.GeoLocation,
+	now time.Time,
+) bool {
+
+	previous := valueobjects.GeoLocation{
+		Latitude:  *last.Latitude,
+		Longitude: *last.Longitude,
+	}
+
+	distance := previous.DistanceKm(current)
+	if distance < minTravelDistanceKm {
+		return false
+	}
+
+	hours := now.Sub(last.CreatedAt).Hours()
+	if hours <= 0 {
+		return true
+	}
+
+	return distance/hours > e.policy.ImpossibleTravelKmh
+}
+
+// ================= HELPERS =================
+
+func isKnownUserAgent(sessions []*domain.UserSession, userAgent string) bool {
+	for _, s := range sessions {
+		if strings.EqualFold(s.UserAgent, userAgent) {
+			return true
+		}
+	}
+	return false
+}
+
+func isKnownIP(sessions []*domain.UserSession, ip string) bool {
+	for _, s := range sessions {
+		if s.IPAddress == ip {
+			return true
+		}
+	}
+	return false
+}
+
+// isKnownCountry: tanpa riwayat lokasi sama sekali, negara apa pun dianggap dikenal.
+// Hanya login yang selesai dihitung (step-up gagal / diblok tidak)
+func isKnownCountry(history []*domain.LoginRiskEvent, country string) bool {
+	located := false
+	for _, h := range history {
+		if !h.Completed || h.Country == "" {
+			continue
+		}
+		if h.Country == country {
+			return true
+		}
+		located = true
+	}
+	return !located
+}
+
+// lastTrustedLocation: login selesai terakhir yang punya koordinat
+func lastTrustedLocation(history []*domain.LoginRiskEvent) *domain.LoginRiskEvent {
+	for _, h := range history {
+		if h.Completed && h.HasLocation() {
+			return h
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+)
+
+type fakeSessionRepo struct {
+	sessions []*domain.UserSession
+}
+
+func (r *fakeSessionRepo) Create(ctx context.Context, s *domain.UserSession) error { return nil }
+func (r *fakeSessionRepo) UpdateLastSeen(ctx context.Context, id uint64) error     { return nil }
+func (r *fakeSessionRepo) Logout(ctx context.Context, id uint64) error             { return nil }
+func (r *fakeSessionRepo) GetActiveSessions(ctx context.Context, userID uint64) ([]*domain.UserSession, error) {
+	return r.sessions, nil
+}
+func (r *fakeSessionRepo) GetRecentByUser(ctx context.Context, userID uint64, limit int) ([]*domain.UserSession, error) {
+	return r.sessions, nil
+}
+
+// fakeRiskRepo menyimpan event urut lama → baru, GetRecentByUser mengembalikan terbaru dulu
+type fakeRiskRepo struct {
+	events []*domain.LoginRiskEvent
+}
+
+func (r *fakeRiskRepo) Create(ctx context.Context, e *domain.LoginRiskEvent) error {
+	e.ID = uint64(len(r.events) + 1)
+	r.events = append(r.events, e)
+	return nil
+}
+
+func (r *fakeRiskRepo) MarkCompleted(ctx context.Context, id uint64) error {
+	for _, e := range r.events {
+		if e.ID == id {
+			e.Completed = true
+		}
+	}
+	return nil
+}
+
+func (r *fakeRiskRepo) GetRecentByUser(ctx context.Context, userID uint64, limit int) ([]*domain.LoginRiskEvent, error) {
+	out := make([]*domain.LoginRiskEvent, 0, len(r.events))
+	for i := len(r.events) - 1; i >= 0 && len(out) < limit; i-- {
+		out = append(out, r.events[i])
+	}
+	return out, nil
+}
+
+type fakeGeoResolver map[string]*valueobjects.GeoLocation
+
+func (g fakeGeoResolver) Lookup(ip string) (*valueobjects.GeoLocation, error) {
+	return g[ip], nil
+}
+
+func newTestRiskEvaluator(riskRepo *fakeRiskRepo) RiskEvaluator {
+	sessions := &fakeSessionRepo{sessions: []*domain.UserSession{
+		{ID: 1, UserID: 1, IPAddress: "10.0.0.1", UserAgent: "owner-browser"},
+	}}
+	geo := fakeGeoResolver{
+		"10.0.0.1":    {Country: "ID", City: "Jakarta", Latitude: -6.2, Longitude: 106.8},
+		"203.0.113.9": {Country: "BR", City: "Sao Paulo", Latitude: -23.5, Longitude: -46.6},
+	}
+	return NewRiskEvaluator(sessions, riskRepo, geo, nil, DefaultRiskPolicy())
+}
+
+// riwayat pemilik akun: login selesai dari Jakarta sebulan lalu
+func ownerHistory() *fakeRiskRepo {
+	lat, lng := -6.2, 106.8
+	return &fakeRiskRepo{events: []*domain.LoginRiskEvent{{
+		ID:        1,
+		UserID:    1,
+		IPAddress: "10.0.0.1",
+		Country:   "ID",
+		Latitude:  &lat,
+		Longitude: &lng,
+		Decision:  domain.RiskDecisionAllow,
+		Completed: true,
+		CreatedAt: time.Now().Add(-30 * 24 * time.Hour),
+	}}}
+}
+
+// Step-up yang tidak pernah diselesaikan tidak boleh membuat negara penyerang
+// "dikenal": percobaan ulang harus tetap diminta step-up.
+func TestRiskEvaluator_FailedStepUpDoesNotTrustLocation(t *testing.T) {
+	ctx := context.Background()
+	riskRepo := ownerHistory()
+	evaluator := newTestRiskEvaluator(riskRepo)
+
+	user := &domain.User{ID: 1}
+	attacker := LoginClient{IPAddress: "203.0.113.9", UserAgent: "attacker-browser"}
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		assessment, err := evaluator.Evaluate(ctx, user, attacker)
+		if err != nil {
+			t.Fatalf("attempt %d: %v", attempt, err)
+		}
+		if assessment.Decision != domain.RiskDecisionStepUp {
+			t.Fatalf("attempt %d: decision = %s (score %d), want step_up", attempt, assessment.Decision, assessment.Score)
+		}
+		if !assessment.NewLocation {
+			t.Fatalf("attempt %d: country of an unfinished login counted as known", attempt)
+		}
+		// kode step-up tidak pernah dikirim / salah: Complete tidak dipanggil
+	}
+
+	if got := len(riskRepo.events); got != 4 {
+		t.Fatalf("audit events = %d, want every attempt recorded (4)", got)
+	}
+}
+
+func TestRiskEvaluator_CompletedStepUpTrustsLocation(t *testing.T) {
+	ctx := context.Background()
+	riskRepo := ownerHistory()
+	evaluator := newTestRiskEvaluator(riskRepo)
+
+	user := &domain.User{ID: 1}
+	traveller := LoginClient{IPAddress: "203.0.113.9", UserAgent: "owner-browser"}
+
+	first, err := evaluator.Evaluate(ctx, user, traveller)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first.NewLocation {
+		t.Fatal("first login from a new country should be flagged")
+	}
+
+	// pemilik akun lolos step-up / login diizinkan
+	if err := evaluator.Complete(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := evaluator.Evaluate(ctx, user, traveller)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.NewLocation {
+		t.Fatal("country of a completed login should be known")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
 	authPort "github.com/dhanarrizky/Golang-template/internal/ports/auth"
 	userPort "github.com/dhanarrizky/Golang-template/internal/ports/users"
 	"github.com/dhanarrizky/Golang-template/pkg/utils"
@@ -32,10 +33,12 @@ type RefreshResult struct {
 }
 
 type TokenUsecase interface {
+	// acr = LoginResult.ACR (valueobjects.ACRPassword / ACRMultiFactor)
 	IssueForLogin(
 		ctx context.Context,
 		user domain.User,
 		deviceName string,
+		acr string,
 	) (*LoginTokenResult, error)
 
 	Refresh(
@@ -78,8 +81,13 @@ func (u *tokenUsecase) IssueForLogin(
 	ctx context.Context,
 	user domain.User,
 	deviceName string,
+	acr string,
 ) (*LoginTokenResult, error) {
 
+	if acr == "" {
+		acr = valueobjects.ACRPassword
+	}
+
 	familyID, err := utils.GenerateID()
 	if err != nil {
 		return nil, err
@@ -112,8 +120,10 @@ func (u *tokenUsecase) IssueForLogin(
 	})
 
 	claims := map[string]any{
-		"email": user.Email,
-		"roles": user.RoleID,
+		"email":     user.Email,
+		"roles":     user.RoleID,
+		"auth_time": now.Unix(),
+		"acr":       acr,
 	}
 
 	accessToken, err := u.tokenSigner.GenerateAccessToken(user.ID, claims)
@@ -180,6 +190,8 @@ func (u *tokenUsecase) Refresh(
 		return nil, err
 	}
 
+	// auth_time sengaja tidak ikut: refresh bukan re-autentikasi,
+	// operasi sensitif tetap butuh /auth/reauthenticate
 	claims := map[string]any{
 		"email": user.Email,
 		"roles": user.RoleID,
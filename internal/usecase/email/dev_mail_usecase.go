@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/email"
+)
+
+var ErrDevMailNotFound = errors.New("captured email not found")
+
+const (
+	defaultDevMailListLimit = 20
+	maxDevMailListLimit     = 100
+)
+
+var (
+	// OTP dari pkg/email.GenerateOTP selalu 6 digit; tahun di footer (4 digit) tidak ikut
+	devMailCodePattern = regexp.MustCompile(`\b\d{6}\b`)
+	devMailLinkPattern = regexp.MustCompile(`https?://[^\s<>"')\]]+`)
+)
+
+// DevMailEntry: email yang ditangkap + OTP / link yang ditemukan di body,
+// supaya e2e test registrasi & forgot password tidak perlu parsing HTML sendiri
+type DevMailEntry struct {
+	ports.CapturedEmail
+
+	Codes  []string
+	Links  []string
+	Tokens []string // nilai query "token" dari Links
+}
+
+// DevMailUsecase hanya dipasang saat development (mail catcher)
+type DevMailUsecase interface {
+	// List urut terbaru dulu; recipient kosong = semua
+	List(ctx context.Context, recipient string, limit int) ([]*DevMailEntry, error)
+	Get(ctx context.Context, id uint64) (*DevMailEntry, error)
+	// Latest email terbaru untuk recipient
+	Latest(ctx context.Context, recipient string) (*DevMailEntry, error)
+	Clear(ctx context.Context) error
+}
+
+type devMailUsecase struct {
+	catcher ports.MailCatcher
+}
+
+func NewDevMailUsecase(catcher ports.MailCatcher) DevMailUsecase {
+	return &devMailUsecase{catcher: catcher}
+}
+
+func (u *devMailUsecase) List(
+	ctx context.Context,
+	recipient string,
+	limit int,
+) ([]*DevMailEntry, error) {
+
+	if limit <= 0 {
+		limit = defaultDevMailListLimit
+	}
+	if limit > maxDevMailListLimit {
+		limit = maxDevMailListLimit
+	}
+
+	entries := make([]*DevMailEntry, 0, limit)
+	for _, m := range u.catcher.Messages() {
+		if len(entries) == limit {
+			break
+		}
+		if recipient != "" && !sentTo(m, recipient) {
+			continue
+		}
+		entries = append(entries, toDevMailEntry(m))
+	}
+
+	return entries, nil
+}
+
+func (u *devMailUsecase) Get(ctx context.Context, id uint64) (*DevMailEntry, error) {
+	m, ok := u.catcher.Get(id)
+	if !ok {
+		return nil, ErrDevMailNotFound
+	}
+
+	return toDevMailEntry(m), nil
+}
+
+func (u *devMailUsecase) Latest(ctx context.Context, recipient string) (*DevMailEntry, error) {
+	entries, err := u.List(ctx, recipient, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, ErrDevMailNotFound
+	}
+
+	return entries[0], nil
+}
+
+func (u *devMailUsecase) Clear(ctx context.Context) error {
+	u.catcher.Reset()
+	return nil
+}
+
+// sentTo membandingkan alamat tanpa display name dan tanpa beda huruf besar/kecil
+func sentTo(m ports.CapturedEmail, recipient string) bool {
+	recipient = strings.ToLower(strings.TrimSpace(recipient))
+	for _, to := range m.To {
+		addr := strings.ToLower(to)
+		if i := strings.LastIndex(addr, "<"); i >= 0 {
+			addr = strings.TrimSuffix(addr[i+1:], ">")
+		}
+		if strings.TrimSpace(addr) == recipient {
+			return true
+		}
+	}
+	return false
+}
+
+func toDevMailEntry(m ports.CapturedEmail) *DevMailEntry {
+	entry := &DevMailEntry{
+		CapturedEmail: m,
+		Codes:         unique(devMailCodePattern.FindAllString(m.Text, -1)),
+		Links:         unique(devMailLinkPattern.FindAllString(m.Text, -1)),
+	}
+
+	for _, link := range entry.Links {
+		if u, err := url.Parse(link); err == nil {
+			if token := u.Query().Get("token"); token != "" {
+				entry.Tokens = append(entry.Tokens, token)
+			}
+		}
+	}
+
+	return entry
+}
+
+func unique(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/email"
+)
+
+// NotificationUsecase mengirim OTP, kode MFA dan alert keamanan lewat channel
+// pilihan user (User.NotificationChannel): email, SMS atau WhatsApp.
+// Semua pesan lewat outbox, jadi aman dipanggil di dalam transaksi.
+type NotificationUsecase interface {
+	// SendOTP return channel yang dipakai (email | sms | whatsapp)
+	SendOTP(ctx context.Context, user *domain.User, code, idempotencyKey string) (string, error)
+
+	// SendPhoneCode kode verifikasi ke nomor yang belum terverifikasi
+	SendPhoneCode(ctx context.Context, channel, phone, code, idempotencyKey string) error
+
+	SendLoginAlert(ctx context.Context, user *domain.User, alert ports.LoginAlert) error
+	SendAccountLocked(ctx context.Context, user *domain.User, notice ports.AccountLockedNotice) error
+	SendPasswordChanged(ctx context.Context, user *domain.User, notice ports.PasswordChangedNotice) error
+	SendPasswordExpiry(ctx context.Context, user *domain.User, notice ports.PasswordExpiryNotice) error
+}
+
+type notificationUsecase struct {
+	outbox           EmailOutboxUsecase
+	appName          string
+	otpExpiryMinutes int
+}
+
+func NewNotificationUsecase(
+	outbox EmailOutboxUsecase,
+	appName string,
+	otpExpiryMinutes int,
+) NotificationUsecase {
+	return &notificationUsecase{
+		outbox:           outbox,
+		appName:          appName,
+		otpExpiryMinutes: otpExpiryMinutes,
+	}
+}
+
+func (u *notificationUsecase) SendOTP(
+	ctx context.Context,
+	user *domain.User,
+	code, idempotencyKey string,
+) (string, error) {
+
+	channel := user.NotificationChannel()
+
+	if channel == valueobjects.ChannelEmail {
+		err := u.outbox.Enqueue(ctx, domain.EmailOutboxKindOTP, user.Email, user.Locale, idempotencyKey, otpPayload{Code: code})
+		return channel, err
+	}
+
+	return channel, u.outbox.EnqueueMessage(ctx, channel, *user.Phone, idempotencyKey, u.codeText(code))
+}
+
+func (u *notificationUsecase) SendPhoneCode(
+	ctx context.Context,
+	channel, phone, code, idempotencyKey string,
+) error {
+
+	return u.outbox.EnqueueMessage(ctx, channel, phone, idempotencyKey, u.codeText(code))
+}
+
+func (u *notificationUsecase) SendLoginAlert(
+	ctx context.Context,
+	user *domain.User,
+	alert ports.LoginAlert,
+) error {
+
+	key := fmt.Sprintf("login_alert:%d:%d", user.ID, alert.Time.UnixNano())
+
+	channel := user.NotificationChannel()
+	if channel == valueobjects.ChannelEmail {
+		return u.outbox.Enqueue(ctx, domain.EmailOutboxKindLoginAlert, user.Email, user.Locale, key, alert)
+	}
+
+	where := alert.IPAddress
+	if alert.Location != "" {
+		where = alert.Location + " (" + alert.IPAddress + ")"
+	}
+	text := fmt.Sprintf(
+		"%s: new sign-in from %s at %s UTC. If this wasn't you, change your password now.",
+		u.appName, where, alert.Time.UTC().Format("02 Jan 15:04"),
+	)
+
+	return u.outbox.EnqueueMessage(ctx, channel, *user.Phone, key, text)
+}
+
+func (u *notificationUsecase) SendAccountLocked(
+	ctx context.Context,
+	user *domain.User,
+	notice ports.AccountLockedNotice,
+) error {
+
+	key := fmt.Sprintf("account_locked:%d:%d", user.ID, time.Now().UnixNano())
+
+	channel := user.NotificationChannel()
+	if channel == valueobjects.ChannelEmail {
+		return u.outbox.Enqueue(ctx, domain.EmailOutboxKindAccountLocked, user.Email, user.Locale, key, notice)
+	}
+
+	text := u.appName + ": your account was locked after too many failed sign-ins."
+	if notice.UnlockURL != "" {
+		text += " Unlock: " + notice.UnlockURL
+	}
+
+	return u.outbox.EnqueueMessage(ctx, channel, *user.Phone, key, text)
+}
+
+func (u *notificationUsecase) SendPasswordChanged(
+	ctx context.Context,
+	user *domain.User,
+	notice ports.PasswordChangedNotice,
+) error {
+
+	key := fmt.Sprintf("password_changed:%d:%d", user.ID, notice.Time.UnixNano())
+
+	channel := user.NotificationChannel()
+	if channel == valueobjects.ChannelEmail {
+		return u.outbox.Enqueue(ctx, domain.EmailOutboxKindPasswordChanged, user.Email, user.Locale, key, notice)
+	}
+
+	text := u.appName + ": your password was changed and other sessions were signed out. If this wasn't you, reset your password now."
+
+	return u.outbox.EnqueueMessage(ctx, channel, *user.Phone, key, text)
+}
+
+// SendPasswordExpiry: satu peringatan per password (key memakai waktu ganti password)
+func (u *notificationUsecase) SendPasswordExpiry(
+	ctx context.Context,
+	user *domain.User,
+	notice ports.PasswordExpiryNotice,
+) error {
+
+	key := fmt.Sprintf("password_expiry:%d:%d", user.ID, user.PasswordChangedAt.UnixNano())
+
+	channel := user.NotificationChannel()
+	if channel == valueobjects.ChannelEmail {
+		return u.outbox.Enqueue(ctx, domain.EmailOutboxKindPasswordExpiry, user.Email, user.Locale, key, notice)
+	}
+
+	text := fmt.Sprintf(
+		"%s: your password expires on %s UTC. Sign in and change it to keep full access.",
+		u.appName, notice.ExpiresAt.UTC().Format("02 Jan 2006"),
+	)
+
+	return u.outbox.EnqueueMessage(ctx, channel, *user.Phone, key, text)
+}
+
+// codeText: pesan OTP pendek (muat 1 segmen SMS)
+func (u *notificationUsecase) codeText(code string) string {
+	return fmt.Sprintf(
+		"%s: your verification code is %s. It expires in %d minutes. Never share this code.",
+		u.appName, code, u.otpExpiryMinutes,
+	)
+}
@@ -2,47 +2,60 @@ package auth
 
 import (
 	"context"
+	"crypto/subtle"
+	"strconv"
 	"time"
 
 	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
 	ports "github.com/dhanarrizky/Golang-template/internal/ports/email"
+	otherPorts "github.com/dhanarrizky/Golang-template/internal/ports/others"
 )
 
 type OTPUsecase struct {
-	emailService ports.EmailSender
+	notifier     NotificationUsecase
 	otpRepo      ports.EmailOTPRepository
+	txManager    otherPorts.TransactionManager
 	expiryMinute int
+	maxAttempts  int
 }
 
+// maxAttempts: percobaan kode salah sebelum OTP dihapus (<= 0 → 5)
 func NewOTPUsecase(
-	email ports.EmailSender,
+	notifier NotificationUsecase,
 	repo ports.EmailOTPRepository,
+	txManager otherPorts.TransactionManager,
 	expiry int,
+	maxAttempts int,
 ) *OTPUsecase {
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+
 	return &OTPUsecase{
-		emailService: email,
+		notifier:     notifier,
 		otpRepo:      repo,
+		txManager:    txManager,
 		expiryMinute: expiry,
+		maxAttempts:  maxAttempts,
 	}
 }
 
+// RequestOTP simpan OTP (per email user + purpose, menggantikan OTP sebelumnya)
+// lalu kirim lewat channel pilihan user. Return channel yang dipakai (email | sms | whatsapp).
 func (u *OTPUsecase) RequestOTP(
 	ctx context.Context,
-	email string,
+	user *domain.User,
+	purpose string,
 	otp string,
 	hash string,
 	ip string,
 	ua string,
-) error {
+) (string, error) {
 
-	// 1️⃣ Kirim email
-	if err := u.emailService.SendOTP(email, otp); err != nil {
-		return err
-	}
-
-	// 2️⃣ Simpan OTP sebagai ENTITY
+	// 1️⃣ Simpan OTP sebagai ENTITY
 	entity := &domain.EmailOTP{
-		Email:   email,
+		Email:   user.Email,
+		Purpose: purpose,
 		OTPHash: hash,
 		ExpiredAt: time.Now().Add(
 			time.Minute * time.Duration(u.expiryMinute),
@@ -51,29 +64,54 @@ func (u *OTPUsecase) RequestOTP(
 		UserAgent: ua,
 	}
 
-	return u.otpRepo.Save(ctx, entity)
+	// 2️⃣ Pesan masuk outbox di transaksi yang sama, dikirim worker di background
+	// (SMTP / gateway SMS down tidak menggagalkan request, retry otomatis)
+	var channel string
+	err := u.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := u.otpRepo.Save(txCtx, entity); err != nil {
+			return err
+		}
+
+		var err error
+		channel, err = u.notifier.SendOTP(
+			txCtx,
+			user,
+			otp,
+			"otp:"+strconv.FormatUint(entity.ID, 10),
+		)
+		return err
+	})
+
+	return channel, err
 }
 
+// VerifyOTP: kode salah menambah Attempts; setelah maxAttempts OTP dihapus
+// (harus minta kode baru). Berhasil = OTP langsung diinvalidasi (sekali pakai)
 func (u *OTPUsecase) VerifyOTP(
 	ctx context.Context,
 	email string,
+	purpose string,
 	hash string,
 ) bool {
 
-	data, err := u.otpRepo.FindActiveByEmail(ctx, email)
-	if err != nil {
+	data, err := u.otpRepo.FindActiveByEmail(ctx, email, purpose)
+	if err != nil || data == nil {
 		return false
 	}
 
-	if time.Now().After(data.ExpiredAt) {
-		_ = u.otpRepo.DeleteByEmail(ctx, email)
+	if data.Attempts >= u.maxAttempts {
+		_ = u.otpRepo.DeleteByEmail(ctx, email, purpose)
 		return false
 	}
 
-	if data.OTPHash != hash {
+	if subtle.ConstantTimeCompare([]byte(data.OTPHash), []byte(hash)) != 1 {
+		attempts, err := u.otpRepo.IncrementAttempts(ctx, email, purpose)
+		if err == nil && attempts >= u.maxAttempts {
+			_ = u.otpRepo.DeleteByEmail(ctx, email, purpose)
+		}
 		return false
 	}
 
-	_ = u.otpRepo.DeleteByEmail(ctx, email)
+	_ = u.otpRepo.DeleteByEmail(ctx, email, purpose)
 	return true
 }
@@ -0,0 +1,408 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/email"
+	otherPorts "github.com/dhanarrizky/Golang-template/internal/ports/others"
+)
+
+var (
+	ErrOutboxMessageNotFound      = errors.New("outbox message not found")
+	ErrOutboxMessageNotReplayable = errors.New("only failed or pending messages can be replayed")
+	ErrOutboxInvalidStatus        = errors.New("invalid outbox status")
+	ErrOutboxIDDecode             = errors.New("invalid outbox message id")
+	ErrUnsupportedChannel         = errors.New("unsupported message channel")
+
+	errUnknownOutboxKind  = errors.New("unknown outbox message kind")
+	errOutboxLeaseExpired = errors.New("lease expired too many times before delivery finished")
+)
+
+const (
+	defaultOutboxListLimit = 20
+	maxOutboxListLimit     = 100
+)
+
+// EmailOutboxPolicy: retry dengan exponential backoff, dead setelah MaxAttempts
+type EmailOutboxPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	BatchSize   int
+	// Lease: pesan yang diambil worker tapi tidak selesai (crash) diambil ulang setelah Lease
+	Lease time.Duration
+}
+
+func DefaultEmailOutboxPolicy() EmailOutboxPolicy {
+	return EmailOutboxPolicy{
+		MaxAttempts: 8,
+		BaseDelay:   30 * time.Second,
+		MaxDelay:    time.Hour,
+		BatchSize:   20,
+		Lease:       2 * time.Minute,
+	}
+}
+
+// EmailOutboxEntry: ID sudah di-encode (public ID); payload tidak diekspos
+// karena bisa berisi OTP / link sekali pakai
+type EmailOutboxEntry struct {
+	ID            string
+	Kind          string
+	Recipient     string
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	SentAt        *time.Time
+	CreatedAt     time.Time
+}
+
+type EmailOutboxUsecase interface {
+	// Enqueue dipanggil di dalam TransactionManager.WithinTransaction supaya
+	// pesan hanya tersimpan jika perubahan bisnisnya ter-commit.
+	// Key yang sama tidak membuat pesan kedua.
+	// locale = User.Locale, kosong = EMAIL_LOCALE
+	Enqueue(ctx context.Context, kind, recipient, locale, idempotencyKey string, payload any) error
+
+	// EnqueueMessage pesan teks ke nomor E.164 lewat channel sms / whatsapp
+	EnqueueMessage(ctx context.Context, channel, phone, idempotencyKey, text string) error
+
+	// ProcessDue kirim satu batch pesan jatuh tempo, return jumlah yang diproses
+	ProcessDue(ctx context.Context) (int, error)
+
+	List(ctx context.Context, status string, limit, offset int) ([]*EmailOutboxEntry, error)
+	Get(ctx context.Context, id string) (*EmailOutboxEntry, error)
+	Replay(ctx context.Context, id string) error
+}
+
+type emailOutboxUsecase struct {
+	outboxRepo ports.EmailOutboxRepository
+	sender     ports.EmailSender
+	channels   map[string]otherPorts.MessageChannel
+	idCodec    otherPorts.PublicIDCodec
+	policy     EmailOutboxPolicy
+}
+
+func NewEmailOutboxUsecase(
+	outboxRepo ports.EmailOutboxRepository,
+	sender ports.EmailSender,
+	channels map[string]otherPorts.MessageChannel, // key: valueobjects.ChannelSMS / ChannelWhatsApp, boleh kosong
+	idCodec otherPorts.PublicIDCodec,
+	policy EmailOutboxPolicy,
+) EmailOutboxUsecase {
+	return &emailOutboxUsecase{
+		outboxRepo: outboxRepo,
+		sender:     sender,
+		channels:   channels,
+		idCodec:    idCodec,
+		policy:     policy,
+	}
+}
+
+// payload untuk SendOTP
+type otpPayload struct {
+	Code string `json:"code"`
+}
+
+// payload untuk pesan sms / whatsapp
+type textPayload struct {
+	Text string `json:"text"`
+}
+
+// ================= ENQUEUE =================
+
+func (u *emailOutboxUsecase) Enqueue(
+	ctx context.Context,
+	kind, recipient, locale, idempotencyKey string,
+	payload any,
+) error {
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = u.outboxRepo.Create(ctx, &domain.EmailOutboxMessage{
+		IdempotencyKey: idempotencyKey,
+		Kind:           kind,
+		Recipient:      recipient,
+		Locale:         locale,
+		Payload:        string(raw),
+		Status:         domain.EmailOutboxStatusPending,
+		NextAttemptAt:  time.Now(),
+	})
+	return err
+}
+
+func (u *emailOutboxUsecase) EnqueueMessage(
+	ctx context.Context,
+	channel, phone, idempotencyKey, text string,
+) error {
+
+	kind, ok := map[string]string{
+		valueobjects.ChannelSMS:      domain.EmailOutboxKindSMS,
+		valueobjects.ChannelWhatsApp: domain.EmailOutboxKindWhatsApp,
+	}[channel]
+	if !ok {
+		return ErrUnsupportedChannel
+	}
+
+	return u.Enqueue(ctx, kind, phone, "", idempotencyKey, textPayload{Text: text})
+}
+
+// ================= DELIVERY =================
+
+func (u *emailOutboxUsecase) ProcessDue(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	msgs, err := u.outboxRepo.ClaimDue(ctx, now, u.policy.BatchSize, now.Add(u.policy.Lease))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, m := range msgs {
+		// setiap lease yang kedaluwarsa sudah dihitung ClaimDue sebagai percobaan
+		if m.Attempts >= u.policy.MaxAttempts {
+			_, _ = u.outboxRepo.MarkFailed(ctx, m.ID, m.NextAttemptAt, m.Attempts, time.Now(), errOutboxLeaseExpired.Error(), true)
+			continue
+		}
+
+		if err := u.deliver(ctx, m); err != nil {
+			u.fail(ctx, m, err)
+			continue
+		}
+
+		updated, err := u.outboxRepo.MarkSent(ctx, m.ID, m.NextAttemptAt, time.Now())
+		if err != nil {
+			return 0, err
+		}
+		if !updated {
+			// pengiriman lebih lama dari Lease: worker lain sudah mengambil pesan ini
+			log.Printf("[OUTBOX] lease of message %d expired during delivery, it may be sent twice", m.ID)
+		}
+	}
+
+	return len(msgs), nil
+}
+
+func (u *emailOutboxUsecase) deliver(ctx context.Context, m *domain.EmailOutboxMessage) error {
+	decode := func(v any) error {
+		return json.Unmarshal([]byte(m.Payload), v)
+	}
+	to := ports.Recipient{Email: m.Recipient, Locale: m.Locale}
+
+	switch m.Kind {
+	case domain.EmailOutboxKindOTP:
+		var p otpPayload
+		if err := decode(&p); err != nil {
+			return err
+		}
+		return u.sender.SendOTP(to, p.Code)
+
+	case domain.EmailOutboxKindResetPassword:
+		var p ports.PasswordReset
+		if err := decode(&p); err != nil {
+			return err
+		}
+		return u.sender.SendResetPassword(to, p)
+
+	case domain.EmailOutboxKindPasswordChanged:
+		var p ports.PasswordChangedNotice
+		if err := decode(&p); err != nil {
+			return err
+		}
+		return u.sender.SendPasswordChanged(to, p)
+
+	case domain.EmailOutboxKindPasswordExpiry:
+		var p ports.PasswordExpiryNotice
+		if err := decode(&p); err != nil {
+			return err
+		}
+		return u.sender.SendPasswordExpiry(to, p)
+
+	case domain.EmailOutboxKindEmailVerification:
+		var p ports.EmailVerification
+		if err := decode(&p); err != nil {
+			return err
+		}
+		return u.sender.SendEmailVerification(to, p)
+
+	case domain.EmailOutboxKindLoginAlert:
+		var p ports.LoginAlert
+		if err := decode(&p); err != nil {
+			return err
+		}
+		return u.sender.SendLoginAlert(to, p)
+
+	case domain.EmailOutboxKindAccountLocked:
+		var p ports.AccountLockedNotice
+		if err := decode(&p); err != nil {
+			return err
+		}
+		return u.sender.SendAccountLocked(to, p)
+
+	case domain.EmailOutboxKindEmailChangeVerification:
+		var p ports.EmailChangeVerification
+		if err := decode(&p); err != nil {
+			return err
+		}
+		return u.sender.SendEmailChangeVerification(to, p)
+
+	case domain.EmailOutboxKindEmailChangeNotice:
+		var p ports.EmailChangeNotice
+		if err := decode(&p); err != nil {
+			return err
+		}
+		return u.sender.SendEmailChangeNotice(to, p)
+
+	case domain.EmailOutboxKindSMS, domain.EmailOutboxKindWhatsApp:
+		channel := u.channels[m.Kind]
+		if channel == nil {
+			// channel tidak dikonfigurasi di instance ini → tidak akan berhasil walau dicoba ulang
+			return fmt.Errorf("%w: %s channel is not configured", errUnknownOutboxKind, m.Kind)
+		}
+
+		var p textPayload
+		if err := decode(&p); err != nil {
+			return err
+		}
+		return channel.Send(ctx, m.Recipient, p.Text)
+	}
+
+	return fmt.Errorf("%w: %s", errUnknownOutboxKind, m.Kind)
+}
+
+func (u *emailOutboxUsecase) fail(ctx context.Context, m *domain.EmailOutboxMessage, cause error) {
+	attempts := m.Attempts + 1
+
+	// payload/kind rusak tidak akan berhasil walau dicoba ulang
+	var syntaxErr *json.SyntaxError
+	dead := attempts >= u.policy.MaxAttempts ||
+		errors.Is(cause, errUnknownOutboxKind) ||
+		errors.As(cause, &syntaxErr)
+
+	_, _ = u.outboxRepo.MarkFailed(ctx, m.ID, m.NextAttemptAt, attempts, time.Now().Add(u.backoff(attempts)), cause.Error(), dead)
+}
+
+// backoff: BaseDelay * 2^(attempts-1), maksimal MaxDelay, jitter ±20%
+// supaya retry banyak pesan tidak menumpuk di detik yang sama
+func (u *emailOutboxUsecase) backoff(attempts int) time.Duration {
+	delay := u.policy.BaseDelay
+	for i := 1; i < attempts && delay < u.policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > u.policy.MaxDelay {
+		delay = u.policy.MaxDelay
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(delay)/5+1)) * 2
+	return delay - delay/5 + jitter
+}
+
+// ================= ADMIN =================
+
+func (u *emailOutboxUsecase) List(
+	ctx context.Context,
+	status string,
+	limit, offset int,
+) ([]*EmailOutboxEntry, error) {
+
+	switch status {
+	case "",
+		domain.EmailOutboxStatusPending,
+		domain.EmailOutboxStatusProcessing,
+		domain.EmailOutboxStatusSent,
+		domain.EmailOutboxStatusDead:
+	default:
+		return nil, ErrOutboxInvalidStatus
+	}
+
+	if limit <= 0 {
+		limit = defaultOutboxListLimit
+	}
+	if limit > maxOutboxListLimit {
+		limit = maxOutboxListLimit
+	}
+
+	msgs, err := u.outboxRepo.List(ctx, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*EmailOutboxEntry, 0, len(msgs))
+	for _, m := range msgs {
+		entry, err := u.toEntry(m)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (u *emailOutboxUsecase) Get(ctx context.Context, id string) (*EmailOutboxEntry, error) {
+	m, err := u.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.toEntry(m)
+}
+
+func (u *emailOutboxUsecase) Replay(ctx context.Context, id string) error {
+	m, err := u.find(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if m.Status != domain.EmailOutboxStatusDead && m.Status != domain.EmailOutboxStatusPending {
+		return ErrOutboxMessageNotReplayable
+	}
+
+	return u.outboxRepo.Replay(ctx, m.ID, time.Now())
+}
+
+func (u *emailOutboxUsecase) find(ctx context.Context, id string) (*domain.EmailOutboxMessage, error) {
+	decoded, err := u.idCodec.Decode(id)
+	if err != nil {
+		return nil, ErrOutboxIDDecode
+	}
+
+	m, err := u.outboxRepo.GetByID(ctx, decoded)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, ErrOutboxMessageNotFound
+	}
+
+	return m, nil
+}
+
+func (u *emailOutboxUsecase) toEntry(m *domain.EmailOutboxMessage) (*EmailOutboxEntry, error) {
+	id, err := u.idCodec.Encode(m.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmailOutboxEntry{
+		ID:            id,
+		Kind:          m.Kind,
+		Recipient:     m.Recipient,
+		Status:        m.Status,
+		Attempts:      m.Attempts,
+		NextAttemptAt: m.NextAttemptAt,
+		LastError:     m.LastError,
+		SentAt:        m.SentAt,
+		CreatedAt:     m.CreatedAt,
+	}, nil
+}
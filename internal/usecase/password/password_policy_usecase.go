@@ -0,0 +1,188 @@
+package password
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	ports "github.com/dhanarrizky/Golang-template/internal/ports/others"
+	userPorts "github.com/dhanarrizky/Golang-template/internal/ports/users"
+)
+
+// PasswordPolicyUsecase: satu pintu validasi password baru, dipakai register,
+// change password dan reset password supaya aturannya tidak beda-beda
+type PasswordPolicyUsecase interface {
+	// Validate return *valueobjects.PasswordPolicyError berisi semua violation
+	Validate(ctx context.Context, password string, user valueobjects.PasswordUserInfo) error
+
+	// Breached: password ada di data breach; selalu false jika breach check mati
+	Breached(ctx context.Context, password string) (bool, error)
+
+	// Estimate kekuatan password + saran, username / email user ikut dinilai
+	Estimate(ctx context.Context, password string, user valueobjects.PasswordUserInfo) valueobjects.PasswordStrength
+
+	// CheckHistory: *valueobjects.PasswordPolicyError (rule reused) jika password
+	// sama dengan salah satu dari HistorySize password lama user
+	CheckHistory(ctx context.Context, userID uint64, password string) error
+
+	// Remember simpan hash password yang diganti; dipanggil di transaksi ganti password
+	Remember(ctx context.Context, userID uint64, oldHash string) error
+
+	// Policy yang aktif, untuk GET /auth/password-policy
+	Policy() valueobjects.PasswordPolicy
+}
+
+type passwordPolicyUsecase struct {
+	policy        valueobjects.PasswordPolicy
+	breachChecker ports.BreachedPasswordChecker // nil = tidak dicek
+	estimator     ports.PasswordStrengthEstimator
+	historyRepo   userPorts.PasswordHistoryRepository // nil = history tidak disimpan
+	hasher        userPorts.PasswordHasher
+}
+
+func NewPasswordPolicyUsecase(
+	policy valueobjects.PasswordPolicy,
+	breachChecker ports.BreachedPasswordChecker,
+	estimator ports.PasswordStrengthEstimator,
+	historyRepo userPorts.PasswordHistoryRepository,
+	hasher userPorts.PasswordHasher,
+) PasswordPolicyUsecase {
+	policy.BreachCheck = breachChecker != nil
+	if policy.BreachMinCount < 1 {
+		policy.BreachMinCount = 1
+	}
+	if estimator == nil {
+		policy.MinStrength = 0
+	}
+	if historyRepo == nil || hasher == nil || policy.HistorySize < 0 {
+		policy.HistorySize = 0
+	}
+
+	return &passwordPolicyUsecase{
+		policy:        policy,
+		breachChecker: breachChecker,
+		estimator:     estimator,
+		historyRepo:   historyRepo,
+		hasher:        hasher,
+	}
+}
+
+func (u *passwordPolicyUsecase) Validate(
+	ctx context.Context,
+	password string,
+	user valueobjects.PasswordUserInfo,
+) error {
+
+	err := u.policy.Validate(password, user)
+
+	var extra []valueobjects.PasswordRule
+
+	breached, berr := u.Breached(ctx, password)
+	if berr != nil {
+		// dataset / API breach bermasalah tidak boleh memblokir register & reset
+		log.Printf("breached password check failed: %v", berr)
+	}
+	if breached {
+		extra = append(extra, u.policy.Rule(valueobjects.PasswordRuleBreached))
+	}
+
+	if u.policy.MinStrength > 0 {
+		strength := u.Estimate(ctx, password, user)
+		if strength.Score < u.policy.MinStrength {
+			rule := u.policy.Rule(valueobjects.PasswordRuleStrength)
+			if strength.Warning != "" {
+				rule.Message += " (" + strength.Warning + ")"
+			}
+			extra = append(extra, rule)
+		}
+	}
+
+	if len(extra) == 0 {
+		return err
+	}
+
+	policyErr, ok := err.(*valueobjects.PasswordPolicyError)
+	if !ok {
+		policyErr = &valueobjects.PasswordPolicyError{}
+	}
+	policyErr.Violations = append(policyErr.Violations, extra...)
+
+	return policyErr
+}
+
+func (u *passwordPolicyUsecase) Breached(ctx context.Context, password string) (bool, error) {
+	if u.breachChecker == nil {
+		return false, nil
+	}
+
+	count, err := u.breachChecker.Count(ctx, password)
+	if err != nil {
+		return false, err
+	}
+
+	return count >= u.policy.BreachMinCount, nil
+}
+
+func (u *passwordPolicyUsecase) Estimate(
+	ctx context.Context,
+	password string,
+	user valueobjects.PasswordUserInfo,
+) valueobjects.PasswordStrength {
+
+	if u.estimator == nil {
+		return valueobjects.PasswordStrength{}
+	}
+	return u.estimator.Estimate(password, []string{user.Username, user.Email})
+}
+
+func (u *passwordPolicyUsecase) CheckHistory(ctx context.Context, userID uint64, password string) error {
+	if u.policy.HistorySize == 0 {
+		return nil
+	}
+
+	entries, err := u.historyRepo.GetRecent(ctx, userID, u.policy.HistorySize)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		// hash lama diverifikasi dengan pepper versinya sendiri
+		match, _, err := u.hasher.VerifyPassword(ctx, []byte(password), entry.PasswordHash)
+		if err != nil && (errors.Is(err, userPorts.ErrPasswordHasherBusy) || ctx.Err() != nil) {
+			return err // bukan masalah entry, jangan dilewati diam-diam
+		}
+		if err != nil {
+			// mis. pepper versi lama sudah dicabut: entry ini tidak bisa dicek lagi
+			log.Printf("password history entry %d not verifiable: %v", entry.ID, err)
+			continue
+		}
+		if match {
+			return &valueobjects.PasswordPolicyError{
+				Violations: []valueobjects.PasswordRule{u.policy.Rule(valueobjects.PasswordRuleReused)},
+			}
+		}
+	}
+
+	return nil
+}
+
+func (u *passwordPolicyUsecase) Remember(ctx context.Context, userID uint64, oldHash string) error {
+	if u.policy.HistorySize == 0 || oldHash == "" {
+		return nil
+	}
+
+	if err := u.historyRepo.Create(ctx, &domain.PasswordHistory{
+		UserID:       userID,
+		PasswordHash: oldHash,
+	}); err != nil {
+		return err
+	}
+
+	return u.historyRepo.Prune(ctx, userID, u.policy.HistorySize)
+}
+
+func (u *passwordPolicyUsecase) Policy() valueobjects.PasswordPolicy {
+	return u.policy
+}
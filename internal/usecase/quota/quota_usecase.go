@@ -0,0 +1,706 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	authPorts "github.com/dhanarrizky/Golang-template/internal/ports/auth"
+	otherPorts "github.com/dhanarrizky/Golang-template/internal/ports/others"
+	rolePorts "github.com/dhanarrizky/Golang-template/internal/ports/roles"
+	userPorts "github.com/dhanarrizky/Golang-template/internal/ports/users"
+)
+
+// Sumber limit yang berlaku untuk subject
+const (
+	QuotaSourceDefault  = "default"
+	QuotaSourcePlan     = "plan"
+	QuotaSourceOverride = "override"
+)
+
+const (
+	flushBatchSize = 500
+
+	// counter tetap hidup sebentar setelah periode berakhir supaya sempat di-flush
+	counterGracePeriod = time.Hour
+
+	// plan & override di-cache per replica; perubahan admin di replica lain
+	// berlaku paling lambat setelah TTL ini
+	limitsCacheTTL = time.Minute
+
+	// batas jumlah subject di cache; entry expired dibuang tiap limitsCacheTTL,
+	// saat penuh entry acak dibuang (subject API key / service account tidak terbatas)
+	limitsCacheMaxEntries = 10000
+)
+
+var (
+	ErrQuotaInvalidSubject   = errors.New("invalid quota subject")
+	ErrQuotaSubjectNotFound  = errors.New("quota subject not found")
+	ErrQuotaRoleNotFound     = errors.New("role not found")
+	ErrQuotaRoleIDDecode     = errors.New("invalid role id")
+	ErrQuotaActorIDDecode    = errors.New("internal server decode")
+	ErrQuotaNegativeLimit    = errors.New("limit must not be negative")
+	ErrQuotaEmptyOverride    = errors.New("at least one of daily_limit or monthly_limit is required")
+	ErrQuotaReasonIsRequired = errors.New("reason is required")
+	ErrQuotaOverrideExpired  = errors.New("expires_at must be in the future")
+	ErrQuotaPlanNameRequired = errors.New("plan name is required")
+	ErrQuotaOverrideNotFound = errors.New("quota override not found")
+	ErrQuotaPlanNotFound     = errors.New("quota plan not found")
+	errQuotaMalformedKey     = errors.New("malformed quota counter key")
+)
+
+// QuotaPlanEntry: plan kuota per role, RoleID = public id
+type QuotaPlanEntry struct {
+	RoleID       string
+	Name         string
+	DailyLimit   int64
+	MonthlyLimit int64
+	UpdatedAt    time.Time
+}
+
+// QuotaOverrideInput: limit nil = ikut plan, 0 = tanpa batas
+type QuotaOverrideInput struct {
+	Subject      valueobjects.QuotaSubject
+	DailyLimit   *int64
+	MonthlyLimit *int64
+	Reason       string
+	ExpiresAt    *time.Time
+}
+
+type QuotaUsecase interface {
+	// Consume mencatat satu request. Jika kuota habis, Allowed = false
+	// dan request tersebut tidak ikut dihitung.
+	Consume(ctx context.Context, subject valueobjects.QuotaSubject) (*valueobjects.QuotaStatus, error)
+	// GetUsage membaca usage tanpa mengonsumsi kuota
+	GetUsage(ctx context.Context, subject valueobjects.QuotaSubject) (*valueobjects.QuotaStatus, error)
+
+	// Flush memindahkan counter yang berubah ke Postgres (dipanggil berkala)
+	Flush(ctx context.Context) (int, error)
+
+	// Admin
+	ListPlans(ctx context.Context) ([]*QuotaPlanEntry, error)
+	SetRolePlan(ctx context.Context, roleID, name string, daily, monthly int64) (*QuotaPlanEntry, error)
+	RemoveRolePlan(ctx context.Context, roleID string) error
+	SetOverride(ctx context.Context, actorID string, input QuotaOverrideInput) error
+	RemoveOverride(ctx context.Context, subject valueobjects.QuotaSubject) error
+}
+
+type resolvedLimits struct {
+	limits    valueobjects.QuotaLimits
+	plan      string
+	source    string
+	expiresAt time.Time
+}
+
+type quotaUsecase struct {
+	counter      otherPorts.QuotaCounter
+	planRepo     authPorts.QuotaPlanRepository
+	overrideRepo authPorts.QuotaOverrideRepository
+	usageRepo    authPorts.QuotaUsageRepository
+	userRepo     userPorts.UserRepository
+	roleRepo     rolePorts.RoleRepository
+	idCodec      otherPorts.PublicIDCodec
+
+	// dipakai jika subject tidak punya plan (service account, API key, role tanpa plan)
+	defaultLimits valueobjects.QuotaLimits
+
+	mu        sync.Mutex
+	cache     map[valueobjects.QuotaSubject]*resolvedLimits
+	lastSweep time.Time
+}
+
+func NewQuotaUsecase(
+	counter otherPorts.QuotaCounter,
+	planRepo authPorts.QuotaPlanRepository,
+	overrideRepo authPorts.QuotaOverrideRepository,
+	usageRepo authPorts.QuotaUsageRepository,
+	userRepo userPorts.UserRepository,
+	roleRepo rolePorts.RoleRepository,
+	idCodec otherPorts.PublicIDCodec,
+	defaultLimits valueobjects.QuotaLimits,
+) QuotaUsecase {
+	return &quotaUsecase{
+		counter:       counter,
+		planRepo:      planRepo,
+		overrideRepo:  overrideRepo,
+		usageRepo:     usageRepo,
+		userRepo:      userRepo,
+		roleRepo:      roleRepo,
+		idCodec:       idCodec,
+		defaultLimits: defaultLimits,
+		cache:         make(map[valueobjects.QuotaSubject]*resolvedLimits),
+	}
+}
+
+func quotaPeriods() []valueobjects.QuotaPeriod {
+	return []valueobjects.QuotaPeriod{
+		valueobjects.QuotaPeriodDaily,
+		valueobjects.QuotaPeriodMonthly,
+	}
+}
+
+// ================= CONSUME =================
+
+func (u *quotaUsecase) Consume(
+	ctx context.Context,
+	subject valueobjects.QuotaSubject,
+) (*valueobjects.QuotaStatus, error) {
+
+	resolved, err := u.resolveLimits(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	periods := quotaPeriods()
+	used := make([]int64, len(periods))
+	allowed := true
+
+	for i, period := range periods {
+		start, end := period.Bounds(now)
+
+		value, err := u.increment(ctx, subject, period, start, end.Sub(now), 1)
+		if err != nil {
+			return nil, err
+		}
+		used[i] = value
+
+		if limit := resolved.limits.For(period); limit > 0 && value > limit {
+			allowed = false
+		}
+	}
+
+	// request yang ditolak tidak memakan kuota
+	if !allowed {
+		for i, period := range periods {
+			start, end := period.Bounds(now)
+			key := counterKey(subject, period, start)
+
+			if _, _, err := u.counter.Increment(ctx, key, -1, end.Sub(now)+counterGracePeriod); err != nil {
+				return nil, err
+			}
+			used[i]--
+		}
+	}
+
+	status := u.newStatus(subject, resolved, now, used)
+	status.Allowed = allowed
+	return status, nil
+}
+
+// ================= GET USAGE =================
+
+func (u *quotaUsecase) GetUsage(
+	ctx context.Context,
+	subject valueobjects.QuotaSubject,
+) (*valueobjects.QuotaStatus, error) {
+
+	resolved, err := u.resolveLimits(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	periods := quotaPeriods()
+	used := make([]int64, len(periods))
+
+	for i, period := range periods {
+		start, _ := period.Bounds(now)
+
+		live, err := u.counter.Get(ctx, counterKey(subject, period, start))
+		if err != nil {
+			return nil, err
+		}
+
+		// counter bisa hilang (restart / Redis flush); Postgres jadi batas bawah
+		persisted, err := u.usageRepo.GetCount(ctx, string(subject.Type), subject.ID, string(period), start)
+		if err != nil {
+			return nil, err
+		}
+
+		used[i] = max(live, persisted)
+	}
+
+	status := u.newStatus(subject, resolved, now, used)
+	status.Allowed = true
+	for _, w := range status.Windows {
+		if w.Limit > 0 && w.Used >= w.Limit {
+			status.Allowed = false
+		}
+	}
+	return status, nil
+}
+
+// ================= FLUSH =================
+
+func (u *quotaUsecase) Flush(ctx context.Context) (int, error) {
+	flushed := 0
+
+	for {
+		keys, err := u.counter.PopDirty(ctx, flushBatchSize)
+		if err != nil {
+			return flushed, err
+		}
+		if len(keys) == 0 {
+			return flushed, nil
+		}
+
+		now := time.Now()
+		usages := make([]*domain.QuotaUsage, 0, len(keys))
+		for _, key := range keys {
+			subject, period, start, err := parseCounterKey(key)
+			if err != nil {
+				continue
+			}
+
+			value, err := u.counter.Get(ctx, key)
+			if err != nil {
+				return flushed, err
+			}
+			if value <= 0 {
+				continue // sudah expired
+			}
+
+			usages = append(usages, &domain.QuotaUsage{
+				SubjectType: string(subject.Type),
+				SubjectID:   subject.ID,
+				Period:      string(period),
+				PeriodStart: start,
+				Count:       value,
+				UpdatedAt:   now,
+			})
+		}
+
+		if err := u.usageRepo.Upsert(ctx, usages); err != nil {
+			// tandai dirty lagi supaya ikut flush berikutnya
+			for _, usage := range usages {
+				subject := valueobjects.QuotaSubject{
+					Type: valueobjects.QuotaSubjectType(usage.SubjectType),
+					ID:   usage.SubjectID,
+				}
+				period := valueobjects.QuotaPeriod(usage.Period)
+				_, end := period.Bounds(usage.PeriodStart)
+				_, _, _ = u.counter.Increment(ctx, counterKey(subject, period, usage.PeriodStart), 0, end.Sub(now)+counterGracePeriod)
+			}
+			return flushed, err
+		}
+
+		flushed += len(usages)
+		if len(keys) < flushBatchSize {
+			return flushed, nil
+		}
+	}
+}
+
+// ================= ADMIN: PLAN =================
+
+func (u *quotaUsecase) ListPlans(ctx context.Context) ([]*QuotaPlanEntry, error) {
+	plans, err := u.planRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*QuotaPlanEntry, 0, len(plans))
+	for _, plan := range plans {
+		entry, err := u.toPlanEntry(plan)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (u *quotaUsecase) SetRolePlan(
+	ctx context.Context,
+	roleID, name string,
+	daily, monthly int64,
+) (*QuotaPlanEntry, error) {
+
+	if name == "" {
+		return nil, ErrQuotaPlanNameRequired
+	}
+	if daily < 0 || monthly < 0 {
+		return nil, ErrQuotaNegativeLimit
+	}
+
+	id, err := u.idCodec.Decode(roleID)
+	if err != nil {
+		return nil, ErrQuotaRoleIDDecode
+	}
+
+	role, err := u.roleRepo.GetByID(ctx, id)
+	if err != nil || role == nil {
+		return nil, ErrQuotaRoleNotFound
+	}
+
+	plan := &domain.QuotaPlan{
+		RoleID:       role.ID,
+		Name:         name,
+		DailyLimit:   daily,
+		MonthlyLimit: monthly,
+	}
+	if err := u.planRepo.Upsert(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	// satu plan bisa dipakai banyak user → buang seluruh cache
+	u.invalidate(nil)
+	return u.toPlanEntry(plan)
+}
+
+func (u *quotaUsecase) RemoveRolePlan(ctx context.Context, roleID string) error {
+	id, err := u.idCodec.Decode(roleID)
+	if err != nil {
+		return ErrQuotaRoleIDDecode
+	}
+
+	plan, err := u.planRepo.GetByRoleID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if plan == nil {
+		return ErrQuotaPlanNotFound
+	}
+
+	if err := u.planRepo.DeleteByRoleID(ctx, id); err != nil {
+		return err
+	}
+
+	u.invalidate(nil)
+	return nil
+}
+
+// ================= ADMIN: OVERRIDE =================
+
+func (u *quotaUsecase) SetOverride(
+	ctx context.Context,
+	actorID string,
+	input QuotaOverrideInput,
+) error {
+
+	if err := validateSubject(input.Subject); err != nil {
+		return err
+	}
+	if input.Reason == "" {
+		return ErrQuotaReasonIsRequired
+	}
+	if input.DailyLimit == nil && input.MonthlyLimit == nil {
+		return ErrQuotaEmptyOverride
+	}
+	if (input.DailyLimit != nil && *input.DailyLimit < 0) ||
+		(input.MonthlyLimit != nil && *input.MonthlyLimit < 0) {
+		return ErrQuotaNegativeLimit
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return ErrQuotaOverrideExpired
+	}
+
+	actor, err := u.idCodec.Decode(actorID)
+	if err != nil {
+		return ErrQuotaActorIDDecode
+	}
+
+	if input.Subject.Type == valueobjects.QuotaSubjectUser {
+		if _, err := u.findUser(ctx, input.Subject.ID); err != nil {
+			return err
+		}
+	}
+
+	override := &domain.QuotaOverride{
+		SubjectType:  string(input.Subject.Type),
+		SubjectID:    input.Subject.ID,
+		DailyLimit:   input.DailyLimit,
+		MonthlyLimit: input.MonthlyLimit,
+		Reason:       input.Reason,
+		CreatedBy:    actor,
+		ExpiresAt:    input.ExpiresAt,
+	}
+	if err := u.overrideRepo.Upsert(ctx, override); err != nil {
+		return err
+	}
+
+	u.invalidate(&input.Subject)
+	return nil
+}
+
+func (u *quotaUsecase) RemoveOverride(
+	ctx context.Context,
+	subject valueobjects.QuotaSubject,
+) error {
+
+	if err := validateSubject(subject); err != nil {
+		return err
+	}
+
+	override, err := u.overrideRepo.GetBySubject(ctx, string(subject.Type), subject.ID)
+	if err != nil {
+		return err
+	}
+	if override == nil {
+		return ErrQuotaOverrideNotFound
+	}
+
+	if err := u.overrideRepo.Delete(ctx, string(subject.Type), subject.ID); err != nil {
+		return err
+	}
+
+	u.invalidate(&subject)
+	return nil
+}
+
+// ================= HELPERS =================
+
+// resolveLimits: default → plan role (khusus user) → override admin
+func (u *quotaUsecase) resolveLimits(
+	ctx context.Context,
+	subject valueobjects.QuotaSubject,
+) (*resolvedLimits, error) {
+
+	if err := validateSubject(subject); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	u.mu.Lock()
+	cached, ok := u.cache[subject]
+	u.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached, nil
+	}
+
+	resolved := &resolvedLimits{
+		limits:    u.defaultLimits,
+		plan:      QuotaSourceDefault,
+		source:    QuotaSourceDefault,
+		expiresAt: now.Add(limitsCacheTTL),
+	}
+
+	if subject.Type == valueobjects.QuotaSubjectUser {
+		user, err := u.findUser(ctx, subject.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		plan, err := u.planRepo.GetByRoleID(ctx, user.RoleID)
+		if err != nil {
+			return nil, err
+		}
+		if plan != nil {
+			resolved.limits = valueobjects.QuotaLimits{
+				Daily:   plan.DailyLimit,
+				Monthly: plan.MonthlyLimit,
+			}
+			resolved.plan = plan.Name
+			resolved.source = QuotaSourcePlan
+		}
+	}
+
+	override, err := u.overrideRepo.GetBySubject(ctx, string(subject.Type), subject.ID)
+	if err != nil {
+		return nil, err
+	}
+	if override != nil && override.IsActive(now) {
+		if override.DailyLimit != nil {
+			resolved.limits.Daily = *override.DailyLimit
+		}
+		if override.MonthlyLimit != nil {
+			resolved.limits.Monthly = *override.MonthlyLimit
+		}
+		resolved.source = QuotaSourceOverride
+
+		// override yang akan expired tidak boleh tertahan di cache
+		if override.ExpiresAt != nil && override.ExpiresAt.Before(resolved.expiresAt) {
+			resolved.expiresAt = *override.ExpiresAt
+		}
+	}
+
+	u.store(subject, resolved, now)
+	return resolved, nil
+}
+
+// store menyimpan limit ke cache tanpa membuatnya tumbuh tanpa batas
+func (u *quotaUsecase) store(subject valueobjects.QuotaSubject, resolved *resolvedLimits, now time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	// subject yang tidak aktif lagi tidak boleh menumpuk
+	if now.Sub(u.lastSweep) >= limitsCacheTTL {
+		for s, c := range u.cache {
+			if !now.Before(c.expiresAt) {
+				delete(u.cache, s)
+			}
+		}
+		u.lastSweep = now
+	}
+
+	// masih penuh: buang entry sembarang (urutan map acak), paling mahal satu query ulang
+	if _, exists := u.cache[subject]; !exists {
+		for s := range u.cache {
+			if len(u.cache) < limitsCacheMaxEntries {
+				break
+			}
+			delete(u.cache, s)
+		}
+	}
+
+	u.cache[subject] = resolved
+}
+
+// invalidate membuang cache satu subject, atau seluruh cache jika nil
+func (u *quotaUsecase) invalidate(subject *valueobjects.QuotaSubject) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if subject == nil {
+		u.cache = make(map[valueobjects.QuotaSubject]*resolvedLimits)
+		return
+	}
+	delete(u.cache, *subject)
+}
+
+// increment menaikkan counter; key baru di-seed dari Postgres
+// supaya usage tidak ter-reset setelah Redis restart
+func (u *quotaUsecase) increment(
+	ctx context.Context,
+	subject valueobjects.QuotaSubject,
+	period valueobjects.QuotaPeriod,
+	start time.Time,
+	untilEnd time.Duration,
+	delta int64,
+) (int64, error) {
+
+	key := counterKey(subject, period, start)
+	ttl := untilEnd + counterGracePeriod
+
+	value, created, err := u.counter.Increment(ctx, key, delta, ttl)
+	if err != nil {
+		return 0, err
+	}
+	if !created {
+		return value, nil
+	}
+
+	persisted, err := u.usageRepo.GetCount(ctx, string(subject.Type), subject.ID, string(period), start)
+	if err != nil {
+		return 0, err
+	}
+	if persisted == 0 {
+		return value, nil
+	}
+
+	value, _, err = u.counter.Increment(ctx, key, persisted, ttl)
+	return value, err
+}
+
+func (u *quotaUsecase) findUser(ctx context.Context, publicID string) (*domain.User, error) {
+	id, err := u.idCodec.Decode(publicID)
+	if err != nil {
+		return nil, ErrQuotaSubjectNotFound
+	}
+
+	user, err := u.userRepo.GetByID(ctx, id)
+	if err != nil || user == nil {
+		return nil, ErrQuotaSubjectNotFound
+	}
+	return user, nil
+}
+
+func (u *quotaUsecase) newStatus(
+	subject valueobjects.QuotaSubject,
+	resolved *resolvedLimits,
+	now time.Time,
+	used []int64,
+) *valueobjects.QuotaStatus {
+
+	status := &valueobjects.QuotaStatus{
+		Subject: subject,
+		Plan:    resolved.plan,
+		Source:  resolved.source,
+	}
+
+	for i, period := range quotaPeriods() {
+		_, end := period.Bounds(now)
+		limit := resolved.limits.For(period)
+
+		remaining := int64(-1)
+		if limit > 0 {
+			remaining = max(limit-used[i], 0)
+		}
+
+		status.Windows = append(status.Windows, valueobjects.QuotaWindow{
+			Period:    period,
+			Limit:     limit,
+			Used:      used[i],
+			Remaining: remaining,
+			ResetAt:   end,
+		})
+	}
+
+	return status
+}
+
+func (u *quotaUsecase) toPlanEntry(plan *domain.QuotaPlan) (*QuotaPlanEntry, error) {
+	roleID, err := u.idCodec.Encode(plan.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QuotaPlanEntry{
+		RoleID:       roleID,
+		Name:         plan.Name,
+		DailyLimit:   plan.DailyLimit,
+		MonthlyLimit: plan.MonthlyLimit,
+		UpdatedAt:    plan.UpdatedAt,
+	}, nil
+}
+
+func validateSubject(subject valueobjects.QuotaSubject) error {
+	if subject.ID == "" {
+		return ErrQuotaInvalidSubject
+	}
+
+	switch subject.Type {
+	case valueobjects.QuotaSubjectUser,
+		valueobjects.QuotaSubjectServiceAccount,
+		valueobjects.QuotaSubjectAPIKey:
+		return nil
+	default:
+		return ErrQuotaInvalidSubject
+	}
+}
+
+// counterKey: "quota:<type>:<period>:<period start unix>:<subject id>"
+func counterKey(
+	subject valueobjects.QuotaSubject,
+	period valueobjects.QuotaPeriod,
+	start time.Time,
+) string {
+	return fmt.Sprintf("quota:%s:%s:%d:%s", subject.Type, period, start.Unix(), subject.ID)
+}
+
+func parseCounterKey(key string) (valueobjects.QuotaSubject, valueobjects.QuotaPeriod, time.Time, error) {
+	parts := strings.SplitN(key, ":", 5)
+	if len(parts) != 5 || parts[0] != "quota" || parts[4] == "" {
+		return valueobjects.QuotaSubject{}, "", time.Time{}, errQuotaMalformedKey
+	}
+
+	unix, err := strconv.ParseInt(parts[3], 10, 64)
+	if err != nil {
+		return valueobjects.QuotaSubject{}, "", time.Time{}, errQuotaMalformedKey
+	}
+
+	subject := valueobjects.QuotaSubject{
+		Type: valueobjects.QuotaSubjectType(parts[1]),
+		ID:   parts[4],
+	}
+	return subject, valueobjects.QuotaPeriod(parts[2]), time.Unix(unix, 0).UTC(), nil
+}
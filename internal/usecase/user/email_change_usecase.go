@@ -0,0 +1,344 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	authPorts "github.com/dhanarrizky/Golang-template/internal/ports/auth"
+	emailPorts "github.com/dhanarrizky/Golang-template/internal/ports/email"
+	otherPorts "github.com/dhanarrizky/Golang-template/internal/ports/others"
+	userPorts "github.com/dhanarrizky/Golang-template/internal/ports/users"
+	emailUC "github.com/dhanarrizky/Golang-template/internal/usecase/email"
+)
+
+var (
+	ErrEmailChangeSameAddress  = errors.New("new email must be different from the current email")
+	ErrInvalidEmailChangeToken = errors.New("invalid or expired email change link")
+	ErrEmailRevertConflict     = errors.New("the original email is now used by another account")
+)
+
+// EmailChangePolicy: URL halaman frontend, token ditambahkan sebagai ?token=
+type EmailChangePolicy struct {
+	VerifyURL string
+	RevertURL string
+	VerifyTTL time.Duration
+	// RevertTTL lebih panjang dari VerifyTTL supaya pemilik lama masih bisa
+	// mengembalikan email setelah perubahan dikonfirmasi
+	RevertTTL time.Duration
+}
+
+func DefaultEmailChangePolicy() EmailChangePolicy {
+	return EmailChangePolicy{
+		VerifyTTL: 24 * time.Hour,
+		RevertTTL: 7 * 24 * time.Hour,
+	}
+}
+
+type PendingEmailChange struct {
+	NewEmail  string
+	ExpiresAt time.Time
+}
+
+type EmailChangeUsecase interface {
+	// RequestChange cek ulang password, kirim link verifikasi ke alamat baru
+	// dan notifikasi + link revert ke alamat lama. Email belum berubah.
+	RequestChange(ctx context.Context, userID, newEmail, password string) (*PendingEmailChange, error)
+
+	// Confirm dari link di alamat baru; email baru dipakai & terverifikasi
+	Confirm(ctx context.Context, userID, token string) error
+
+	// Revert dari link di alamat lama: batalkan perubahan yang masih pending,
+	// atau kembalikan alamat lama dan cabut semua session jika sudah dikonfirmasi
+	Revert(ctx context.Context, token string) error
+}
+
+type emailChangeUsecase struct {
+	userRepo          userPorts.UserRepository
+	tokenRepo         emailPorts.EmailVerificationTokenRepository
+	refreshRepo       authPorts.RefreshTokenRepository
+	refreshFamilyRepo authPorts.RefreshTokenFamilyRepository
+	passwordHasher    userPorts.PasswordHasher
+	tokenGenerator    otherPorts.TokenGenerator
+	tokenVerifier     otherPorts.TokenVerifier
+	outbox            emailUC.EmailOutboxUsecase
+	txManager         otherPorts.TransactionManager
+	idCodec           otherPorts.PublicIDCodec
+	policy            EmailChangePolicy
+}
+
+func NewEmailChangeUsecase(
+	userRepo userPorts.UserRepository,
+	tokenRepo emailPorts.EmailVerificationTokenRepository,
+	refreshRepo authPorts.RefreshTokenRepository,
+	refreshFamilyRepo authPorts.RefreshTokenFamilyRepository,
+	passwordHasher userPorts.PasswordHasher,
+	tokenGenerator otherPorts.TokenGenerator,
+	tokenVerifier otherPorts.TokenVerifier,
+	outbox emailUC.EmailOutboxUsecase,
+	txManager otherPorts.TransactionManager,
+	idCodec otherPorts.PublicIDCodec,
+	policy EmailChangePolicy,
+) EmailChangeUsecase {
+	return &emailChangeUsecase{
+		userRepo:          userRepo,
+		tokenRepo:         tokenRepo,
+		refreshRepo:       refreshRepo,
+		refreshFamilyRepo: refreshFamilyRepo,
+		passwordHasher:    passwordHasher,
+		tokenGenerator:    tokenGenerator,
+		tokenVerifier:     tokenVerifier,
+		outbox:            outbox,
+		txManager:         txManager,
+		idCodec:           idCodec,
+		policy:            policy,
+	}
+}
+
+// ================= REQUEST CHANGE =================
+
+func (u *emailChangeUsecase) RequestChange(
+	ctx context.Context,
+	userID, newEmail, password string,
+) (*PendingEmailChange, error) {
+
+	id, err := u.idCodec.Decode(userID)
+	if err != nil {
+		return nil, ErrDecode
+	}
+
+	user, err := u.userRepo.GetByID(ctx, id)
+	if err != nil || user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	match, _, err := u.passwordHasher.VerifyPassword(ctx, []byte(password), user.PasswordHash)
+	if errors.Is(err, userPorts.ErrPasswordHasherBusy) {
+		return nil, err
+	}
+	if err != nil || !match {
+		return nil, ErrInvalidPassword
+	}
+
+	newEmail = strings.ToLower(strings.TrimSpace(newEmail))
+	if _, err := valueobjects.NewEmail(newEmail); err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(newEmail, user.Email) {
+		return nil, ErrEmailChangeSameAddress
+	}
+
+	exists, err := u.userRepo.ExistsByEmailExceptID(ctx, newEmail, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrEmailTaken
+	}
+
+	now := time.Now()
+
+	// token + kedua email ditulis satu transaksi (dikirim worker outbox)
+	err = u.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		// hanya satu perubahan pending; token revert lama sengaja dibiarkan
+		// supaya pemilik alamat lama tetap bisa mengembalikan perubahan sebelumnya
+		if err := u.tokenRepo.DeleteByUserAndPurpose(txCtx, user.ID, domain.EmailVerificationPurposeChange); err != nil {
+			return err
+		}
+
+		verifyToken, verifyID, err := u.issueToken(txCtx, user.ID, domain.EmailVerificationPurposeChange, newEmail, now.Add(u.policy.VerifyTTL))
+		if err != nil {
+			return err
+		}
+
+		revertToken, revertID, err := u.issueToken(txCtx, user.ID, domain.EmailVerificationPurposeRevert, user.Email, now.Add(u.policy.RevertTTL))
+		if err != nil {
+			return err
+		}
+
+		err = u.outbox.Enqueue(
+			txCtx,
+			domain.EmailOutboxKindEmailChangeVerification,
+			newEmail,
+			user.Locale,
+			"email_change_verification:"+strconv.FormatUint(verifyID, 10),
+			emailPorts.EmailChangeVerification{
+				VerifyURL: u.policy.VerifyURL + "?token=" + verifyToken,
+				ExpiresAt: now.Add(u.policy.VerifyTTL),
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		return u.outbox.Enqueue(
+			txCtx,
+			domain.EmailOutboxKindEmailChangeNotice,
+			user.Email,
+			user.Locale,
+			"email_change_notice:"+strconv.FormatUint(revertID, 10),
+			emailPorts.EmailChangeNotice{
+				NewEmail:  valueobjects.Email(newEmail).Masked(),
+				RevertURL: u.policy.RevertURL + "?token=" + revertToken,
+				ExpiresAt: now.Add(u.policy.RevertTTL),
+			},
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &PendingEmailChange{
+		NewEmail:  newEmail,
+		ExpiresAt: now.Add(u.policy.VerifyTTL),
+	}, nil
+}
+
+// ================= CONFIRM =================
+
+func (u *emailChangeUsecase) Confirm(
+	ctx context.Context,
+	userID, token string,
+) error {
+
+	id, err := u.idCodec.Decode(userID)
+	if err != nil {
+		return ErrDecode
+	}
+
+	t, err := u.lookupToken(ctx, token, domain.EmailVerificationPurposeChange)
+	if err != nil {
+		return err
+	}
+
+	// link hanya berlaku untuk akun yang memintanya
+	if t.UserID != id {
+		return ErrInvalidEmailChangeToken
+	}
+
+	exists, err := u.userRepo.ExistsByEmailExceptID(ctx, t.Email, id)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrEmailTaken
+	}
+
+	if err := u.userRepo.UpdateEmail(ctx, id, t.Email, true); err != nil {
+		return err
+	}
+
+	return u.tokenRepo.Delete(ctx, t.ID)
+}
+
+// ================= REVERT =================
+
+func (u *emailChangeUsecase) Revert(ctx context.Context, token string) error {
+	t, err := u.lookupToken(ctx, token, domain.EmailVerificationPurposeRevert)
+	if err != nil {
+		return err
+	}
+
+	user, err := u.userRepo.GetByID(ctx, t.UserID)
+	if err != nil || user == nil {
+		return ErrInvalidEmailChangeToken
+	}
+
+	// perubahan pending (belum dikonfirmasi) cukup dibatalkan
+	if err := u.tokenRepo.DeleteByUserAndPurpose(ctx, user.ID, domain.EmailVerificationPurposeChange); err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(user.Email, t.Email) {
+		exists, err := u.userRepo.ExistsByEmailExceptID(ctx, t.Email, user.ID)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrEmailRevertConflict
+		}
+
+		if err := u.userRepo.UpdateEmail(ctx, user.ID, t.Email, true); err != nil {
+			return err
+		}
+
+		// email sempat diganti tanpa sepengetahuan pemilik → anggap akun diambil alih
+		if err := u.revokeSessions(ctx, user.ID); err != nil {
+			return err
+		}
+	}
+
+	return u.tokenRepo.Delete(ctx, t.ID)
+}
+
+// ================= HELPERS =================
+
+func (u *emailChangeUsecase) issueToken(
+	ctx context.Context,
+	userID uint64,
+	purpose, email string,
+	expiresAt time.Time,
+) (string, uint64, error) {
+
+	plain, hash, err := u.tokenGenerator.Generate()
+	if err != nil {
+		return "", 0, err
+	}
+
+	token := &domain.EmailVerificationToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		Email:     email,
+		TokenHash: hash,
+		ExpiresAt: expiresAt,
+	}
+	if err := u.tokenRepo.Create(ctx, token); err != nil {
+		return "", 0, err
+	}
+
+	return plain, token.ID, nil
+}
+
+func (u *emailChangeUsecase) lookupToken(
+	ctx context.Context,
+	token, purpose string,
+) (*domain.EmailVerificationToken, error) {
+
+	if token == "" {
+		return nil, ErrInvalidEmailChangeToken
+	}
+
+	t, err := u.tokenRepo.GetByTokenHash(ctx, u.tokenVerifier.Hash(token))
+	if err != nil || t == nil || t.Purpose != purpose {
+		return nil, ErrInvalidEmailChangeToken
+	}
+
+	if t.IsExpired(time.Now()) {
+		_ = u.tokenRepo.Delete(ctx, t.ID)
+		return nil, ErrInvalidEmailChangeToken
+	}
+
+	return t, nil
+}
+
+func (u *emailChangeUsecase) revokeSessions(ctx context.Context, userID uint64) error {
+	families, err := u.refreshFamilyRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, family := range families {
+		if err := u.refreshRepo.RevokeByFamily(ctx, family.ID); err != nil {
+			return err
+		}
+		if err := u.refreshFamilyRepo.Revoke(ctx, family.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,274 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	emailPorts "github.com/dhanarrizky/Golang-template/internal/ports/email"
+	otherPorts "github.com/dhanarrizky/Golang-template/internal/ports/others"
+	userPorts "github.com/dhanarrizky/Golang-template/internal/ports/users"
+	emailUC "github.com/dhanarrizky/Golang-template/internal/usecase/email"
+	"github.com/dhanarrizky/Golang-template/pkg/email"
+)
+
+var (
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+	ErrVerificationCooldown     = errors.New("verification email was sent recently")
+)
+
+const (
+	EmailVerificationMethodLink = "link"
+	EmailVerificationMethodOTP  = "otp"
+)
+
+// VerificationCooldownError: resend ditolak sampai RetryAfter lewat
+type VerificationCooldownError struct {
+	RetryAfter time.Duration
+}
+
+func (e *VerificationCooldownError) Error() string { return ErrVerificationCooldown.Error() }
+func (e *VerificationCooldownError) Unwrap() error { return ErrVerificationCooldown }
+
+// EmailVerificationPolicy: Method "link" (VerifyURL?token=) atau "otp" (kode 6 digit)
+type EmailVerificationPolicy struct {
+	Method         string
+	VerifyURL      string
+	TokenTTL       time.Duration
+	ResendCooldown time.Duration
+	// MaxAttempts kode salah sebelum kode hangus (metode otp)
+	MaxAttempts int
+}
+
+func DefaultEmailVerificationPolicy() EmailVerificationPolicy {
+	return EmailVerificationPolicy{
+		Method:         EmailVerificationMethodLink,
+		TokenTTL:       24 * time.Hour,
+		ResendCooldown: time.Minute,
+		MaxAttempts:    5,
+	}
+}
+
+type EmailVerificationUsecase interface {
+	// SendVerification kirim link/kode ke email user, token lama diganti
+	SendVerification(ctx context.Context, user *domain.User) error
+
+	// Resend tidak membedakan email tidak terdaftar / sudah terverifikasi / masih
+	// cooldown supaya tidak bisa dipakai untuk enumerasi akun: cooldown diam-diam
+	// tidak mengirim apa pun
+	Resend(ctx context.Context, email string) error
+
+	// Verify dari link (metode link)
+	Verify(ctx context.Context, token string) error
+
+	// VerifyCode dari kode OTP (metode otp)
+	VerifyCode(ctx context.Context, email, code string) error
+
+	IsVerified(ctx context.Context, userID string) (bool, error)
+}
+
+type emailVerificationUsecase struct {
+	userRepo       userPorts.UserRepository
+	tokenRepo      emailPorts.EmailVerificationTokenRepository
+	tokenGenerator otherPorts.TokenGenerator
+	tokenVerifier  otherPorts.TokenVerifier
+	outbox         emailUC.EmailOutboxUsecase
+	txManager      otherPorts.TransactionManager
+	idCodec        otherPorts.PublicIDCodec
+	policy         EmailVerificationPolicy
+}
+
+func NewEmailVerificationUsecase(
+	userRepo userPorts.UserRepository,
+	tokenRepo emailPorts.EmailVerificationTokenRepository,
+	tokenGenerator otherPorts.TokenGenerator,
+	tokenVerifier otherPorts.TokenVerifier,
+	outbox emailUC.EmailOutboxUsecase,
+	txManager otherPorts.TransactionManager,
+	idCodec otherPorts.PublicIDCodec,
+	policy EmailVerificationPolicy,
+) EmailVerificationUsecase {
+	return &emailVerificationUsecase{
+		userRepo:       userRepo,
+		tokenRepo:      tokenRepo,
+		tokenGenerator: tokenGenerator,
+		tokenVerifier:  tokenVerifier,
+		outbox:         outbox,
+		txManager:      txManager,
+		idCodec:        idCodec,
+		policy:         policy,
+	}
+}
+
+// ================= SEND =================
+
+func (u *emailVerificationUsecase) SendVerification(ctx context.Context, user *domain.User) error {
+	if user.EmailVerified {
+		return nil
+	}
+
+	expiresAt := time.Now().Add(u.policy.TokenTTL)
+	notice := emailPorts.EmailVerification{ExpiresAt: expiresAt}
+
+	var hash string
+	if u.policy.Method == EmailVerificationMethodOTP {
+		code, err := email.GenerateOTP()
+		if err != nil {
+			return err
+		}
+		hash = u.codeHash(user.ID, code)
+		notice.Code = code
+	} else {
+		plain, h, err := u.tokenGenerator.Generate()
+		if err != nil {
+			return err
+		}
+		hash = h
+		notice.VerifyURL = u.policy.VerifyURL + "?token=" + plain
+	}
+
+	// token lama diganti + email ditulis satu transaksi (dikirim worker outbox)
+	return u.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := u.tokenRepo.DeleteByUserAndPurpose(txCtx, user.ID, domain.EmailVerificationPurposeSignup); err != nil {
+			return err
+		}
+
+		token := &domain.EmailVerificationToken{
+			UserID:    user.ID,
+			Purpose:   domain.EmailVerificationPurposeSignup,
+			Email:     user.Email,
+			TokenHash: hash,
+			ExpiresAt: expiresAt,
+		}
+		if err := u.tokenRepo.Create(txCtx, token); err != nil {
+			return err
+		}
+
+		return u.outbox.Enqueue(
+			txCtx,
+			domain.EmailOutboxKindEmailVerification,
+			user.Email,
+			user.Locale,
+			"email_verification:"+strconv.FormatUint(token.ID, 10),
+			notice,
+		)
+	})
+}
+
+// ================= RESEND =================
+
+func (u *emailVerificationUsecase) Resend(ctx context.Context, emailAddr string) error {
+	user, err := u.userRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(emailAddr)))
+	if err != nil || user == nil || user.EmailVerified {
+		return nil
+	}
+
+	last, err := u.tokenRepo.GetLatestByUserAndPurpose(ctx, user.ID, domain.EmailVerificationPurposeSignup)
+	if err != nil {
+		return err
+	}
+
+	// error cooldown hanya mungkin untuk akun yang ada → jangan dikembalikan
+	if last != nil && time.Now().Before(last.CreatedAt.Add(u.policy.ResendCooldown)) {
+		return nil
+	}
+
+	return u.SendVerification(ctx, user)
+}
+
+// ================= VERIFY =================
+
+func (u *emailVerificationUsecase) Verify(ctx context.Context, token string) error {
+	if token == "" {
+		return ErrInvalidVerificationToken
+	}
+
+	return u.complete(ctx, u.tokenVerifier.Hash(token), 0)
+}
+
+func (u *emailVerificationUsecase) VerifyCode(ctx context.Context, emailAddr, code string) error {
+	user, err := u.userRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(emailAddr)))
+	if err != nil || user == nil || code == "" {
+		return ErrInvalidVerificationToken
+	}
+
+	t, err := u.tokenRepo.GetLatestByUserAndPurpose(ctx, user.ID, domain.EmailVerificationPurposeSignup)
+	if err != nil || t == nil {
+		return ErrInvalidVerificationToken
+	}
+
+	if !u.tokenVerifier.Compare(t.TokenHash, u.codePlain(user.ID, code)) {
+		// kode 6 digit: batasi tebakan per kode, setelah itu harus minta kode baru
+		attempts, err := u.tokenRepo.IncrementAttempts(ctx, t.ID)
+		if err == nil && attempts >= u.policy.MaxAttempts {
+			_ = u.tokenRepo.Delete(ctx, t.ID)
+		}
+		return ErrInvalidVerificationToken
+	}
+
+	return u.complete(ctx, t.TokenHash, user.ID)
+}
+
+// ================= STATUS =================
+
+func (u *emailVerificationUsecase) IsVerified(ctx context.Context, userID string) (bool, error) {
+	id, err := u.idCodec.Decode(userID)
+	if err != nil {
+		return false, ErrDecode
+	}
+
+	user, err := u.userRepo.GetByID(ctx, id)
+	if err != nil || user == nil {
+		return false, ErrUserNotFound
+	}
+
+	return user.EmailVerified, nil
+}
+
+// ================= HELPERS =================
+
+// complete: userID 0 = tidak dibatasi ke user tertentu (link)
+func (u *emailVerificationUsecase) complete(ctx context.Context, hash string, userID uint64) error {
+	t, err := u.tokenRepo.GetByTokenHash(ctx, hash)
+	if err != nil || t == nil || t.Purpose != domain.EmailVerificationPurposeSignup {
+		return ErrInvalidVerificationToken
+	}
+	if userID != 0 && t.UserID != userID {
+		return ErrInvalidVerificationToken
+	}
+
+	if t.IsExpired(time.Now()) {
+		_ = u.tokenRepo.Delete(ctx, t.ID)
+		return ErrInvalidVerificationToken
+	}
+
+	user, err := u.userRepo.GetByID(ctx, t.UserID)
+	if err != nil || user == nil {
+		return ErrInvalidVerificationToken
+	}
+
+	// token untuk alamat lama tidak berlaku lagi setelah email diganti
+	if !strings.EqualFold(user.Email, t.Email) {
+		_ = u.tokenRepo.Delete(ctx, t.ID)
+		return ErrInvalidVerificationToken
+	}
+
+	user.VerifyEmail()
+	if err := u.userRepo.UpdateEmail(ctx, user.ID, user.Email, user.EmailVerified); err != nil {
+		return err
+	}
+
+	return u.tokenRepo.DeleteByUserAndPurpose(ctx, user.ID, domain.EmailVerificationPurposeSignup)
+}
+
+// codePlain mengikat kode OTP ke user supaya kode yang sama milik user lain tidak bentrok
+func (u *emailVerificationUsecase) codePlain(userID uint64, code string) string {
+	return strconv.FormatUint(userID, 10) + ":" + code
+}
+
+func (u *emailVerificationUsecase) codeHash(userID uint64, code string) string {
+	return u.tokenVerifier.Hash(u.codePlain(userID, code))
+}
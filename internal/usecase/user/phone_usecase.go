@@ -0,0 +1,258 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
+	emailPorts "github.com/dhanarrizky/Golang-template/internal/ports/email"
+	otherPorts "github.com/dhanarrizky/Golang-template/internal/ports/others"
+	userPorts "github.com/dhanarrizky/Golang-template/internal/ports/users"
+	emailUC "github.com/dhanarrizky/Golang-template/internal/usecase/email"
+	"github.com/dhanarrizky/Golang-template/pkg/email"
+)
+
+var (
+	ErrInvalidPhone         = errors.New("invalid phone number, use international format e.g. +6281234567890")
+	ErrNoPhone              = errors.New("no phone number on this account")
+	ErrPhoneNotVerified     = errors.New("phone number has not been verified")
+	ErrInvalidPhoneCode     = errors.New("invalid or expired verification code")
+	ErrChannelNotAvailable  = errors.New("notification channel is not available")
+	ErrPhoneAlreadyVerified = errors.New("phone number is already verified")
+)
+
+// PhonePolicy: Channels = channel non-email yang dikonfigurasi (sms / whatsapp)
+type PhonePolicy struct {
+	DefaultCountryCode string // untuk nomor lokal "08..." → "+62 8..."
+	CodeTTL            time.Duration
+	ResendCooldown     time.Duration
+	Channels           []string
+}
+
+func DefaultPhonePolicy() PhonePolicy {
+	return PhonePolicy{
+		CodeTTL:        10 * time.Minute,
+		ResendCooldown: time.Minute,
+	}
+}
+
+// PhoneVerificationPending: nomor tersimpan, kode dikirim ke Phone lewat Channel
+type PhoneVerificationPending struct {
+	Phone     string
+	Channel   string
+	ExpiresAt time.Time
+}
+
+type PhoneUsecase interface {
+	// SetPhone simpan nomor (belum terverifikasi) lalu kirim kode lewat channel (sms / whatsapp).
+	// Nomor yang sama dan belum terverifikasi = kirim ulang kode (dengan cooldown)
+	SetPhone(ctx context.Context, userID, phone, channel string) (*PhoneVerificationPending, error)
+	VerifyPhone(ctx context.Context, userID, code string) error
+	RemovePhone(ctx context.Context, userID string) error
+
+	// SetPreferredChannel untuk OTP, MFA dan alert keamanan; sms / whatsapp butuh nomor terverifikasi
+	SetPreferredChannel(ctx context.Context, userID, channel string) error
+}
+
+type phoneUsecase struct {
+	userRepo      userPorts.UserRepository
+	tokenRepo     emailPorts.EmailVerificationTokenRepository
+	tokenVerifier otherPorts.TokenVerifier
+	notifier      emailUC.NotificationUsecase
+	idCodec       otherPorts.PublicIDCodec
+	policy        PhonePolicy
+}
+
+func NewPhoneUsecase(
+	userRepo userPorts.UserRepository,
+	tokenRepo emailPorts.EmailVerificationTokenRepository,
+	tokenVerifier otherPorts.TokenVerifier,
+	notifier emailUC.NotificationUsecase,
+	idCodec otherPorts.PublicIDCodec,
+	policy PhonePolicy,
+) PhoneUsecase {
+	return &phoneUsecase{
+		userRepo:      userRepo,
+		tokenRepo:     tokenRepo,
+		tokenVerifier: tokenVerifier,
+		notifier:      notifier,
+		idCodec:       idCodec,
+		policy:        policy,
+	}
+}
+
+// ================= PHONE =================
+
+func (u *phoneUsecase) SetPhone(
+	ctx context.Context,
+	userID, phone, channel string,
+) (*PhoneVerificationPending, error) {
+
+	if !u.channelAvailable(channel) {
+		return nil, ErrChannelNotAvailable
+	}
+
+	number, err := valueobjects.NewPhoneNumber(phone, u.policy.DefaultCountryCode)
+	if err != nil {
+		return nil, ErrInvalidPhone
+	}
+
+	user, err := u.find(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	samePhone := user.Phone != nil && *user.Phone == number.String()
+	if samePhone && user.PhoneVerified {
+		return nil, ErrPhoneAlreadyVerified
+	}
+
+	if samePhone {
+		last, err := u.tokenRepo.GetLatestByUserAndPurpose(ctx, user.ID, domain.EmailVerificationPurposePhone)
+		if err != nil {
+			return nil, err
+		}
+		if last != nil {
+			if wait := time.Until(last.CreatedAt.Add(u.policy.ResendCooldown)); wait > 0 {
+				return nil, &VerificationCooldownError{RetryAfter: wait}
+			}
+		}
+	} else {
+		value := number.String()
+		user.SetPhone(&value)
+		if err := u.userRepo.UpdatePhone(ctx, user.ID, user.Phone, user.PhoneVerified, user.PreferredChannel); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.tokenRepo.DeleteByUserAndPurpose(ctx, user.ID, domain.EmailVerificationPurposePhone); err != nil {
+		return nil, err
+	}
+
+	code, err := email.GenerateOTP()
+	if err != nil {
+		return nil, err
+	}
+
+	token := &domain.EmailVerificationToken{
+		UserID:    user.ID,
+		Purpose:   domain.EmailVerificationPurposePhone,
+		Email:     number.String(),
+		TokenHash: u.codeHash(user.ID, code),
+		ExpiresAt: time.Now().Add(u.policy.CodeTTL),
+	}
+	if err := u.tokenRepo.Create(ctx, token); err != nil {
+		return nil, err
+	}
+
+	key := "verify_phone:" + strconv.FormatUint(token.ID, 10)
+	if err := u.notifier.SendPhoneCode(ctx, channel, number.String(), code, key); err != nil {
+		return nil, err
+	}
+
+	return &PhoneVerificationPending{
+		Phone:     number.Masked(),
+		Channel:   channel,
+		ExpiresAt: token.ExpiresAt,
+	}, nil
+}
+
+func (u *phoneUsecase) VerifyPhone(ctx context.Context, userID, code string) error {
+	user, err := u.find(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Phone == nil {
+		return ErrNoPhone
+	}
+	if code == "" {
+		return ErrInvalidPhoneCode
+	}
+
+	t, err := u.tokenRepo.GetByTokenHash(ctx, u.codeHash(user.ID, code))
+	if err != nil || t == nil || t.Purpose != domain.EmailVerificationPurposePhone || t.UserID != user.ID {
+		return ErrInvalidPhoneCode
+	}
+
+	// kode untuk nomor sebelumnya tidak berlaku setelah nomor diganti
+	if t.IsExpired(time.Now()) || t.Email != *user.Phone {
+		_ = u.tokenRepo.Delete(ctx, t.ID)
+		return ErrInvalidPhoneCode
+	}
+
+	user.VerifyPhone()
+	if err := u.userRepo.UpdatePhone(ctx, user.ID, user.Phone, user.PhoneVerified, user.PreferredChannel); err != nil {
+		return err
+	}
+
+	return u.tokenRepo.DeleteByUserAndPurpose(ctx, user.ID, domain.EmailVerificationPurposePhone)
+}
+
+func (u *phoneUsecase) RemovePhone(ctx context.Context, userID string) error {
+	user, err := u.find(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Phone == nil {
+		return ErrNoPhone
+	}
+
+	user.SetPhone(nil)
+	if err := u.userRepo.UpdatePhone(ctx, user.ID, nil, false, user.PreferredChannel); err != nil {
+		return err
+	}
+
+	return u.tokenRepo.DeleteByUserAndPurpose(ctx, user.ID, domain.EmailVerificationPurposePhone)
+}
+
+// ================= CHANNEL =================
+
+func (u *phoneUsecase) SetPreferredChannel(ctx context.Context, userID, channel string) error {
+	if channel != valueobjects.ChannelEmail && !u.channelAvailable(channel) {
+		return ErrChannelNotAvailable
+	}
+
+	user, err := u.find(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if channel != valueobjects.ChannelEmail && (user.Phone == nil || !user.PhoneVerified) {
+		return ErrPhoneNotVerified
+	}
+
+	return u.userRepo.UpdatePreferredChannel(ctx, user.ID, channel)
+}
+
+// ================= HELPERS =================
+
+func (u *phoneUsecase) find(ctx context.Context, userID string) (*domain.User, error) {
+	id, err := u.idCodec.Decode(userID)
+	if err != nil {
+		return nil, ErrDecode
+	}
+
+	user, err := u.userRepo.GetByID(ctx, id)
+	if err != nil || user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+func (u *phoneUsecase) channelAvailable(channel string) bool {
+	for _, c := range u.policy.Channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// codeHash: prefix "phone" supaya tidak bentrok dengan kode verifikasi email user yang sama
+func (u *phoneUsecase) codeHash(userID uint64, code string) string {
+	return u.tokenVerifier.Hash("phone:" + strconv.FormatUint(userID, 10) + ":" + code)
+}
@@ -3,12 +3,15 @@ package user
 import (
 	"context"
 	"errors"
+	"log"
 
 	"github.com/dhanarrizky/Golang-template/internal/delivery/http/dto"
 	domain "github.com/dhanarrizky/Golang-template/internal/domain/entities/auth"
+	"github.com/dhanarrizky/Golang-template/internal/domain/valueobjects"
 	authPorts "github.com/dhanarrizky/Golang-template/internal/ports/auth"
 	otherPorts "github.com/dhanarrizky/Golang-template/internal/ports/others"
 	userPorts "github.com/dhanarrizky/Golang-template/internal/ports/users"
+	passwordUC "github.com/dhanarrizky/Golang-template/internal/usecase/password"
 )
 
 var (
@@ -30,7 +33,8 @@ var (
 // }
 
 type UserUsecase interface {
-	Register(ctx context.Context, username, email, password string) (*dto.CreateUserResponse, error)
+	// locale dari Accept-Language, kosong = EMAIL_LOCALE
+	Register(ctx context.Context, username, email, password, locale string) (*dto.CreateUserResponse, error)
 	GetMe(ctx context.Context, userID string) (*dto.UserProfileResponse, error)
 	GetUserByID(ctx context.Context, userID string) (*dto.UserResponse, error)
 
@@ -38,7 +42,8 @@ type UserUsecase interface {
 	GetList(ctx context.Context) ([]dto.UserResponse, error)
 
 	UpdateProfile(ctx context.Context, userID, username string) error
-	UpdateUser(ctx context.Context, userID, username, email string) error
+	// UpdateUser tidak mengubah email: ganti email hanya lewat EmailChangeUsecase (verifikasi alamat baru)
+	UpdateUser(ctx context.Context, userID, username string) error
 	SoftDelete(ctx context.Context, userID string) error
 	PermanentDelete(ctx context.Context, userID string) error
 }
@@ -47,32 +52,45 @@ type userUsecase struct {
 	userRepo          userPorts.UserRepository
 	sessionRepo       userPorts.UserSessionRepository
 	passwordHasher    userPorts.PasswordHasher
+	passwordPolicy    passwordUC.PasswordPolicyUsecase
 	idCodec           otherPorts.PublicIDCodec
 	refreshRepo       authPorts.RefreshTokenRepository
 	refreshFamilyRepo authPorts.RefreshTokenFamilyRepository
+	emailVerification EmailVerificationUsecase
 }
 
 func NewUserUsecase(
 	userRepo userPorts.UserRepository,
 	sessionRepo userPorts.UserSessionRepository,
 	passwordHasher userPorts.PasswordHasher,
+	passwordPolicy passwordUC.PasswordPolicyUsecase,
 	idCodec otherPorts.PublicIDCodec,
 	refreshRepo authPorts.RefreshTokenRepository,
 	refreshFamilyRepo authPorts.RefreshTokenFamilyRepository,
+	emailVerification EmailVerificationUsecase,
 ) UserUsecase {
 	return &userUsecase{
-		userRepo:    userRepo,
-		sessionRepo: sessionRepo,
-		refreshRepo: refreshRepo,
+		userRepo:          userRepo,
+		sessionRepo:       sessionRepo,
+		passwordHasher:    passwordHasher,
+		passwordPolicy:    passwordPolicy,
+		idCodec:           idCodec,
+		refreshRepo:       refreshRepo,
+		refreshFamilyRepo: refreshFamilyRepo,
+		emailVerification: emailVerification,
 	}
 }
 
 // ================= REGISTER =================
-func (u *userUsecase) Register(ctx context.Context, username, email, password string) (*dto.CreateUserResponse, error) {
+func (u *userUsecase) Register(ctx context.Context, username, email, password, locale string) (*dto.CreateUserResponse, error) {
 	if username == "" || email == "" || password == "" {
 		return nil, errors.New("username, email, and password are required")
 	}
 
+	if err := u.passwordPolicy.Validate(ctx, password, valueobjects.PasswordUserInfo{Username: username, Email: email}); err != nil {
+		return nil, err
+	}
+
 	// Check uniqueness
 	if exists, _ := u.userRepo.GetByEmailOrUsername(ctx, username); exists != nil {
 		return nil, ErrUsernameTaken
@@ -82,7 +100,7 @@ func (u *userUsecase) Register(ctx context.Context, username, email, password st
 	}
 
 	// Hash password using bcrypt (bukan dari PasswordHasher port)
-	hashedPassword, err := u.passwordHasher.HashPassword([]byte(password))
+	hashedPassword, err := u.passwordHasher.HashPassword(ctx, []byte(password))
 	if err != nil {
 		return nil, err
 	}
@@ -91,12 +109,18 @@ func (u *userUsecase) Register(ctx context.Context, username, email, password st
 		Username:     username,
 		Email:        email,
 		PasswordHash: hashedPassword, // asumsikan field di domain adalah Password atau HashedPassword
+		Locale:       locale,
 	}
 
 	if err := u.userRepo.Create(ctx, user); err != nil {
 		return nil, err
 	}
 
+	// gagal kirim tidak membatalkan registrasi, user bisa minta kirim ulang
+	if err := u.emailVerification.SendVerification(ctx, user); err != nil {
+		log.Printf("[REGISTER] failed to send email verification for user %d: %v", user.ID, err)
+	}
+
 	// Kosongkan password sebelum return
 	encrypId, err := u.idCodec.Encode(user.ID)
 	if err != nil {
@@ -135,6 +159,12 @@ func (u *userUsecase) GetMe(ctx context.Context, userID string) (*dto.UserProfil
 		Username:      user.Username,
 		EmailVerified: user.EmailVerified,
 		CreatedAt:     user.CreatedAt,
+
+		PhoneVerified:       user.PhoneVerified,
+		NotificationChannel: user.NotificationChannel(),
+	}
+	if user.Phone != nil {
+		result.Phone = valueobjects.PhoneNumber(*user.Phone).Masked()
 	}
 
 	return &result, nil
@@ -213,8 +243,8 @@ func (u *userUsecase) UpdateProfile(ctx context.Context, userID, username string
 }
 
 // ================= UPDATE USER (admin/full) =================
-func (u *userUsecase) UpdateUser(ctx context.Context, userID, username, email string) error {
-	if username == "" && email == "" {
+func (u *userUsecase) UpdateUser(ctx context.Context, userID, username string) error {
+	if username == "" {
 		return nil
 	}
 
@@ -228,19 +258,14 @@ func (u *userUsecase) UpdateUser(ctx context.Context, userID, username, email st
 		return ErrUserNotFound
 	}
 
-	if username != "" && username != user.Username {
-		if exists, _ := u.userRepo.ExistsByUsernameExceptID(ctx, username, id); exists {
-			return ErrUsernameTaken
-		}
-		user.Username = username
+	if username == user.Username {
+		return nil
 	}
 
-	if email != "" && email != user.Email {
-		if exists, _ := u.userRepo.ExistsByEmailExceptID(ctx, email, id); exists {
-			return ErrEmailTaken
-		}
-		user.Email = email
+	if exists, _ := u.userRepo.ExistsByUsernameExceptID(ctx, username, id); exists {
+		return ErrUsernameTaken
 	}
+	user.Username = username
 
 	return u.userRepo.Update(ctx, user)
 }
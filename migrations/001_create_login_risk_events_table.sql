@@ -0,0 +1,20 @@
+-- ======================================
+-- TABLE: login_risk_events (audit risk-based login)
+-- ======================================
+CREATE TABLE login_risk_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    ip_address VARCHAR(60),
+    user_agent TEXT,
+    country VARCHAR(2),
+    city VARCHAR(255),
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    score INT NOT NULL,
+    decision VARCHAR(16) NOT NULL,
+    reasons TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_lre_user_created ON login_risk_events (user_id, created_at);
+CREATE INDEX idx_lre_decision ON login_risk_events (decision);
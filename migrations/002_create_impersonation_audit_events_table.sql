@@ -0,0 +1,19 @@
+-- ======================================
+-- TABLE: impersonation_audit_events (audit admin "login as" user)
+-- ======================================
+CREATE TABLE impersonation_audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id BIGINT NOT NULL REFERENCES users(id),
+    subject_id BIGINT NOT NULL REFERENCES users(id),
+    action VARCHAR(16) NOT NULL,
+    reason VARCHAR(255),
+    method VARCHAR(10),
+    path TEXT,
+    status_code INT,
+    ip_address VARCHAR(60),
+    user_agent TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_iae_subject_created ON impersonation_audit_events (subject_id, created_at);
+CREATE INDEX idx_impersonation_audit_events_actor_id ON impersonation_audit_events (actor_id);
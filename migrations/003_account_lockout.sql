@@ -0,0 +1,25 @@
+-- ======================================
+-- USERS: progressive lockout
+-- ======================================
+ALTER TABLE users
+    ADD COLUMN locked_until TIMESTAMPTZ,
+    ADD COLUMN lock_reason VARCHAR(255),
+    ADD COLUMN unlock_token_hash VARCHAR(255);
+
+CREATE INDEX idx_users_unlock_token_hash ON users (unlock_token_hash);
+
+-- ======================================
+-- TABLE: account_lock_events (audit lock/unlock)
+-- ======================================
+CREATE TABLE account_lock_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    actor_id BIGINT REFERENCES users(id),
+    action VARCHAR(16) NOT NULL,
+    reason VARCHAR(255),
+    locked_until TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_ale_user_created ON account_lock_events (user_id, created_at);
+CREATE INDEX idx_account_lock_events_actor_id ON account_lock_events (actor_id);
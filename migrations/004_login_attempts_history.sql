@@ -0,0 +1,13 @@
+-- ======================================
+-- LOGIN_ATTEMPTS: full history (sukses + gagal, tidak pernah dihapus)
+-- ======================================
+ALTER TABLE login_attempts
+    ADD COLUMN IF NOT EXISTS identifier VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS user_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS failure_reason VARCHAR(64);
+
+UPDATE login_attempts SET identifier = email WHERE identifier IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_la_identifier_created ON login_attempts (identifier, created_at);
+CREATE INDEX IF NOT EXISTS idx_la_user_created ON login_attempts (user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_la_ip_created ON login_attempts (ip_address, created_at);
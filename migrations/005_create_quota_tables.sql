@@ -0,0 +1,45 @@
+-- ======================================
+-- TABLE: quota_plans (satu plan per role, 0 = tanpa batas)
+-- ======================================
+CREATE TABLE quota_plans (
+    id BIGSERIAL PRIMARY KEY,
+    role_id BIGINT NOT NULL UNIQUE REFERENCES roles(id) ON DELETE CASCADE,
+    name VARCHAR(64) NOT NULL,
+    daily_limit BIGINT NOT NULL DEFAULT 0,
+    monthly_limit BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- ======================================
+-- TABLE: quota_overrides (override admin per subject)
+-- ======================================
+CREATE TABLE quota_overrides (
+    id BIGSERIAL PRIMARY KEY,
+    subject_type VARCHAR(32) NOT NULL,
+    subject_id VARCHAR(64) NOT NULL,
+    daily_limit BIGINT,
+    monthly_limit BIGINT,
+    reason VARCHAR(255),
+    created_by BIGINT NOT NULL REFERENCES users(id),
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_quota_override_subject ON quota_overrides (subject_type, subject_id);
+
+-- ======================================
+-- TABLE: quota_usage (flush berkala dari counter Redis)
+-- ======================================
+CREATE TABLE quota_usage (
+    id BIGSERIAL PRIMARY KEY,
+    subject_type VARCHAR(32) NOT NULL,
+    subject_id VARCHAR(64) NOT NULL,
+    period VARCHAR(16) NOT NULL,
+    period_start TIMESTAMPTZ NOT NULL,
+    count BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_quota_usage_subject_period ON quota_usage (subject_type, subject_id, period, period_start);
@@ -0,0 +1,9 @@
+-- ======================================
+-- EMAIL_VERIFICATION_TOKENS: tujuan token + alamat yang diverifikasi
+-- (signup, change_email → alamat baru, revert_email → alamat lama)
+-- ======================================
+ALTER TABLE email_verification_tokens
+    ADD COLUMN purpose VARCHAR(32) NOT NULL DEFAULT 'signup',
+    ADD COLUMN email VARCHAR(255);
+
+CREATE INDEX idx_evt_user_purpose ON email_verification_tokens (user_id, purpose);
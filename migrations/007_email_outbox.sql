@@ -0,0 +1,20 @@
+-- ======================================
+-- TABLE: email_outbox (ditulis satu transaksi dengan perubahan bisnis,
+-- dikirim worker dengan retry + backoff, 'dead' setelah batas percobaan)
+-- ======================================
+CREATE TABLE email_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    idempotency_key VARCHAR(255) NOT NULL UNIQUE,
+    kind VARCHAR(64) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_email_outbox_due ON email_outbox (status, next_attempt_at);
@@ -0,0 +1,12 @@
+-- ======================================
+-- USERS: nomor telepon (E.164) + channel OTP / MFA / alert keamanan
+-- ======================================
+ALTER TABLE users
+    ADD COLUMN phone VARCHAR(20),
+    ADD COLUMN phone_verified BOOLEAN DEFAULT FALSE,
+    ADD COLUMN preferred_channel VARCHAR(16) NOT NULL DEFAULT 'email';
+
+CREATE INDEX idx_users_phone ON users (phone);
+
+-- kode verifikasi nomor memakai email_verification_tokens
+-- (purpose = 'verify_phone', kolom email berisi nomor yang diverifikasi)
@@ -0,0 +1,16 @@
+-- ======================================
+-- EMAIL_OTPS: tujuan OTP (signup, login, password_reset, email_change)
+-- + jumlah percobaan kode salah
+-- ======================================
+ALTER TABLE email_otps
+    ALTER COLUMN purpose TYPE VARCHAR(32),
+    ALTER COLUMN purpose SET DEFAULT 'signup';
+
+UPDATE email_otps SET purpose = 'signup' WHERE purpose IS NULL OR purpose = '';
+
+ALTER TABLE email_otps
+    ALTER COLUMN purpose SET NOT NULL,
+    ADD COLUMN attempts INT NOT NULL DEFAULT 0;
+
+DROP INDEX IF EXISTS idx_email_otps_email;
+CREATE INDEX idx_email_otp_email_purpose ON email_otps (email, purpose);
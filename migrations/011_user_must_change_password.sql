@@ -0,0 +1,6 @@
+-- ======================================
+-- USERS: paksa ganti password
+-- (password ditemukan di data breach saat login)
+-- ======================================
+ALTER TABLE users
+    ADD COLUMN must_change_password BOOLEAN NOT NULL DEFAULT FALSE;
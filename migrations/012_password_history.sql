@@ -0,0 +1,11 @@
+-- ======================================
+-- TABLE: password_histories (hash password lama, cegah pakai ulang)
+-- ======================================
+CREATE TABLE IF NOT EXISTS password_histories (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    password_hash TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_password_histories_user_created ON password_histories (user_id, created_at DESC);
@@ -0,0 +1,10 @@
+-- ======================================
+-- USERS: umur password (PASSWORD_MAX_AGE_DAYS) & peringatan expiry
+-- ======================================
+-- user lama dihitung dari waktu migrasi, supaya tidak langsung expired
+-- begitu PASSWORD_MAX_AGE_DAYS diaktifkan
+ALTER TABLE users
+    ADD COLUMN password_changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN password_expiry_warned_at TIMESTAMPTZ;
+
+CREATE INDEX idx_users_password_changed_at ON users (password_changed_at);
@@ -0,0 +1,9 @@
+-- ======================================
+-- LOGIN RISK EVENTS: hanya login yang selesai jadi riwayat lokasi terpercaya
+-- ======================================
+-- step-up yang tidak pernah lolos tidak boleh membuat negara / lokasi penyerang "dikenal"
+ALTER TABLE login_risk_events
+    ADD COLUMN completed BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- event lama: hanya "allow" yang pasti selesai tanpa kode tambahan
+UPDATE login_risk_events SET completed = TRUE WHERE decision = 'allow';
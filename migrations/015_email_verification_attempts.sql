@@ -0,0 +1,6 @@
+-- ======================================
+-- EMAIL VERIFICATION TOKENS: jumlah percobaan kode salah
+-- ======================================
+-- kode 6 digit (metode otp) hangus setelah EMAIL_VERIFICATION_MAX_ATTEMPTS kali salah
+ALTER TABLE email_verification_tokens
+    ADD COLUMN attempts INT NOT NULL DEFAULT 0;
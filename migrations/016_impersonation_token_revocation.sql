@@ -0,0 +1,8 @@
+-- ======================================
+-- IMPERSONATION AUDIT EVENTS: jti token impersonation
+-- ======================================
+-- event stop dengan token_id mencabut token tersebut sebelum exp
+ALTER TABLE impersonation_audit_events
+    ADD COLUMN token_id VARCHAR(64);
+
+CREATE INDEX idx_impersonation_audit_events_token_id ON impersonation_audit_events (token_id);
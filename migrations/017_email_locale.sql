@@ -0,0 +1,10 @@
+-- ======================================
+-- EMAIL LOCALE: bahasa template email per user
+-- ======================================
+-- diisi dari Accept-Language saat registrasi, NULL / kosong = EMAIL_LOCALE
+ALTER TABLE users
+    ADD COLUMN locale VARCHAR(16);
+
+-- locale penerima ikut disimpan supaya retry outbox memakai bahasa yang sama
+ALTER TABLE email_outbox
+    ADD COLUMN locale VARCHAR(16);
@@ -0,0 +1,482 @@
+package email
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DKIM canonicalization algorithms (RFC 6376 section 3.4)
+const (
+	DKIMCanonicalizationSimple  = "simple"
+	DKIMCanonicalizationRelaxed = "relaxed"
+)
+
+var (
+	ErrDKIMSignatureMissing  = errors.New("dkim: message has no DKIM-Signature header")
+	ErrDKIMBodyHashMismatch  = errors.New("dkim: body hash does not match")
+	ErrDKIMSignatureMismatch = errors.New("dkim: signature verification failed")
+)
+
+// DefaultDKIMHeaders are signed when DKIMOptions.Headers is empty. These are
+// the headers BuildMIME writes; a header listed but missing is still signed
+// (as absent) so it cannot be added in transit.
+var DefaultDKIMHeaders = []string{
+	"From", "To", "Subject", "Date", "Message-ID", "MIME-Version", "Content-Type",
+}
+
+type DKIMOptions struct {
+	Domain   string
+	Selector string
+	// Signer is an *rsa.PrivateKey (rsa-sha256) or ed25519.PrivateKey (ed25519-sha256)
+	Signer crypto.Signer
+
+	HeaderCanonicalization string // simple | relaxed (default relaxed)
+	BodyCanonicalization   string // simple | relaxed (default relaxed)
+	Headers                []string
+}
+
+// DKIMSigner adds a DKIM-Signature header to raw MIME messages
+type DKIMSigner struct {
+	opts      DKIMOptions
+	algorithm string
+}
+
+func NewDKIMSigner(opts DKIMOptions) (*DKIMSigner, error) {
+	if opts.Domain == "" || opts.Selector == "" {
+		return nil, errors.New("dkim: domain and selector are required")
+	}
+
+	algorithm, err := dkimAlgorithm(opts.Signer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range []*string{&opts.HeaderCanonicalization, &opts.BodyCanonicalization} {
+		switch *c {
+		case "":
+			*c = DKIMCanonicalizationRelaxed
+		case DKIMCanonicalizationSimple, DKIMCanonicalizationRelaxed:
+		default:
+			return nil, fmt.Errorf("dkim: unknown canonicalization %q", *c)
+		}
+	}
+
+	if len(opts.Headers) == 0 {
+		opts.Headers = DefaultDKIMHeaders
+	}
+
+	return &DKIMSigner{opts: opts, algorithm: algorithm}, nil
+}
+
+// DKIMSettings are the DKIM values as they come from configuration
+type DKIMSettings struct {
+	Domain         string
+	Selector       string
+	PrivateKey     string // PEM, used when PrivateKeyPath is empty
+	PrivateKeyPath string // PEM file
+	// Canonicalization is "header/body" (e.g. relaxed/simple); a single
+	// value applies to both
+	Canonicalization string
+	Headers          string // comma separated, empty = DefaultDKIMHeaders
+}
+
+// NewDKIMSignerFromSettings loads the private key and builds a signer
+func NewDKIMSignerFromSettings(s DKIMSettings) (*DKIMSigner, error) {
+	pemKey := []byte(s.PrivateKey)
+	if s.PrivateKeyPath != "" {
+		data, err := os.ReadFile(s.PrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("dkim: read private key: %w", err)
+		}
+		pemKey = data
+	}
+
+	key, err := ParseDKIMPrivateKey(pemKey)
+	if err != nil {
+		return nil, err
+	}
+
+	headerCanon, bodyCanon, _ := strings.Cut(s.Canonicalization, "/")
+	if bodyCanon == "" {
+		bodyCanon = headerCanon
+	}
+
+	var headers []string
+	for _, h := range strings.Split(s.Headers, ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			headers = append(headers, h)
+		}
+	}
+
+	return NewDKIMSigner(DKIMOptions{
+		Domain:                 s.Domain,
+		Selector:               s.Selector,
+		Signer:                 key,
+		HeaderCanonicalization: headerCanon,
+		BodyCanonicalization:   bodyCanon,
+		Headers:                headers,
+	})
+}
+
+// Sign returns raw with a DKIM-Signature header prepended
+func (s *DKIMSigner) Sign(raw []byte) ([]byte, error) {
+	raw = normalizeCRLF(raw)
+
+	headers, body, err := splitMessage(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	bodyHash := sha256.Sum256(canonicalBody(body, s.opts.BodyCanonicalization))
+
+	names := make([]string, len(s.opts.Headers))
+	for i, h := range s.opts.Headers {
+		names[i] = strings.ToLower(h)
+	}
+
+	// b= is last and empty: the same text is hashed here and by the verifier
+	// after it strips the signature value
+	field := fmt.Sprintf(
+		"DKIM-Signature: v=1; a=%s; c=%s/%s; d=%s; s=%s; t=%d;\r\n\th=%s;\r\n\tbh=%s;\r\n\tb=",
+		s.algorithm,
+		s.opts.HeaderCanonicalization,
+		s.opts.BodyCanonicalization,
+		s.opts.Domain,
+		s.opts.Selector,
+		time.Now().Unix(),
+		strings.Join(names, ":"),
+		base64.StdEncoding.EncodeToString(bodyHash[:]),
+	)
+
+	digest := headerHash(headers, names, field, s.opts.HeaderCanonicalization)
+
+	var opts crypto.SignerOpts = crypto.SHA256
+	if s.algorithm == "ed25519-sha256" {
+		// RFC 8463: Ed25519 signs the SHA-256 digest as its message
+		opts = crypto.Hash(0)
+	}
+
+	sig, err := s.opts.Signer.Sign(rand.Reader, digest, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.WriteString(field)
+	out.WriteString(base64.StdEncoding.EncodeToString(sig))
+	out.WriteString("\r\n")
+	out.Write(raw)
+
+	return out.Bytes(), nil
+}
+
+// PublicKey returns the public half of the signing key
+func (s *DKIMSigner) PublicKey() crypto.PublicKey {
+	return s.opts.Signer.Public()
+}
+
+// DNSRecord is the TXT record to publish at <selector>._domainkey.<domain>
+func (s *DKIMSigner) DNSRecord() (name, value string, err error) {
+	value, err = DKIMRecord(s.opts.Signer.Public())
+	return s.opts.Selector + "._domainkey." + s.opts.Domain, value, err
+}
+
+// VerifyDKIM checks the first DKIM-Signature of raw against pub. It does not
+// look the key up in DNS; the caller supplies it (see ParseDKIMPublicKey).
+func VerifyDKIM(raw []byte, pub crypto.PublicKey) error {
+	raw = normalizeCRLF(raw)
+
+	headers, body, err := splitMessage(raw)
+	if err != nil {
+		return err
+	}
+
+	var sigField string
+	for _, h := range headers {
+		if strings.EqualFold(headerName(h), "DKIM-Signature") {
+			sigField = h
+			break
+		}
+	}
+	if sigField == "" {
+		return ErrDKIMSignatureMissing
+	}
+
+	tags := parseDKIMTags(sigField[strings.Index(sigField, ":")+1:])
+
+	headerCanon, bodyCanon := DKIMCanonicalizationSimple, DKIMCanonicalizationSimple
+	if c := tags["c"]; c != "" {
+		parts := strings.SplitN(c, "/", 2)
+		headerCanon = parts[0]
+		if len(parts) == 2 {
+			bodyCanon = parts[1]
+		}
+	}
+
+	bodyHash := sha256.Sum256(canonicalBody(body, bodyCanon))
+	if base64.StdEncoding.EncodeToString(bodyHash[:]) != tags["bh"] {
+		return ErrDKIMBodyHashMismatch
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(tags["b"])
+	if err != nil {
+		return fmt.Errorf("dkim: invalid b= tag: %w", err)
+	}
+
+	// the signature header takes part in its own hash with b= emptied;
+	// it is not one of the h= headers, so drop it from the candidates
+	var others []string
+	for _, h := range headers {
+		if h != sigField {
+			others = append(others, h)
+		}
+	}
+
+	names := strings.Split(strings.ToLower(tags["h"]), ":")
+	digest := headerHash(others, names, stripSignatureValue(sigField), headerCanon)
+
+	switch tags["a"] {
+	case "rsa-sha256":
+		key, ok := pub.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("dkim: a=rsa-sha256 but the public key is not RSA")
+		}
+		if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, sig) != nil {
+			return ErrDKIMSignatureMismatch
+		}
+	case "ed25519-sha256":
+		key, ok := pub.(ed25519.PublicKey)
+		if !ok {
+			return errors.New("dkim: a=ed25519-sha256 but the public key is not Ed25519")
+		}
+		if !ed25519.Verify(key, digest, sig) {
+			return ErrDKIMSignatureMismatch
+		}
+	default:
+		return fmt.Errorf("dkim: unsupported algorithm %q", tags["a"])
+	}
+
+	return nil
+}
+
+// ================= KEYS =================
+
+// ParseDKIMPrivateKey reads a PEM encoded RSA (PKCS#1 / PKCS#8) or Ed25519 (PKCS#8) key
+func ParseDKIMPrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("dkim: private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("dkim: parse private key: %w", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("dkim: unsupported private key type")
+	}
+	if _, err := dkimAlgorithm(signer); err != nil {
+		return nil, err
+	}
+
+	return signer, nil
+}
+
+// ParseDKIMPublicKey accepts a PEM public key or the DNS TXT record
+// ("v=DKIM1; k=rsa; p=...") as published for the selector
+func ParseDKIMPublicKey(data []byte) (crypto.PublicKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	}
+
+	tags := parseDKIMTags(string(data))
+	raw, err := base64.StdEncoding.DecodeString(tags["p"])
+	if err != nil || len(raw) == 0 {
+		return nil, errors.New("dkim: record has no valid p= tag")
+	}
+
+	if tags["k"] == "ed25519" {
+		if len(raw) != ed25519.PublicKeySize {
+			return nil, errors.New("dkim: invalid ed25519 public key")
+		}
+		return ed25519.PublicKey(raw), nil
+	}
+
+	if key, err := x509.ParsePKIXPublicKey(raw); err == nil {
+		return key, nil
+	}
+	return x509.ParsePKCS1PublicKey(raw)
+}
+
+// DKIMRecord formats pub as a DNS TXT record value
+func DKIMRecord(pub crypto.PublicKey) (string, error) {
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		der, err := x509.MarshalPKIXPublicKey(key)
+		if err != nil {
+			return "", err
+		}
+		return "v=DKIM1; k=rsa; p=" + base64.StdEncoding.EncodeToString(der), nil
+	case ed25519.PublicKey:
+		return "v=DKIM1; k=ed25519; p=" + base64.StdEncoding.EncodeToString(key), nil
+	}
+	return "", errors.New("dkim: unsupported public key type")
+}
+
+func dkimAlgorithm(signer crypto.Signer) (string, error) {
+	switch key := signer.(type) {
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < 1024 {
+			return "", errors.New("dkim: RSA keys must be at least 1024 bits")
+		}
+		return "rsa-sha256", nil
+	case ed25519.PrivateKey:
+		return "ed25519-sha256", nil
+	case nil:
+		return "", errors.New("dkim: private key is required")
+	}
+	return "", fmt.Errorf("dkim: unsupported key type %T", signer)
+}
+
+// ================= CANONICALIZATION =================
+
+var (
+	wspRun      = regexp.MustCompile(`[ \t]+`)
+	signatureB  = regexp.MustCompile(`(b=)[^;]*`)
+	tagSplitter = regexp.MustCompile(`\s*;\s*`)
+)
+
+func normalizeCRLF(raw []byte) []byte {
+	raw = bytes.ReplaceAll(raw, []byte("\r\n"), []byte("\n"))
+	return bytes.ReplaceAll(raw, []byte("\n"), []byte("\r\n"))
+}
+
+// splitMessage returns the header fields (each with its folded continuation
+// lines and trailing CRLF) and the body
+func splitMessage(raw []byte) ([]string, []byte, error) {
+	end := bytes.Index(raw, []byte("\r\n\r\n"))
+	if end < 0 {
+		return nil, nil, errors.New("dkim: message has no header/body separator")
+	}
+
+	var fields []string
+	for _, line := range strings.SplitAfter(string(raw[:end+2]), "\r\n") {
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(fields) > 0 {
+			fields[len(fields)-1] += line
+			continue
+		}
+		fields = append(fields, line)
+	}
+
+	return fields, raw[end+4:], nil
+}
+
+func headerName(field string) string {
+	if i := strings.Index(field, ":"); i >= 0 {
+		return strings.TrimSpace(field[:i])
+	}
+	return field
+}
+
+// headerHash hashes the h= headers (bottom-most unused instance first, per
+// RFC 6376 section 5.4.2) followed by the signature field itself
+func headerHash(fields, names []string, sigField, canon string) []byte {
+	used := make([]bool, len(fields))
+	h := sha256.New()
+
+	for _, name := range names {
+		for i := len(fields) - 1; i >= 0; i-- {
+			if used[i] || !strings.EqualFold(headerName(fields[i]), name) {
+				continue
+			}
+			used[i] = true
+			h.Write([]byte(canonicalHeader(fields[i], canon)))
+			break
+		}
+	}
+
+	h.Write([]byte(strings.TrimSuffix(canonicalHeader(sigField+"\r\n", canon), "\r\n")))
+	return h.Sum(nil)
+}
+
+func canonicalHeader(field, canon string) string {
+	if canon == DKIMCanonicalizationSimple {
+		return field
+	}
+
+	i := strings.Index(field, ":")
+	name := strings.ToLower(strings.TrimSpace(field[:i]))
+	value := strings.NewReplacer("\r\n", "").Replace(field[i+1:])
+	value = strings.TrimSpace(wspRun.ReplaceAllString(value, " "))
+
+	return name + ":" + value + "\r\n"
+}
+
+func canonicalBody(body []byte, canon string) []byte {
+	lines := strings.Split(string(body), "\r\n")
+
+	if canon == DKIMCanonicalizationRelaxed {
+		for i, line := range lines {
+			lines[i] = strings.TrimRight(wspRun.ReplaceAllString(line, " "), " ")
+		}
+	}
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) == 0 {
+		if canon == DKIMCanonicalizationRelaxed {
+			return nil
+		}
+		return []byte("\r\n")
+	}
+
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+// stripSignatureValue empties b= (but not bh=) and drops the trailing CRLF
+func stripSignatureValue(field string) string {
+	tags := strings.Split(strings.TrimSuffix(field, "\r\n"), ";")
+	for i, tag := range tags {
+		if strings.HasPrefix(strings.TrimLeft(tag, " \t\r\n"), "b=") {
+			tags[i] = signatureB.ReplaceAllString(tag, "$1")
+		}
+	}
+	return strings.Join(tags, ";")
+}
+
+func parseDKIMTags(value string) map[string]string {
+	tags := map[string]string{}
+	for _, part := range tagSplitter.Split(strings.TrimSpace(value), -1) {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		// folding whitespace is not part of tag values
+		tags[strings.TrimSpace(k)] = strings.Join(strings.Fields(v), "")
+	}
+	return tags
+}
@@ -0,0 +1,67 @@
+package email
+
+import (
+	"strconv"
+	"strings"
+)
+
+// maxLocaleLength matches the users.locale / email_outbox.locale columns
+const maxLocaleLength = 16
+
+// PreferredLocale returns the language tag with the highest weight in an
+// Accept-Language header ("id-ID,id;q=0.9,en;q=0.8" → "id-ID"), or "" when
+// the header holds no usable tag. The Renderer falls back from the region to
+// the language and then to the default locale, so the tag is not checked
+// against the available templates here.
+func PreferredLocale(header string) string {
+	var (
+		best       string
+		bestWeight float64
+	)
+
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if !validLocale(tag) {
+			continue
+		}
+
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			w, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			weight = w
+		}
+
+		// first tag wins on equal weight, q=0 means "not acceptable"
+		if weight > bestWeight {
+			best, bestWeight = tag, weight
+		}
+	}
+
+	return best
+}
+
+// validLocale accepts tags like "en", "id-ID" or "zh-Hant-TW"; "*" and
+// anything that would not fit the column are rejected
+func validLocale(tag string) bool {
+	if tag == "" || len(tag) > maxLocaleLength {
+		return false
+	}
+
+	for i, sub := range strings.Split(tag, "-") {
+		if sub == "" || len(sub) > 8 || (i == 0 && len(sub) < 2) {
+			return false
+		}
+		for _, r := range sub {
+			isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+			if !isLetter && (i == 0 || r < '0' || r > '9') {
+				return false
+			}
+		}
+	}
+
+	return true
+}
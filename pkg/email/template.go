@@ -0,0 +1,259 @@
+package email
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrTemplateNotFound = errors.New("email template not found")
+
+// Message is a rendered email ready to be wrapped in a MIME envelope
+type Message struct {
+	Subject string
+	HTML    string
+	Text    string
+
+	// Raw, when set, is the complete MIME message (e.g. DKIM signed) and is
+	// sent as-is instead of being built from the fields above
+	Raw []byte
+}
+
+// TemplateData is what every template receives; message specific values live in Data
+type TemplateData struct {
+	AppName string
+	Year    int
+	Locale  string
+	Data    any
+}
+
+// Renderer renders named email templates with per-locale variants.
+//
+// Layout of the template filesystem:
+//
+//	layouts/base.html      defines "layout", wraps {{template "content" .}}
+//	partials/*.html        shared blocks (button, footer, ...)
+//	<locale>/<name>.html   defines "subject", "content" and optionally "text"
+//
+// When a template has no "text" block the plain-text alternative is generated
+// from the rendered HTML.
+type Renderer struct {
+	fsys          fs.FS
+	appName       string
+	defaultLocale string
+
+	mu    sync.RWMutex
+	cache map[string]*template.Template
+}
+
+// NewRenderer uses base (usually an embed.FS). Files in overrideDir, when set,
+// take precedence over base so deployments can rebrand single templates
+// without rebuilding the binary.
+func NewRenderer(base fs.FS, overrideDir, appName, defaultLocale string) *Renderer {
+	fsys := base
+	if overrideDir != "" {
+		fsys = overlayFS{upper: os.DirFS(overrideDir), lower: base}
+	}
+
+	if defaultLocale == "" {
+		defaultLocale = "en"
+	}
+
+	return &Renderer{
+		fsys:          fsys,
+		appName:       appName,
+		defaultLocale: defaultLocale,
+		cache:         map[string]*template.Template{},
+	}
+}
+
+// Render falls back from "id-ID" to "id" and then to the default locale
+func (r *Renderer) Render(name, locale string, data any) (*Message, error) {
+	var (
+		tpl *template.Template
+		err error
+	)
+	for _, candidate := range r.locales(locale) {
+		tpl, err = r.load(name, candidate)
+		if err == nil {
+			locale = candidate
+			break
+		}
+		if !errors.Is(err, ErrTemplateNotFound) {
+			return nil, err
+		}
+	}
+	if tpl == nil {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+
+	td := TemplateData{
+		AppName: r.appName,
+		Year:    time.Now().Year(),
+		Locale:  locale,
+		Data:    data,
+	}
+
+	subject, err := execute(tpl, "subject", td)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := execute(tpl, "layout", td)
+	if err != nil {
+		return nil, err
+	}
+
+	text := HTMLToText(body)
+	if tpl.Lookup("text") != nil {
+		if text, err = execute(tpl, "text", td); err != nil {
+			return nil, err
+		}
+		text = html.UnescapeString(strings.TrimSpace(text)) + "\n"
+	}
+
+	return &Message{
+		// subject dieksekusi dalam konteks HTML → kembalikan entity ke karakter asli
+		Subject: strings.Join(strings.Fields(html.UnescapeString(subject)), " "),
+		HTML:    body,
+		Text:    text,
+	}, nil
+}
+
+func (r *Renderer) locales(locale string) []string {
+	var out []string
+	add := func(l string) {
+		l = strings.ToLower(strings.TrimSpace(l))
+		for _, existing := range out {
+			if existing == l {
+				return
+			}
+		}
+		if l != "" {
+			out = append(out, l)
+		}
+	}
+
+	add(locale)
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		add(locale[:i])
+	}
+	add(r.defaultLocale)
+
+	return out
+}
+
+func (r *Renderer) load(name, locale string) (*template.Template, error) {
+	key := locale + "/" + name
+
+	r.mu.RLock()
+	tpl, ok := r.cache[key]
+	r.mu.RUnlock()
+	if ok {
+		return tpl, nil
+	}
+
+	page := key + ".html"
+	if _, err := fs.Stat(r.fsys, page); err != nil {
+		return nil, ErrTemplateNotFound
+	}
+
+	tpl, err := template.New(name).
+		Funcs(template.FuncMap{"dict": dict, "datetime": datetime}).
+		ParseFS(r.fsys, "layouts/*.html", "partials/*.html", page)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cache[key] = tpl
+	r.mu.Unlock()
+
+	return tpl, nil
+}
+
+func execute(tpl *template.Template, name string, data TemplateData) (string, error) {
+	var buf bytes.Buffer
+	if err := tpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// dict builds a map for passing several values to a partial:
+// {{template "button" dict "URL" .Data.URL "Label" "Verify"}}
+func dict(pairs ...any) (map[string]any, error) {
+	if len(pairs)%2 != 0 {
+		return nil, errors.New("dict requires key/value pairs")
+	}
+
+	m := make(map[string]any, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict key %v is not a string", pairs[i])
+		}
+		m[key] = pairs[i+1]
+	}
+	return m, nil
+}
+
+// datetime formats time.Time / *time.Time in UTC so the email reads the same
+// regardless of the server timezone
+func datetime(v any) string {
+	switch t := v.(type) {
+	case time.Time:
+		return t.UTC().Format("02 Jan 2006 15:04 MST")
+	case *time.Time:
+		if t != nil {
+			return t.UTC().Format("02 Jan 2006 15:04 MST")
+		}
+	}
+	return ""
+}
+
+// overlayFS serves files from upper first, falling back to lower.
+// Directory listings are merged so globs see templates from both.
+type overlayFS struct {
+	upper fs.FS
+	lower fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	if f, err := o.upper.Open(name); err == nil {
+		return f, nil
+	}
+	return o.lower.Open(name)
+}
+
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	upper, upperErr := fs.ReadDir(o.upper, name)
+	lower, lowerErr := fs.ReadDir(o.lower, name)
+	if upperErr != nil && lowerErr != nil {
+		return nil, lowerErr
+	}
+
+	seen := map[string]fs.DirEntry{}
+	for _, e := range lower {
+		seen[e.Name()] = e
+	}
+	for _, e := range upper {
+		seen[e.Name()] = e
+	}
+
+	entries := make([]fs.DirEntry, 0, len(seen))
+	for _, e := range seen {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	return entries, nil
+}
@@ -0,0 +1,48 @@
+package email
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	reHiddenBlock = regexp.MustCompile(`(?is)<(head|style|script)[^>]*>.*?</(head|style|script)>`)
+	reLink        = regexp.MustCompile(`(?is)<a\s[^>]*href="([^"]*)"[^>]*>(.*?)</a>`)
+	reLineBreak   = regexp.MustCompile(`(?i)<br\s*/?>`)
+	reBlockEnd    = regexp.MustCompile(`(?i)</(p|div|h[1-6]|tr|li|table)>|<hr[^>]*>`)
+	reTag         = regexp.MustCompile(`(?s)<[^>]+>`)
+	reSpaces      = regexp.MustCompile(`[ \t\r\f\v]+`)
+	reBlankLines  = regexp.MustCompile(`\n{3,}`)
+)
+
+// HTMLToText produces the text/plain alternative of an HTML email.
+// Links keep their target ("Verify (https://...)") so they stay usable
+// in clients that only show plain text.
+func HTMLToText(body string) string {
+	s := reHiddenBlock.ReplaceAllString(body, "")
+
+	s = reLink.ReplaceAllStringFunc(s, func(a string) string {
+		m := reLink.FindStringSubmatch(a)
+		label := strings.TrimSpace(reTag.ReplaceAllString(m[2], ""))
+		href := html.UnescapeString(m[1])
+		if label == "" || label == href {
+			return href
+		}
+		return label + " (" + href + ")"
+	})
+
+	s = reLineBreak.ReplaceAllString(s, "\n")
+	s = reBlockEnd.ReplaceAllString(s, "\n\n")
+	s = reTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(reSpaces.ReplaceAllString(line, " "))
+	}
+	s = strings.Join(lines, "\n")
+	s = reBlankLines.ReplaceAllString(s, "\n\n")
+
+	return strings.TrimSpace(s) + "\n"
+}
@@ -0,0 +1,8 @@
+// Package template_html berisi template email bawaan (di-embed ke binary).
+// Bisa ditimpa per file lewat EMAIL_TEMPLATE_DIR.
+package template_html
+
+import "embed"
+
+//go:embed layouts partials en id
+var FS embed.FS